@@ -0,0 +1,36 @@
+package chat
+
+import "time"
+
+// Client is one session's seat in the lobby. A client starts outside the
+// lobby; Join takes a nickname and Leave gives it up.
+type Client struct {
+	hub   *Hub
+	id    uint64
+	key   string
+	owner bool
+}
+
+func (c *Client) Join(nick string) error { return c.hub.join(c, nick) }
+
+// Leave is safe to call when not joined, e.g. on disconnect.
+func (c *Client) Leave() { c.hub.leave(c) }
+
+func (c *Client) Say(text string) error { return c.hub.say(c, text) }
+
+// Kick removes nick from the lobby. Only owner clients may kick.
+func (c *Client) Kick(nick string) error { return c.hub.kick(c, nick) }
+
+// Mute silences nick for d, or lifts the mute when d is zero. Only owner
+// clients may mute.
+func (c *Client) Mute(nick string, d time.Duration) error { return c.hub.mute(c, nick, d) }
+
+// Member reports the client's nickname, and false when it is not in the
+// lobby, including after being kicked.
+func (c *Client) Member() (Member, bool) { return c.hub.member(c) }
+
+func (c *Client) Owner() bool { return c.owner }
+
+func (c *Client) History() []Message { return c.hub.History() }
+
+func (c *Client) Members() []Member { return c.hub.Members() }
@@ -0,0 +1,301 @@
+// Package chat runs the lobby, an ephemeral chat room shared by the visitors
+// connected to one portfolio. Nothing is persisted: the scrollback lives in
+// memory and is gone after a restart.
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/andatoshiki/termfolio/textutil"
+)
+
+const (
+	MaxNick = 16
+	MaxText = 200
+
+	scrollback = 200
+
+	// Each member may burst rateBurst messages, then one every rateRefill.
+	rateBurst  = 5
+	rateRefill = 2 * time.Second
+
+	// kickBan keeps a kicked visitor from rejoining straight away.
+	kickBan = 10 * time.Minute
+)
+
+var (
+	ErrNick        = fmt.Errorf("nickname must be 2-%d letters, digits, - or _", MaxNick)
+	ErrNickTaken   = errors.New("nickname is taken")
+	ErrText        = fmt.Errorf("message must be 1-%d characters", MaxText)
+	ErrRateLimited = errors.New("slow down a little")
+	ErrMuted       = errors.New("you are muted")
+	ErrBanned      = errors.New("you were removed from the lobby, try again later")
+	ErrNotMember   = errors.New("join the lobby first")
+	ErrNotOwner    = errors.New("only the owner can do that")
+	ErrNoSuchNick  = errors.New("no one with that nickname is here")
+)
+
+// Message is a line in the lobby. System messages announce joins, leaves
+// and moderation and have no Nick.
+type Message struct {
+	ID    uint64
+	Nick  string
+	Owner bool
+	Text  string
+	At    time.Time
+}
+
+// Member is a nickname in the lobby.
+type Member struct {
+	Nick  string
+	Owner bool
+}
+
+type member struct {
+	nick   string
+	key    string
+	owner  bool
+	tokens float64
+	refill time.Time
+}
+
+// Hub is the lobby of one portfolio. Publish is called after every change,
+// without the lock held; subscribers re-read History and Members.
+type Hub struct {
+	mu          sync.Mutex
+	publish     func()
+	now         func() time.Time
+	nextClient  uint64
+	nextMessage uint64
+	members     map[uint64]*member
+	history     []Message
+	muted       map[string]time.Time
+	banned      map[string]time.Time
+}
+
+func NewHub(publish func()) *Hub {
+	return &Hub{
+		publish: publish,
+		now:     time.Now,
+		members: make(map[uint64]*member),
+		muted:   make(map[string]time.Time),
+		banned:  make(map[string]time.Time),
+	}
+}
+
+// Client returns a handle for one session. Key identifies the visitor for
+// mutes and kicks across rejoins; owner sessions may moderate.
+func (h *Hub) Client(key string, owner bool) *Client {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.nextClient++
+	if key == "" {
+		key = fmt.Sprintf("client-%d", h.nextClient)
+	}
+	return &Client{hub: h, id: h.nextClient, key: key, owner: owner}
+}
+
+// History returns the scrollback, oldest first.
+func (h *Hub) History() []Message {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]Message(nil), h.history...)
+}
+
+// Members returns the people in the lobby sorted by nickname.
+func (h *Hub) Members() []Member {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	members := make([]Member, 0, len(h.members))
+	for _, m := range h.members {
+		members = append(members, Member{Nick: m.nick, Owner: m.owner})
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return strings.ToLower(members[i].Nick) < strings.ToLower(members[j].Nick)
+	})
+	return members
+}
+
+func (h *Hub) join(c *Client, nick string) error {
+	nick = strings.TrimSpace(nick)
+	if !validNick(nick) {
+		return ErrNick
+	}
+
+	h.mu.Lock()
+	now := h.now()
+	if until, ok := h.banned[c.key]; ok && now.Before(until) {
+		h.mu.Unlock()
+		return ErrBanned
+	}
+	if _, ok := h.members[c.id]; ok {
+		h.mu.Unlock()
+		return nil
+	}
+	if h.findLocked(nick) != 0 {
+		h.mu.Unlock()
+		return ErrNickTaken
+	}
+	h.members[c.id] = &member{nick: nick, key: c.key, owner: c.owner, tokens: rateBurst, refill: now}
+	h.systemLocked(now, "%s joined", nick)
+	h.mu.Unlock()
+
+	h.publish()
+	return nil
+}
+
+func (h *Hub) leave(c *Client) {
+	h.mu.Lock()
+	m, ok := h.members[c.id]
+	if !ok {
+		h.mu.Unlock()
+		return
+	}
+	delete(h.members, c.id)
+	h.systemLocked(h.now(), "%s left", m.nick)
+	h.mu.Unlock()
+
+	h.publish()
+}
+
+func (h *Hub) say(c *Client, text string) error {
+	text = textutil.Clean(text)
+	if text == "" || utf8.RuneCountInString(text) > MaxText {
+		return ErrText
+	}
+
+	h.mu.Lock()
+	m, ok := h.members[c.id]
+	if !ok {
+		h.mu.Unlock()
+		return ErrNotMember
+	}
+	now := h.now()
+	if until, ok := h.muted[m.key]; ok && now.Before(until) {
+		h.mu.Unlock()
+		return fmt.Errorf("%w for %s", ErrMuted, until.Sub(now).Round(time.Second))
+	}
+	m.tokens = min(rateBurst, m.tokens+now.Sub(m.refill).Seconds()/rateRefill.Seconds())
+	m.refill = now
+	if m.tokens < 1 {
+		h.mu.Unlock()
+		return ErrRateLimited
+	}
+	m.tokens--
+	h.appendLocked(Message{Nick: m.nick, Owner: m.owner, Text: text, At: now})
+	h.mu.Unlock()
+
+	h.publish()
+	return nil
+}
+
+// kick removes a member and keeps their key out for kickBan.
+func (h *Hub) kick(c *Client, nick string) error {
+	if !c.owner {
+		return ErrNotOwner
+	}
+	h.mu.Lock()
+	id := h.findLocked(nick)
+	if id == 0 {
+		h.mu.Unlock()
+		return ErrNoSuchNick
+	}
+	m := h.members[id]
+	now := h.now()
+	delete(h.members, id)
+	h.banned[m.key] = now.Add(kickBan)
+	h.systemLocked(now, "%s was removed by the owner", m.nick)
+	h.mu.Unlock()
+
+	h.publish()
+	return nil
+}
+
+// mute silences a member's key for d; zero d lifts the mute.
+func (h *Hub) mute(c *Client, nick string, d time.Duration) error {
+	if !c.owner {
+		return ErrNotOwner
+	}
+	h.mu.Lock()
+	id := h.findLocked(nick)
+	if id == 0 {
+		h.mu.Unlock()
+		return ErrNoSuchNick
+	}
+	m := h.members[id]
+	now := h.now()
+	if d <= 0 {
+		delete(h.muted, m.key)
+		h.systemLocked(now, "%s can talk again", m.nick)
+	} else {
+		h.muted[m.key] = now.Add(d)
+		h.systemLocked(now, "%s was muted for %s", m.nick, d)
+	}
+	h.mu.Unlock()
+
+	h.publish()
+	return nil
+}
+
+func (h *Hub) member(c *Client) (Member, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	m, ok := h.members[c.id]
+	if !ok {
+		return Member{}, false
+	}
+	return Member{Nick: m.nick, Owner: m.owner}, true
+}
+
+// findLocked returns the client ID using nick, ignoring case, or 0.
+func (h *Hub) findLocked(nick string) uint64 {
+	for id, m := range h.members {
+		if strings.EqualFold(m.nick, nick) {
+			return id
+		}
+	}
+	return 0
+}
+
+func (h *Hub) systemLocked(now time.Time, format string, args ...any) {
+	h.appendLocked(Message{Text: fmt.Sprintf(format, args...), At: now})
+}
+
+func (h *Hub) appendLocked(msg Message) {
+	h.nextMessage++
+	msg.ID = h.nextMessage
+	h.history = append(h.history, msg)
+	if len(h.history) > scrollback {
+		h.history = append(h.history[:0:0], h.history[len(h.history)-scrollback:]...)
+	}
+	for key, until := range h.muted {
+		if msg.At.After(until) {
+			delete(h.muted, key)
+		}
+	}
+	for key, until := range h.banned {
+		if msg.At.After(until) {
+			delete(h.banned, key)
+		}
+	}
+}
+
+func validNick(nick string) bool {
+	n := utf8.RuneCountInString(nick)
+	if n < 2 || n > MaxNick {
+		return false
+	}
+	for _, r := range nick {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,147 @@
+package chat
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestHub() (*Hub, *time.Time, *int) {
+	now := time.Unix(1700000000, 0)
+	published := 0
+	h := NewHub(func() { published++ })
+	h.now = func() time.Time { return now }
+	return h, &now, &published
+}
+
+func TestJoinAndSay(t *testing.T) {
+	h, _, published := newTestHub()
+	alice := h.Client("10.0.0.1", false)
+	bob := h.Client("10.0.0.2", false)
+
+	cases := []struct {
+		name   string
+		client *Client
+		nick   string
+		want   error
+	}{
+		{name: "valid", client: alice, nick: "alice"},
+		{name: "taken ignoring case", client: bob, nick: "ALICE", want: ErrNickTaken},
+		{name: "too short", client: bob, nick: "b", want: ErrNick},
+		{name: "spaces", client: bob, nick: "bob smith", want: ErrNick},
+		{name: "escape", client: bob, nick: "bob\x1b[31m", want: ErrNick},
+		{name: "valid second", client: bob, nick: "bob_2"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.client.Join(tc.nick); !errors.Is(err, tc.want) {
+				t.Fatalf("Join(%q) error = %v, want %v", tc.nick, err, tc.want)
+			}
+		})
+	}
+
+	if err := alice.Say("  hi\x1b[2J ‮all\t\n"); err != nil {
+		t.Fatalf("Say() error = %v", err)
+	}
+	if err := alice.Say("\x1b[31m"); !errors.Is(err, ErrText) {
+		t.Fatalf("Say() error = %v, want %v", err, ErrText)
+	}
+	if err := h.Client("10.0.0.3", false).Say("hi"); !errors.Is(err, ErrNotMember) {
+		t.Fatalf("Say() error = %v, want %v", err, ErrNotMember)
+	}
+
+	history := h.History()
+	last := history[len(history)-1]
+	if last.Nick != "alice" || last.Text != "hi all" {
+		t.Fatalf("last message = %+v", last)
+	}
+	if members := h.Members(); len(members) != 2 || members[0].Nick != "alice" || members[1].Nick != "bob_2" {
+		t.Fatalf("Members() = %+v", members)
+	}
+
+	bob.Leave()
+	bob.Leave()
+	if got := h.History(); got[len(got)-1].Text != "bob_2 left" || *published != 4 {
+		t.Fatalf("after Leave: last = %+v, published = %d", got[len(got)-1], *published)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	h, now, _ := newTestHub()
+	c := h.Client("10.0.0.1", false)
+	if err := c.Join("alice"); err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+
+	for i := 0; i < rateBurst; i++ {
+		if err := c.Say("hi"); err != nil {
+			t.Fatalf("Say() #%d error = %v", i+1, err)
+		}
+	}
+	if err := c.Say("hi"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Say() error = %v, want %v", err, ErrRateLimited)
+	}
+	*now = now.Add(rateRefill)
+	if err := c.Say("hi"); err != nil {
+		t.Fatalf("Say() after refill error = %v", err)
+	}
+}
+
+func TestOwnerModeration(t *testing.T) {
+	h, now, _ := newTestHub()
+	owner := h.Client("owner-key", true)
+	troll := h.Client("10.0.0.9", false)
+	for c, nick := range map[*Client]string{owner: "owner", troll: "troll"} {
+		if err := c.Join(nick); err != nil {
+			t.Fatalf("Join(%q) error = %v", nick, err)
+		}
+	}
+
+	if err := troll.Kick("owner"); !errors.Is(err, ErrNotOwner) {
+		t.Fatalf("Kick() by visitor error = %v, want %v", err, ErrNotOwner)
+	}
+
+	if err := owner.Mute("troll", time.Minute); err != nil {
+		t.Fatalf("Mute() error = %v", err)
+	}
+	if err := troll.Say("spam"); !errors.Is(err, ErrMuted) {
+		t.Fatalf("Say() while muted error = %v, want %v", err, ErrMuted)
+	}
+
+	if err := owner.Kick("TROLL"); err != nil {
+		t.Fatalf("Kick() error = %v", err)
+	}
+	if _, ok := troll.Member(); ok {
+		t.Fatalf("troll is still a member after Kick")
+	}
+	// The ban follows the key, not the client
+	if err := h.Client("10.0.0.9", false).Join("troll2"); !errors.Is(err, ErrBanned) {
+		t.Fatalf("Join() after Kick error = %v, want %v", err, ErrBanned)
+	}
+	*now = now.Add(kickBan + time.Second)
+	if err := troll.Join("troll"); err != nil {
+		t.Fatalf("Join() after ban error = %v", err)
+	}
+	if err := owner.Kick("nobody"); !errors.Is(err, ErrNoSuchNick) {
+		t.Fatalf("Kick() error = %v, want %v", err, ErrNoSuchNick)
+	}
+}
+
+func TestScrollbackIsBounded(t *testing.T) {
+	h, now, _ := newTestHub()
+	c := h.Client("10.0.0.1", false)
+	if err := c.Join("alice"); err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+	for i := 0; i < scrollback+10; i++ {
+		*now = now.Add(rateRefill)
+		if err := c.Say(strings.Repeat("x", i%MaxText+1)); err != nil {
+			t.Fatalf("Say() error = %v", err)
+		}
+	}
+	history := h.History()
+	if len(history) != scrollback || history[0].ID != 12 {
+		t.Fatalf("len = %d, first ID = %d", len(history), history[0].ID)
+	}
+}
@@ -2,28 +2,51 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	SSH     SSHConfig     `yaml:"ssh"`
-	Counter CounterConfig `yaml:"counter"`
-	Stats   StatsConfig   `yaml:"stats"`
+	SSH      SSHConfig      `yaml:"ssh"`
+	Counter  CounterConfig  `yaml:"counter"`
+	Stats    StatsConfig    `yaml:"stats"`
+	Content  ContentConfig  `yaml:"content"`
+	Messages MessagesConfig `yaml:"messages"`
+	Lobby    LobbyConfig    `yaml:"lobby"`
+	Metrics  MetricsConfig  `yaml:"metrics"`
+	Log      LogConfig      `yaml:"log"`
+	Limits   LimitsConfig   `yaml:"limits"`
+	Tenants  []TenantConfig `yaml:"tenants"`
 }
 
 type SSHConfig struct {
 	Port        int    `yaml:"port"`
 	Address     string `yaml:"address"`
 	HostKeyPath string `yaml:"hostKeyPath"`
+	PublicHost  string `yaml:"publicHost"`
+	// AdminKeys are the owner's public keys in authorized_keys format.
+	// Sessions authenticated with one of them open the admin TUI of any
+	// portfolio on the server.
+	AdminKeys []string `yaml:"adminKeys"`
 }
 
+// CounterConfig controls the visitor counter. IPs are stored as keyed
+// hashes with the secret salt kept at SaltPath, which is replaced every
+// SaltRotation; zero keeps it forever. Visitors are kept for RetentionDays
+// after their last visit and then only counted in anonymous daily totals;
+// zero keeps them forever.
 type CounterConfig struct {
-	Enabled bool   `yaml:"enabled"`
-	DBPath  string `yaml:"dbPath"`
+	Enabled       bool          `yaml:"enabled"`
+	DBPath        string        `yaml:"dbPath"`
+	SaltPath      string        `yaml:"saltPath"`
+	SaltRotation  time.Duration `yaml:"saltRotation"`
+	RetentionDays int           `yaml:"retentionDays"`
 }
 
 type StatsConfig struct {
@@ -31,6 +54,87 @@ type StatsConfig struct {
 	GeoLiteDBPath string `yaml:"geoLiteDbPath"`
 }
 
+// ContentConfig points at the portfolio content file. An empty path uses the
+// built-in content. ReloadInterval controls how often the content and config
+// files are polled for changes; zero disables hot reload.
+type ContentConfig struct {
+	Path           string        `yaml:"path"`
+	ReloadInterval time.Duration `yaml:"reloadInterval"`
+}
+
+// MessagesConfig controls the contact form. Messages are stored in the
+// counter database and forwarded through every configured sink; failed
+// deliveries are retried MaxAttempts times with exponential backoff starting
+// at RetryInterval.
+type MessagesConfig struct {
+	Enabled       bool          `yaml:"enabled"`
+	MaxAttempts   int           `yaml:"maxAttempts"`
+	RetryInterval time.Duration `yaml:"retryInterval"`
+	SMTP          SMTPConfig    `yaml:"smtp"`
+	Webhook       WebhookConfig `yaml:"webhook"`
+}
+
+// SMTPConfig is the mail server messages are sent through. An empty Host
+// disables the sink; an empty To sends to the portfolio's profile email.
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+	To       string `yaml:"to"`
+}
+
+// WebhookConfig is an HTTP endpoint messages are POSTed to as JSON. An empty
+// URL disables the sink.
+type WebhookConfig struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+}
+
+// LobbyConfig enables the lobby page, a chat room between the visitors
+// connected at the same time. Nothing said there is stored.
+type LobbyConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// MetricsConfig enables an HTTP listener on Address serving /metrics in the
+// Prometheus text format.
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Address string `yaml:"address"`
+}
+
+// LogConfig selects how the server logs: Format is "text" or "json" and
+// Level is the least severe level written, "debug", "info", "warn" or
+// "error".
+type LogConfig struct {
+	Format string `yaml:"format"`
+	Level  string `yaml:"level"`
+}
+
+// LimitsConfig caps the sessions visitors open. Each IP may open Burst
+// sessions at once and then ConnectionsPerMinute more; MaxSessionsPerIP and
+// MaxSessions cap the sessions open at the same time. Zero disables a
+// limit. Owner sessions are not limited.
+type LimitsConfig struct {
+	ConnectionsPerMinute int `yaml:"connectionsPerMinute"`
+	Burst                int `yaml:"burst"`
+	MaxSessionsPerIP     int `yaml:"maxSessionsPerIP"`
+	MaxSessions          int `yaml:"maxSessions"`
+}
+
+// TenantConfig is a portfolio served to visitors who connect as User.
+// DBPath defaults to a per-tenant file next to the counter database.
+// AdminKeys open the admin TUI of this portfolio only.
+type TenantConfig struct {
+	User      string   `yaml:"user"`
+	Name      string   `yaml:"name"`
+	Content   string   `yaml:"content"`
+	DBPath    string   `yaml:"dbPath"`
+	AdminKeys []string `yaml:"adminKeys"`
+}
+
 func (s *StatsConfig) UnmarshalYAML(value *yaml.Node) error {
 	switch value.Kind {
 	case yaml.ScalarNode:
@@ -72,8 +176,11 @@ func (c *CounterConfig) UnmarshalYAML(value *yaml.Node) error {
 		return nil
 	case yaml.MappingNode:
 		type counterYAML struct {
-			Enabled *bool   `yaml:"enabled"`
-			DBPath  *string `yaml:"dbPath"`
+			Enabled       *bool          `yaml:"enabled"`
+			DBPath        *string        `yaml:"dbPath"`
+			SaltPath      *string        `yaml:"saltPath"`
+			SaltRotation  *time.Duration `yaml:"saltRotation"`
+			RetentionDays *int           `yaml:"retentionDays"`
 		}
 		var raw counterYAML
 		if err := value.Decode(&raw); err != nil {
@@ -85,12 +192,179 @@ func (c *CounterConfig) UnmarshalYAML(value *yaml.Node) error {
 		if raw.DBPath != nil {
 			c.DBPath = *raw.DBPath
 		}
+		if raw.SaltPath != nil {
+			c.SaltPath = *raw.SaltPath
+		}
+		if raw.SaltRotation != nil {
+			c.SaltRotation = *raw.SaltRotation
+		}
+		if raw.RetentionDays != nil {
+			c.RetentionDays = *raw.RetentionDays
+		}
 		return nil
 	default:
 		return fmt.Errorf("invalid counter config")
 	}
 }
 
+func (l *LobbyConfig) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		var enabled bool
+		if err := value.Decode(&enabled); err != nil {
+			return err
+		}
+		l.Enabled = enabled
+		return nil
+	case yaml.MappingNode:
+		type lobbyYAML struct {
+			Enabled *bool `yaml:"enabled"`
+		}
+		var raw lobbyYAML
+		if err := value.Decode(&raw); err != nil {
+			return err
+		}
+		if raw.Enabled != nil {
+			l.Enabled = *raw.Enabled
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid lobby config")
+	}
+}
+
+func (m *MetricsConfig) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		var enabled bool
+		if err := value.Decode(&enabled); err != nil {
+			return err
+		}
+		m.Enabled = enabled
+		return nil
+	case yaml.MappingNode:
+		type metricsYAML struct {
+			Enabled *bool   `yaml:"enabled"`
+			Address *string `yaml:"address"`
+		}
+		var raw metricsYAML
+		if err := value.Decode(&raw); err != nil {
+			return err
+		}
+		if raw.Enabled != nil {
+			m.Enabled = *raw.Enabled
+		}
+		if raw.Address != nil {
+			m.Address = *raw.Address
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid metrics config")
+	}
+}
+
+func (l *LogConfig) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		var format string
+		if err := value.Decode(&format); err != nil {
+			return err
+		}
+		l.Format = format
+		return nil
+	case yaml.MappingNode:
+		type logYAML struct {
+			Format *string `yaml:"format"`
+			Level  *string `yaml:"level"`
+		}
+		var raw logYAML
+		if err := value.Decode(&raw); err != nil {
+			return err
+		}
+		if raw.Format != nil {
+			l.Format = *raw.Format
+		}
+		if raw.Level != nil {
+			l.Level = *raw.Level
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid log config")
+	}
+}
+
+func (m *MessagesConfig) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		var enabled bool
+		if err := value.Decode(&enabled); err != nil {
+			return err
+		}
+		m.Enabled = enabled
+		return nil
+	case yaml.MappingNode:
+		type messagesYAML struct {
+			Enabled       *bool          `yaml:"enabled"`
+			MaxAttempts   *int           `yaml:"maxAttempts"`
+			RetryInterval *time.Duration `yaml:"retryInterval"`
+			SMTP          *SMTPConfig    `yaml:"smtp"`
+			Webhook       *WebhookConfig `yaml:"webhook"`
+		}
+		var raw messagesYAML
+		if err := value.Decode(&raw); err != nil {
+			return err
+		}
+		if raw.Enabled != nil {
+			m.Enabled = *raw.Enabled
+		}
+		if raw.MaxAttempts != nil {
+			m.MaxAttempts = *raw.MaxAttempts
+		}
+		if raw.RetryInterval != nil {
+			m.RetryInterval = *raw.RetryInterval
+		}
+		if raw.SMTP != nil {
+			m.SMTP = *raw.SMTP
+		}
+		if raw.Webhook != nil {
+			m.Webhook = *raw.Webhook
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid messages config")
+	}
+}
+
+func (c *ContentConfig) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		var path string
+		if err := value.Decode(&path); err != nil {
+			return err
+		}
+		c.Path = path
+		return nil
+	case yaml.MappingNode:
+		type contentYAML struct {
+			Path           *string        `yaml:"path"`
+			ReloadInterval *time.Duration `yaml:"reloadInterval"`
+		}
+		var raw contentYAML
+		if err := value.Decode(&raw); err != nil {
+			return err
+		}
+		if raw.Path != nil {
+			c.Path = *raw.Path
+		}
+		if raw.ReloadInterval != nil {
+			c.ReloadInterval = *raw.ReloadInterval
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid content config")
+	}
+}
+
 // Load loads config from the specified path.
 // If the file doesn't exist and userProvided is false, returns defaults.
 // If the file doesn't exist and userProvided is true, returns an error.
@@ -103,13 +377,37 @@ func Load(configPath string, userProvided bool) (*Config, error) {
 			HostKeyPath: ".ssh/host_ed25519",
 		},
 		Counter: CounterConfig{
-			Enabled: true,
-			DBPath:  "data/visitors.db",
+			Enabled:  true,
+			DBPath:   "data/visitors.db",
+			SaltPath: "data/ip-salt.json",
 		},
 		Stats: StatsConfig{
 			Enabled:       false,
 			GeoLiteDBPath: "data/GeoLite2-Country.mmdb",
 		},
+		Content: ContentConfig{
+			ReloadInterval: 2 * time.Second,
+		},
+		// The contact form stores visitors' names, addresses and messages,
+		// so the owner opts in
+		Messages: MessagesConfig{
+			Enabled:       false,
+			MaxAttempts:   5,
+			RetryInterval: 30 * time.Second,
+		},
+		Metrics: MetricsConfig{
+			Address: "127.0.0.1:9100",
+		},
+		Log: LogConfig{
+			Format: "text",
+			Level:  "info",
+		},
+		Limits: LimitsConfig{
+			ConnectionsPerMinute: 30,
+			Burst:                10,
+			MaxSessionsPerIP:     10,
+			MaxSessions:          500,
+		},
 	}
 
 	// Try to read config file
@@ -125,6 +423,8 @@ func Load(configPath string, userProvided bool) (*Config, error) {
 			resolveHostKeyPath(cfg, configPath)
 			resolveCounterPath(cfg, configPath)
 			resolveStatsPath(cfg, configPath)
+			resolveContentPath(cfg, configPath)
+			resolveTenantPaths(cfg, configPath)
 			return cfg, nil
 		}
 		// Other read errors (permissions, etc.) are always errors
@@ -143,6 +443,27 @@ func Load(configPath string, userProvided bool) (*Config, error) {
 	resolveHostKeyPath(cfg, configPath)
 	resolveCounterPath(cfg, configPath)
 	resolveStatsPath(cfg, configPath)
+	resolveContentPath(cfg, configPath)
+	resolveTenantPaths(cfg, configPath)
+
+	if err := validateTenants(cfg.Tenants); err != nil {
+		return nil, fmt.Errorf("invalid config file at %s: %w", configPath, err)
+	}
+	if err := validateMessages(cfg.Messages); err != nil {
+		return nil, fmt.Errorf("invalid config file at %s: %w", configPath, err)
+	}
+	if err := validateCounter(cfg.Counter); err != nil {
+		return nil, fmt.Errorf("invalid config file at %s: %w", configPath, err)
+	}
+	if err := validateMetrics(cfg.Metrics); err != nil {
+		return nil, fmt.Errorf("invalid config file at %s: %w", configPath, err)
+	}
+	if err := validateLog(cfg.Log); err != nil {
+		return nil, fmt.Errorf("invalid config file at %s: %w", configPath, err)
+	}
+	if err := validateLimits(cfg.Limits); err != nil {
+		return nil, fmt.Errorf("invalid config file at %s: %w", configPath, err)
+	}
 
 	return cfg, nil
 }
@@ -163,6 +484,14 @@ func applyEnvVarOverrides(cfg *Config) {
 	if hostKeyPath := os.Getenv("SSH_HOST_KEY_PATH"); hostKeyPath != "" {
 		cfg.SSH.HostKeyPath = hostKeyPath
 	}
+
+	if contentPath := os.Getenv("CONTENT_PATH"); contentPath != "" {
+		cfg.Content.Path = contentPath
+	}
+
+	if password := os.Getenv("SMTP_PASSWORD"); password != "" {
+		cfg.Messages.SMTP.Password = password
+	}
 }
 
 func resolveHostKeyPath(cfg *Config, configPath string) {
@@ -183,14 +512,16 @@ func resolveCounterPath(cfg *Config, configPath string) {
 	if cfg == nil {
 		return
 	}
-	if cfg.Counter.DBPath == "" || filepath.IsAbs(cfg.Counter.DBPath) {
-		return
-	}
 	if configPath == "" {
 		return
 	}
 	baseDir := filepath.Dir(configPath)
-	cfg.Counter.DBPath = filepath.Clean(filepath.Join(baseDir, cfg.Counter.DBPath))
+	if cfg.Counter.DBPath != "" && !filepath.IsAbs(cfg.Counter.DBPath) {
+		cfg.Counter.DBPath = filepath.Clean(filepath.Join(baseDir, cfg.Counter.DBPath))
+	}
+	if cfg.Counter.SaltPath != "" && !filepath.IsAbs(cfg.Counter.SaltPath) {
+		cfg.Counter.SaltPath = filepath.Clean(filepath.Join(baseDir, cfg.Counter.SaltPath))
+	}
 }
 
 func resolveStatsPath(cfg *Config, configPath string) {
@@ -207,6 +538,152 @@ func resolveStatsPath(cfg *Config, configPath string) {
 	cfg.Stats.GeoLiteDBPath = filepath.Clean(filepath.Join(baseDir, cfg.Stats.GeoLiteDBPath))
 }
 
+func resolveContentPath(cfg *Config, configPath string) {
+	if cfg == nil {
+		return
+	}
+	if cfg.Content.Path == "" || filepath.IsAbs(cfg.Content.Path) {
+		return
+	}
+	if configPath == "" {
+		return
+	}
+	baseDir := filepath.Dir(configPath)
+	cfg.Content.Path = filepath.Clean(filepath.Join(baseDir, cfg.Content.Path))
+}
+
+func resolveTenantPaths(cfg *Config, configPath string) {
+	if cfg == nil {
+		return
+	}
+	baseDir := filepath.Dir(configPath)
+	for i := range cfg.Tenants {
+		t := &cfg.Tenants[i]
+		if t.Content != "" && !filepath.IsAbs(t.Content) && configPath != "" {
+			t.Content = filepath.Clean(filepath.Join(baseDir, t.Content))
+		}
+		if t.DBPath == "" {
+			t.DBPath = filepath.Join(filepath.Dir(cfg.Counter.DBPath), "visitors-"+strings.ToLower(t.User)+".db")
+			continue
+		}
+		if !filepath.IsAbs(t.DBPath) && configPath != "" {
+			t.DBPath = filepath.Clean(filepath.Join(baseDir, t.DBPath))
+		}
+	}
+}
+
+func validateTenants(tenants []TenantConfig) error {
+	seen := make(map[string]bool, len(tenants))
+	for i, t := range tenants {
+		user := strings.ToLower(strings.TrimSpace(t.User))
+		if user == "" {
+			return fmt.Errorf("tenants[%d]: user is required", i)
+		}
+		if strings.ContainsAny(user, `/\`) {
+			return fmt.Errorf("tenants[%d]: invalid user %q", i, t.User)
+		}
+		if seen[user] {
+			return fmt.Errorf("tenants[%d]: duplicate user %q", i, t.User)
+		}
+		if strings.TrimSpace(t.Content) == "" {
+			return fmt.Errorf("tenants[%d]: content is required", i)
+		}
+		seen[user] = true
+	}
+	return nil
+}
+
+func validateCounter(c CounterConfig) error {
+	if c.Enabled && c.SaltPath == "" {
+		return fmt.Errorf("counter: saltPath is required")
+	}
+	if c.SaltRotation < 0 {
+		return fmt.Errorf("counter: saltRotation must not be negative")
+	}
+	if c.RetentionDays < 0 {
+		return fmt.Errorf("counter: retentionDays must not be negative")
+	}
+	return nil
+}
+
+func validateMetrics(m MetricsConfig) error {
+	if !m.Enabled {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(m.Address); err != nil {
+		return fmt.Errorf("metrics: address must be host:port: %w", err)
+	}
+	return nil
+}
+
+func validateLog(l LogConfig) error {
+	switch l.Format {
+	case "text", "json":
+	default:
+		return fmt.Errorf("log: format must be text or json")
+	}
+	switch l.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("log: level must be debug, info, warn or error")
+	}
+	return nil
+}
+
+func validateLimits(l LimitsConfig) error {
+	if l.ConnectionsPerMinute < 0 || l.Burst < 0 || l.MaxSessionsPerIP < 0 || l.MaxSessions < 0 {
+		return fmt.Errorf("limits: values must not be negative")
+	}
+	if l.ConnectionsPerMinute > 0 && l.Burst < 1 {
+		return fmt.Errorf("limits: burst must be at least 1 when connectionsPerMinute is set")
+	}
+	return nil
+}
+
+func validateMessages(m MessagesConfig) error {
+	if m.MaxAttempts < 1 {
+		return fmt.Errorf("messages: maxAttempts must be at least 1")
+	}
+	if m.RetryInterval <= 0 {
+		return fmt.Errorf("messages: retryInterval must be positive")
+	}
+	if m.SMTP.Host != "" && m.SMTP.From == "" {
+		return fmt.Errorf("messages.smtp: from is required")
+	}
+	if m.Webhook.URL != "" && !strings.HasPrefix(m.Webhook.URL, "http://") && !strings.HasPrefix(m.Webhook.URL, "https://") {
+		return fmt.Errorf("messages.webhook: url must be http or https")
+	}
+	return nil
+}
+
+// FindTenant returns the tenant config for user, if any.
+func (cfg *Config) FindTenant(user string) (TenantConfig, bool) {
+	for _, t := range cfg.Tenants {
+		if strings.EqualFold(t.User, user) {
+			return t, true
+		}
+	}
+	return TenantConfig{}, false
+}
+
+// ConnectCommand is the ssh command a visitor runs to reach user's portfolio.
+func (cfg *SSHConfig) ConnectCommand(user string) string {
+	host := cfg.PublicHost
+	if host == "" {
+		host = cfg.Address
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	if user != "" {
+		host = user + "@" + host
+	}
+	if cfg.Port == 22 {
+		return "ssh " + host
+	}
+	return fmt.Sprintf("ssh -p %d %s", cfg.Port, host)
+}
+
 func (cfg *SSHConfig) ListenAddr() string {
 	return fmt.Sprintf("%s:%d", cfg.Address, cfg.Port)
 }
@@ -0,0 +1,386 @@
+// Package content holds the portfolio data rendered by the TUI pages and
+// loads it from an optional YAML content file.
+package content
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Content struct {
+	Resume     string       `yaml:"resume"`
+	Profile    Profile      `yaml:"profile"`
+	Menu       []MenuItem   `yaml:"menu"`
+	Splash     Splash       `yaml:"splash"`
+	About      About        `yaml:"about"`
+	Projects   []Project    `yaml:"projects"`
+	Education  []Education  `yaml:"education"`
+	Experience []Experience `yaml:"experience"`
+	Contact    Contact      `yaml:"contact"`
+	Gallery    []Picture    `yaml:"gallery"`
+	// Warnings are problems Load tolerated, such as missing image files.
+	Warnings []string `yaml:"-"`
+}
+
+// Profile identifies the portfolio owner on generated documents such as the
+// résumé and vCard.
+type Profile struct {
+	Name     string `yaml:"name"`
+	Title    string `yaml:"title"`
+	Email    string `yaml:"email"`
+	Phone    string `yaml:"phone"`
+	Location string `yaml:"location"`
+	URL      string `yaml:"url"`
+}
+
+// MenuItem selects a section for the menu. Label and Description override
+// the section's built-in title and description when set.
+type MenuItem struct {
+	ID          string `yaml:"id"`
+	Label       string `yaml:"label"`
+	Description string `yaml:"description"`
+}
+
+type Splash struct {
+	IntroPrefix      string `yaml:"introPrefix"`
+	IntroName        string `yaml:"introName"`
+	IntroSuffix      string `yaml:"introSuffix"`
+	IntroLink        string `yaml:"introLink"`
+	OpenSourcePrefix string `yaml:"openSourcePrefix"`
+	OpenSourceLabel  string `yaml:"openSourceLabel"`
+	OpenSourceLink   string `yaml:"openSourceLink"`
+}
+
+// About holds the about page. Intro is Markdown; Image is an optional
+// PNG or JPEG avatar shown above the logo.
+type About struct {
+	Logo  string `yaml:"logo"`
+	Image string `yaml:"image"`
+	Intro string `yaml:"intro"`
+}
+
+// Project is a portfolio entry. Desc and Details are Markdown; Details is
+// the long write-up shown on the project's detail view.
+type Project struct {
+	Name        string    `yaml:"name"`
+	Desc        string    `yaml:"desc"`
+	Tech        string    `yaml:"tech"`
+	Link        string    `yaml:"link"`
+	Details     string    `yaml:"details"`
+	Links       []Link    `yaml:"links"`
+	Tags        []string  `yaml:"tags"`
+	Status      string    `yaml:"status"`
+	Year        string    `yaml:"year"`
+	Screenshots []Picture `yaml:"screenshots"`
+}
+
+// Picture is a project screenshot or gallery entry: ASCII art, or a PNG or
+// JPEG image rendered as half-block art.
+type Picture struct {
+	Caption string `yaml:"caption"`
+	Art     string `yaml:"art"`
+	Image   string `yaml:"image"`
+}
+
+type Education struct {
+	Role    string `yaml:"role"`
+	Company string `yaml:"company"`
+	Period  string `yaml:"period"`
+	Desc    string `yaml:"desc"`
+	URL     string `yaml:"url"`
+}
+
+type Experience struct {
+	Role    string `yaml:"role"`
+	Company string `yaml:"company"`
+	Period  string `yaml:"period"`
+	Desc    string `yaml:"desc"`
+}
+
+type Contact struct {
+	Greeting string         `yaml:"greeting"`
+	Groups   []ContactGroup `yaml:"groups"`
+}
+
+type ContactGroup struct {
+	Title string `yaml:"title"`
+	Links []Link `yaml:"links"`
+}
+
+type Link struct {
+	Label string `yaml:"label"`
+	Text  string `yaml:"text"`
+	URL   string `yaml:"url"`
+}
+
+func (m *MenuItem) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		var id string
+		if err := value.Decode(&id); err != nil {
+			return err
+		}
+		*m = MenuItem{ID: id}
+		return nil
+	case yaml.MappingNode:
+		type menuItemYAML MenuItem
+		var raw menuItemYAML
+		if err := value.Decode(&raw); err != nil {
+			return err
+		}
+		*m = MenuItem(raw)
+		return nil
+	default:
+		return fmt.Errorf("line %d: invalid menu item", value.Line)
+	}
+}
+
+func (p *Picture) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		var art string
+		if err := value.Decode(&art); err != nil {
+			return err
+		}
+		*p = Picture{Art: art}
+		return nil
+	case yaml.MappingNode:
+		type pictureYAML Picture
+		var raw pictureYAML
+		if err := value.Decode(&raw); err != nil {
+			return err
+		}
+		*p = Picture(raw)
+		return nil
+	default:
+		return fmt.Errorf("line %d: invalid picture", value.Line)
+	}
+}
+
+func (s Splash) IntroText() string {
+	return s.IntroPrefix + s.IntroName + s.IntroSuffix
+}
+
+// Load reads the content file at path and overlays it onto the built-in
+// defaults. Sections missing from the file keep their default values; an
+// empty path returns the defaults unchanged. When the file names a JSON
+// Resume under resume, its sections are applied first so the file's own
+// sections take precedence.
+func Load(path string) (*Content, error) {
+	if path == "" {
+		return Default(), nil
+	}
+	return load(path, Default())
+}
+
+// LoadWithoutDefaults reads the content file at path like Load, but onto
+// empty content, so sections missing from the file stay empty and required
+// ones fail validation. Tenants use it so they never serve the built-in
+// portfolio.
+func LoadWithoutDefaults(path string) (*Content, error) {
+	if path == "" {
+		return nil, fmt.Errorf("content file is required")
+	}
+	return load(path, &Content{})
+}
+
+// load overlays the content file at path onto c.
+func load(path string, c *Content) (*Content, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("content file not found at %s", path)
+		}
+		return nil, fmt.Errorf("failed to read content file at %s: %w", path, err)
+	}
+
+	resume := resumePath(data, filepath.Dir(path))
+	if resume != "" {
+		resumeData, err := os.ReadFile(resume)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JSON Resume at %s: %w", resume, err)
+		}
+		if err := ImportJSONResume(resumeData, c); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON Resume at %s: %w", resume, err)
+		}
+	}
+
+	if err := decode(data, c); err != nil {
+		return nil, fmt.Errorf("failed to parse content file at %s: %w", path, err)
+	}
+	c.Resume = resume
+
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid content file at %s: %w", path, err)
+	}
+	c.resolveImages(filepath.Dir(path))
+	c.Warnings = c.missingImages()
+
+	return c, nil
+}
+
+// missingImages lists the image files that cannot be found. Pages show
+// the text fallback for them until the file appears.
+func (c *Content) missingImages() []string {
+	var missing []string
+	c.eachImage(func(field, path string) {
+		if _, err := os.Stat(path); err != nil {
+			missing = append(missing, fmt.Sprintf("%s: image not found at %s", field, path))
+		}
+	})
+	return missing
+}
+
+// ImagePaths lists the image files the content shows, so a reload can be
+// triggered when one is added or changed.
+func (c *Content) ImagePaths() []string {
+	var paths []string
+	c.eachImage(func(field, path string) {
+		paths = append(paths, path)
+	})
+	return paths
+}
+
+// eachImage calls fn for every image path set in the content.
+func (c *Content) eachImage(fn func(field, path string)) {
+	visit := func(field, path string) {
+		if path != "" {
+			fn(field, path)
+		}
+	}
+	visit("about.image", c.About.Image)
+	for i, p := range c.Projects {
+		for j, shot := range p.Screenshots {
+			visit(fmt.Sprintf("projects[%d].screenshots[%d].image", i, j), shot.Image)
+		}
+	}
+	for i, pic := range c.Gallery {
+		visit(fmt.Sprintf("gallery[%d].image", i), pic.Image)
+	}
+}
+
+// resolveImages makes relative image paths relative to the content file.
+func (c *Content) resolveImages(dir string) {
+	resolve := func(path string) string {
+		if path == "" || filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(dir, path)
+	}
+	c.About.Image = resolve(c.About.Image)
+	for i := range c.Projects {
+		for j := range c.Projects[i].Screenshots {
+			c.Projects[i].Screenshots[j].Image = resolve(c.Projects[i].Screenshots[j].Image)
+		}
+	}
+	for i := range c.Gallery {
+		c.Gallery[i].Image = resolve(c.Gallery[i].Image)
+	}
+}
+
+// resumePath returns the JSON Resume named by the content file, relative to
+// dir. Parse errors are left for decode to report.
+func resumePath(data []byte, dir string) string {
+	var head struct {
+		Resume string `yaml:"resume"`
+	}
+	if err := yaml.Unmarshal(data, &head); err != nil || head.Resume == "" {
+		return ""
+	}
+	if filepath.IsAbs(head.Resume) {
+		return head.Resume
+	}
+	return filepath.Join(dir, head.Resume)
+}
+
+func decode(data []byte, c *Content) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// Validate reports the first required field that is missing.
+func (c *Content) Validate() error {
+	if c == nil {
+		return fmt.Errorf("content is nil")
+	}
+	if strings.TrimSpace(c.Profile.Name) == "" {
+		return fmt.Errorf("profile: name is required")
+	}
+	seen := make(map[string]bool, len(c.Menu))
+	for i, item := range c.Menu {
+		if strings.TrimSpace(item.ID) == "" {
+			return fmt.Errorf("menu[%d]: id is required", i)
+		}
+		if seen[item.ID] {
+			return fmt.Errorf("menu[%d]: duplicate id %q", i, item.ID)
+		}
+		seen[item.ID] = true
+	}
+	if strings.TrimSpace(c.Splash.IntroText()) == "" {
+		return fmt.Errorf("splash: intro text is required")
+	}
+	if strings.TrimSpace(c.About.Intro) == "" {
+		return fmt.Errorf("about: intro is required")
+	}
+	for i, p := range c.Projects {
+		if strings.TrimSpace(p.Name) == "" {
+			return fmt.Errorf("projects[%d]: name is required", i)
+		}
+		for j, l := range p.Links {
+			if strings.TrimSpace(l.Label) == "" {
+				return fmt.Errorf("projects[%d].links[%d]: label is required", i, j)
+			}
+			if strings.TrimSpace(l.URL) == "" {
+				return fmt.Errorf("projects[%d].links[%d]: url is required", i, j)
+			}
+		}
+		for j, shot := range p.Screenshots {
+			if strings.TrimSpace(shot.Art) == "" && shot.Image == "" {
+				return fmt.Errorf("projects[%d].screenshots[%d]: art or image is required", i, j)
+			}
+		}
+	}
+	for i, e := range c.Education {
+		if strings.TrimSpace(e.Role) == "" {
+			return fmt.Errorf("education[%d]: role is required", i)
+		}
+		if strings.TrimSpace(e.Company) == "" {
+			return fmt.Errorf("education[%d]: company is required", i)
+		}
+	}
+	for i, e := range c.Experience {
+		if strings.TrimSpace(e.Role) == "" {
+			return fmt.Errorf("experience[%d]: role is required", i)
+		}
+		if strings.TrimSpace(e.Company) == "" {
+			return fmt.Errorf("experience[%d]: company is required", i)
+		}
+	}
+	for i, g := range c.Contact.Groups {
+		for j, l := range g.Links {
+			if strings.TrimSpace(l.Label) == "" {
+				return fmt.Errorf("contact.groups[%d].links[%d]: label is required", i, j)
+			}
+			if strings.TrimSpace(l.URL) == "" {
+				return fmt.Errorf("contact.groups[%d].links[%d]: url is required", i, j)
+			}
+		}
+	}
+	for i, pic := range c.Gallery {
+		if strings.TrimSpace(pic.Art) == "" && pic.Image == "" {
+			return fmt.Errorf("gallery[%d]: art or image is required", i)
+		}
+	}
+	return nil
+}
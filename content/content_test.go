@@ -0,0 +1,206 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultIsValid(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("Default().Validate() = %v", err)
+	}
+}
+
+func TestLoadEmptyPathReturnsDefaults(t *testing.T) {
+	c, err := Load("")
+	if err != nil {
+		t.Fatalf("Load(\"\") error = %v", err)
+	}
+	if len(c.Projects) != len(Default().Projects) {
+		t.Fatalf("got %d projects, want %d", len(c.Projects), len(Default().Projects))
+	}
+}
+
+func TestLoadExample(t *testing.T) {
+	c, err := Load(filepath.Join("..", "content.yaml.example"))
+	if err != nil {
+		t.Fatalf("Load(example) error = %v", err)
+	}
+	if got, want := c.About.Intro, Default().About.Intro; got != want {
+		t.Fatalf("about intro = %q, want %q", got, want)
+	}
+	if len(c.Menu) == 0 || c.Menu[0].ID != "about" || c.Menu[3].Label != "School" {
+		t.Fatalf("menu = %#v, want scalar and mapping items", c.Menu)
+	}
+	if p := c.Projects[0]; len(p.Tags) != 3 || p.Year != "2024" || len(p.Screenshots) != 1 || p.Screenshots[0].Caption == "" {
+		t.Fatalf("project detail = %#v, want tags, year and a captioned screenshot", p)
+	}
+}
+
+func TestLoadOverlaysDefaults(t *testing.T) {
+	path := writeContent(t, `
+projects:
+  - name: "Only Project"
+    link: "https://example.com"
+`)
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(c.Projects) != 1 || c.Projects[0].Name != "Only Project" {
+		t.Fatalf("projects = %#v, want single overridden project", c.Projects)
+	}
+	if len(c.Education) != len(Default().Education) {
+		t.Fatalf("education was not kept from defaults")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "unknown field", body: "projcts: []\n", want: "field projcts not found"},
+		{name: "missing name", body: "projects:\n  - desc: nameless\n", want: "projects[0]: name is required"},
+		{name: "empty about", body: "about:\n  intro: \"  \"\n", want: "about: intro is required"},
+		{name: "duplicate menu id", body: "menu:\n  - about\n  - id: about\n", want: `menu[1]: duplicate id "about"`},
+		{name: "project link", body: "projects:\n  - name: x\n    links:\n      - label: Repo\n", want: "projects[0].links[0]: url is required"},
+		{name: "empty screenshot", body: "projects:\n  - name: x\n    screenshots:\n      - caption: nothing\n", want: "projects[0].screenshots[0]: art or image is required"},
+		{name: "missing url", body: "contact:\n  groups:\n    - title: x\n      links:\n        - label: Email\n", want: "url is required"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(writeContent(t, tc.body))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("Load() error = %v, want containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadResolvesImages(t *testing.T) {
+	path := writeContent(t, "about:\n  image: avatar.png\ngallery:\n  - image: avatar.png\n    caption: me\n")
+	avatar := filepath.Join(filepath.Dir(path), "avatar.png")
+	if err := os.WriteFile(avatar, []byte("png"), 0o644); err != nil {
+		t.Fatalf("write image: %v", err)
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if c.About.Image != avatar || c.Gallery[0].Image != avatar {
+		t.Fatalf("images = %q, %q, want %q", c.About.Image, c.Gallery[0].Image, avatar)
+	}
+}
+
+func TestLoadMissingImage(t *testing.T) {
+	path := writeContent(t, "gallery:\n  - image: nope.png\n    art: \"[x]\"\n")
+	missing := filepath.Join(filepath.Dir(path), "nope.png")
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := "gallery[0].image: image not found at " + missing
+	if len(c.Warnings) != 1 || c.Warnings[0] != want {
+		t.Fatalf("Warnings = %q, want [%q]", c.Warnings, want)
+	}
+	if paths := c.ImagePaths(); len(paths) != 1 || paths[0] != missing {
+		t.Fatalf("ImagePaths() = %q, want [%q]", paths, missing)
+	}
+
+	if err := os.WriteFile(missing, []byte("png"), 0o644); err != nil {
+		t.Fatalf("write image: %v", err)
+	}
+	c, err = Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(c.Warnings) != 0 {
+		t.Fatalf("Warnings = %q once the image exists, want none", c.Warnings)
+	}
+}
+
+func TestLoadWithoutDefaults(t *testing.T) {
+	cases := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{
+			name: "complete",
+			body: `
+profile:
+  name: "Ada"
+splash:
+  introName: "Ada's portfolio"
+about:
+  intro: "Hi, I'm Ada."
+`,
+		},
+		{
+			name: "missing profile",
+			body: `
+about:
+  intro: "Hi"
+`,
+			wantErr: "profile: name is required",
+		},
+		{
+			name: "missing about",
+			body: `
+profile:
+  name: "Ada"
+splash:
+  introName: "Ada"
+`,
+			wantErr: "about: intro is required",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := LoadWithoutDefaults(writeContent(t, tc.body))
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("LoadWithoutDefaults() error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadWithoutDefaults() error = %v", err)
+			}
+			// Sections the file leaves out stay empty
+			if len(c.Projects) != 0 || len(c.Education) != 0 || c.Profile.Email != "" || c.Splash.OpenSourceLink != "" {
+				t.Fatalf("LoadWithoutDefaults() kept defaults: %+v", c)
+			}
+		})
+	}
+
+	if _, err := LoadWithoutDefaults(""); err == nil {
+		t.Fatalf("LoadWithoutDefaults(\"\") error = nil, want error")
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("Load() error = %v, want not found", err)
+	}
+}
+
+func writeContent(t *testing.T, body string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "content.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("write content: %v", err)
+	}
+	return path
+}
@@ -0,0 +1,163 @@
+package content
+
+const defaultAboutLogo = `──────▄▀▄─────▄▀▄
+─────▄█░░▀▀▀▀▀░░█▄
+─▄▄──█░░░░░░░░░░░█──▄▄
+█▄▄█─█░░▀░░┬░░▀░░█─█▄▄█`
+
+// Default returns a fresh copy of the built-in portfolio content.
+func Default() *Content {
+	return &Content{
+		Profile: Profile{
+			Name:  "Anda Toshiki",
+			Title: "Software Engineer",
+			Email: "hi@tosh1ki.de",
+			URL:   "https://toshiki.dev",
+		},
+		Splash: Splash{
+			IntroPrefix:      "Hi! Welcome to ",
+			IntroName:        "Toshiki's",
+			IntroSuffix:      " termfolio, say hi to me!",
+			IntroLink:        "https://toshiki.dev",
+			OpenSourcePrefix: "open sourced on ",
+			OpenSourceLabel:  "github",
+			OpenSourceLink:   "https://github.com/andatoshiki/termfolio",
+		},
+		About: About{
+			Logo: defaultAboutLogo,
+			Intro: "Hey there, I'm **Anda Toshiki** -- call me *kiki* for short (like the protagonist from " +
+				"[Kiki's Delivery Service](https://en.wikipedia.org/wiki/Kiki%27s_Delivery_Service) by Hayao Miyazaki). " +
+				"I'm a *Maho ShouJo* (魔法少女) who loves anime, drinks monster, writes code, documents tutorials, " +
+				"takes photos, eats burgers, and stays up way too late. I'm into clean UI, useful tooling, and " +
+				"anything that makes dev life a little smoother.",
+		},
+		Projects: []Project{
+			{
+				Name: "Toshiki's Homepage",
+				Desc: "All-in-one home landing page/blog/portfolio with a Nuxt 3 rebuild.",
+				Tech: "Nuxt 3, Vue, Vercel, Cloudflare Workers, Netlify",
+				Link: "https://github.com/andatoshiki/toshiki-home-nuxt3",
+			},
+			{
+				Name: "Toshiki's Notebook",
+				Desc: "VitePress-powered web notebook and knowledge base.",
+				Tech: "VitePress, Vercel",
+				Link: "https://note.toshiki.dev",
+			},
+			{
+				Name: "Toshiki's HTTP",
+				Desc: "Playful HTTP status code illustrations with the Ukuku character.",
+				Tech: "Web, API",
+				Link: "https://http.toshiki.dev",
+			},
+			{
+				Name: "Toshiki's Live2D Viewer",
+				Desc: "Simple web Live2D viewer built on Pixi.",
+				Tech: "PixiJS, Live2D",
+				Link: "https://live2d.toshiki.dev",
+			},
+			{
+				Name: "Toshiki's Temple Block",
+				Desc: "Virtual temple block to collect merits with a tap.",
+				Tech: "Web",
+				Link: "https://merit.toshiki.dev",
+			},
+			{
+				Name: "Toshiki's Gallery",
+				Desc: "Self-hosted photo gallery built with Hugo.",
+				Tech: "Hugo",
+				Link: "https://github.com/andatoshiki/toshiki-gallery",
+			},
+			{
+				Name: "Toshiki's Mahjong Calculator",
+				Desc: "Mahjong score calculator for quick scorekeeping.",
+				Tech: "Web",
+				Link: "https://github.com/andatoshiki/toshiki-mahjong-calc",
+			},
+		},
+		Education: []Education{
+			{
+				Role:    "Computer Science, B.S.",
+				Company: "Northwestern",
+				Period:  "2025–present",
+				Desc:    "McCormick School of Engineering",
+				URL:     "https://www.northwestern.edu/",
+			},
+			{
+				Role:    "Asian Languages, Japanese, Minor",
+				Company: "ASU",
+				Period:  "2023–present",
+				Desc:    "W. P. Carey School of Business",
+				URL:     "https://wpcarey.asu.edu/",
+			},
+			{
+				Role:    "Finance & Business, Minor",
+				Company: "ASU",
+				Period:  "2023–present",
+				Desc:    "The College of Liberal Arts and Sciences",
+				URL:     "https://thecollege.asu.edu/",
+			},
+			{
+				Role:    "Data Science, B.S.",
+				Company: "ASU",
+				Period:  "2023–2025",
+				Desc:    "Ira A. Fulton Schools of Engineering",
+				URL:     "https://engineering.asu.edu/",
+			},
+			{
+				Role:    "Computer Science, B.S.",
+				Company: "ASU",
+				Period:  "2023–2025",
+				Desc:    "Ira A. Fulton Schools of Engineering",
+				URL:     "https://engineering.asu.edu/",
+			},
+			{
+				Role:    "Global Launch Intl. Program",
+				Company: "ASU",
+				Period:  "2023–2024",
+				Desc:    "Arizona State University, Global Launch",
+				URL:     "https://www.asu.edu/",
+			},
+		},
+		Experience: []Experience{
+			{
+				Role:    "Software Engineer",
+				Company: "Microsoft",
+				Period:  "2025 - Present",
+				Desc:    "Azure SQL VM team",
+			},
+			{
+				Role:    "Software Engineer Intern",
+				Company: "Jenni AI",
+				Period:  "2024 - 2025",
+				Desc:    "Developed new product that reviews manuscripts for Jenni AI",
+			},
+			{
+				Role:    "Software Engineer Intern",
+				Company: "Blue Origin",
+				Period:  "Fall 2023",
+				Desc:    "New Glenn Rocket Software",
+			},
+		},
+		Contact: Contact{
+			Greeting: "Feel free to reach out!",
+			Groups: []ContactGroup{
+				{
+					Title: "Contacts",
+					Links: []Link{
+						{Label: "Email", Text: "hi@tosh1ki.de", URL: "mailto:hi@tosh1ki.de"},
+						{Label: "Telegram", Text: "@andatoshiki", URL: "https://t.me/@andatoshiki"},
+						{Label: "GitHub", Text: "@andatoshiki", URL: "https://github.com/andatoshiki"},
+					},
+				},
+				{
+					Title: "Social",
+					Links: []Link{
+						{Label: "Twitter(X)", Text: "andatoshiki", URL: "https://x.com/andatoshiki"},
+						{Label: "Mastodon", Text: "@andatoshiki", URL: "https://mastodon.social/@andatoshiki"},
+					},
+				},
+			},
+		},
+	}
+}
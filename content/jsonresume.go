@@ -0,0 +1,421 @@
+package content
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const jsonResumeSchema = "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json"
+
+// jsonResume is the subset of the JSON Resume schema (jsonresume.org) that
+// maps onto portfolio content. Other sections are ignored on import.
+type jsonResume struct {
+	Schema    string                `json:"$schema,omitempty"`
+	Basics    jsonResumeBasics      `json:"basics"`
+	Work      []jsonResumeWork      `json:"work,omitempty"`
+	Education []jsonResumeEducation `json:"education,omitempty"`
+	Projects  []jsonResumeProject   `json:"projects,omitempty"`
+}
+
+type jsonResumeBasics struct {
+	Name     string              `json:"name,omitempty"`
+	Label    string              `json:"label,omitempty"`
+	Email    string              `json:"email,omitempty"`
+	Phone    string              `json:"phone,omitempty"`
+	URL      string              `json:"url,omitempty"`
+	Summary  string              `json:"summary,omitempty"`
+	Location *jsonResumeLocation `json:"location,omitempty"`
+	Profiles []jsonResumeProfile `json:"profiles,omitempty"`
+}
+
+type jsonResumeLocation struct {
+	Address     string `json:"address,omitempty"`
+	City        string `json:"city,omitempty"`
+	Region      string `json:"region,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+}
+
+type jsonResumeProfile struct {
+	Network  string `json:"network,omitempty"`
+	Username string `json:"username,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+type jsonResumeWork struct {
+	Name       string   `json:"name,omitempty"`
+	Position   string   `json:"position,omitempty"`
+	URL        string   `json:"url,omitempty"`
+	StartDate  string   `json:"startDate,omitempty"`
+	EndDate    string   `json:"endDate,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	Highlights []string `json:"highlights,omitempty"`
+}
+
+type jsonResumeEducation struct {
+	Institution string   `json:"institution,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Area        string   `json:"area,omitempty"`
+	StudyType   string   `json:"studyType,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Score       string   `json:"score,omitempty"`
+	Courses     []string `json:"courses,omitempty"`
+}
+
+type jsonResumeProject struct {
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Highlights  []string `json:"highlights,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	URL         string   `json:"url,omitempty"`
+}
+
+// ImportJSONResume overlays a JSON Resume document onto c. Sections the
+// résumé leaves empty keep their current values.
+func ImportJSONResume(data []byte, c *Content) error {
+	var r jsonResume
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+
+	b := r.Basics
+	if b.Name != "" || b.Label != "" || b.Email != "" || b.Phone != "" || b.URL != "" {
+		c.Profile = Profile{
+			Name:     b.Name,
+			Title:    b.Label,
+			Email:    b.Email,
+			Phone:    b.Phone,
+			Location: b.Location.String(),
+			URL:      b.URL,
+		}
+	}
+	if strings.TrimSpace(b.Summary) != "" {
+		c.About.Intro = b.Summary
+	}
+	if groups := jsonResumeContact(b); len(groups) > 0 {
+		c.Contact.Groups = groups
+	}
+
+	if len(r.Work) > 0 {
+		c.Experience = nil
+		for _, w := range r.Work {
+			desc := w.Summary
+			if desc == "" {
+				desc = strings.Join(w.Highlights, "; ")
+			}
+			c.Experience = append(c.Experience, Experience{
+				Role:    w.Position,
+				Company: w.Name,
+				Period:  formatPeriod(w.StartDate, w.EndDate),
+				Desc:    desc,
+			})
+		}
+	}
+
+	if len(r.Education) > 0 {
+		c.Education = nil
+		for _, e := range r.Education {
+			role := e.Area
+			if e.StudyType != "" {
+				role = strings.TrimPrefix(role+", "+e.StudyType, ", ")
+			}
+			desc := strings.Join(e.Courses, ", ")
+			if desc == "" && e.Score != "" {
+				desc = "Score: " + e.Score
+			}
+			if desc == "" {
+				desc = e.URL
+			}
+			c.Education = append(c.Education, Education{
+				Role:    role,
+				Company: e.Institution,
+				Period:  formatPeriod(e.StartDate, e.EndDate),
+				Desc:    desc,
+				URL:     e.URL,
+			})
+		}
+	}
+
+	if len(r.Projects) > 0 {
+		c.Projects = nil
+		for _, p := range r.Projects {
+			var details []string
+			for _, h := range p.Highlights {
+				details = append(details, "- "+h)
+			}
+			year := jsonResumeYear(p.EndDate)
+			if year == "" {
+				year = jsonResumeYear(p.StartDate)
+			}
+			c.Projects = append(c.Projects, Project{
+				Name:    p.Name,
+				Desc:    p.Description,
+				Tech:    strings.Join(p.Keywords, ", "),
+				Link:    p.URL,
+				Details: strings.Join(details, "\n"),
+				Year:    year,
+			})
+		}
+	}
+
+	return nil
+}
+
+// ExportJSONResume converts c into an indented JSON Resume document.
+// Periods are parsed back into ISO 8601 dates where they can be read.
+func ExportJSONResume(c *Content) ([]byte, error) {
+	r := jsonResume{
+		Schema: jsonResumeSchema,
+		Basics: jsonResumeBasics{
+			Name:     c.Profile.Name,
+			Label:    c.Profile.Title,
+			Email:    c.Profile.Email,
+			Phone:    c.Profile.Phone,
+			URL:      c.Profile.URL,
+			Summary:  c.About.Intro,
+			Location: parseLocation(c.Profile.Location),
+		},
+	}
+	for _, g := range c.Contact.Groups {
+		for _, l := range g.Links {
+			if strings.HasPrefix(l.URL, "mailto:") || strings.HasPrefix(l.URL, "tel:") || l.URL == c.Profile.URL {
+				continue
+			}
+			r.Basics.Profiles = append(r.Basics.Profiles, jsonResumeProfile{
+				Network:  l.Label,
+				Username: strings.TrimPrefix(l.Text, "@"),
+				URL:      l.URL,
+			})
+		}
+	}
+
+	for _, e := range c.Experience {
+		start, end := parsePeriod(e.Period)
+		r.Work = append(r.Work, jsonResumeWork{
+			Name:      e.Company,
+			Position:  e.Role,
+			StartDate: start,
+			EndDate:   end,
+			Summary:   e.Desc,
+		})
+	}
+
+	for _, e := range c.Education {
+		start, end := parsePeriod(e.Period)
+		area, studyType := e.Role, ""
+		if i := strings.LastIndex(e.Role, ", "); i >= 0 {
+			area, studyType = e.Role[:i], e.Role[i+2:]
+		}
+		r.Education = append(r.Education, jsonResumeEducation{
+			Institution: e.Company,
+			URL:         e.URL,
+			Area:        area,
+			StudyType:   studyType,
+			StartDate:   start,
+			EndDate:     end,
+		})
+	}
+
+	for _, p := range c.Projects {
+		keywords := p.Tags
+		if len(keywords) == 0 {
+			keywords = splitList(p.Tech)
+		}
+		r.Projects = append(r.Projects, jsonResumeProject{
+			Name:        p.Name,
+			Description: p.Desc,
+			Highlights:  markdownBullets(p.Details),
+			Keywords:    keywords,
+			StartDate:   jsonResumeYear(p.Year),
+			URL:         p.Link,
+		})
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return nil, fmt.Errorf("failed to encode JSON Resume: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// jsonResumeContact builds the contact groups from the résumé's email,
+// phone, website and social profiles.
+func jsonResumeContact(b jsonResumeBasics) []ContactGroup {
+	var contacts, social []Link
+	if b.Email != "" {
+		contacts = append(contacts, Link{Label: "Email", Text: b.Email, URL: "mailto:" + b.Email})
+	}
+	if b.Phone != "" {
+		contacts = append(contacts, Link{Label: "Phone", Text: b.Phone, URL: "tel:" + strings.ReplaceAll(b.Phone, " ", "")})
+	}
+	if b.URL != "" {
+		contacts = append(contacts, Link{Label: "Website", Text: strings.TrimPrefix(strings.TrimPrefix(b.URL, "https://"), "http://"), URL: b.URL})
+	}
+	for _, p := range b.Profiles {
+		if p.URL == "" {
+			continue
+		}
+		text := p.Username
+		if text == "" {
+			text = p.URL
+		}
+		social = append(social, Link{Label: p.Network, Text: text, URL: p.URL})
+	}
+
+	var groups []ContactGroup
+	if len(contacts) > 0 {
+		groups = append(groups, ContactGroup{Title: "Contacts", Links: contacts})
+	}
+	if len(social) > 0 {
+		groups = append(groups, ContactGroup{Title: "Social", Links: social})
+	}
+	return groups
+}
+
+func (l *jsonResumeLocation) String() string {
+	if l == nil {
+		return ""
+	}
+	var parts []string
+	for _, part := range []string{l.City, l.Region, l.CountryCode} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 && l.Address != "" {
+		return l.Address
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseLocation splits a "City, Region, CC" location into its parts.
+func parseLocation(location string) *jsonResumeLocation {
+	parts := splitList(location)
+	if len(parts) == 0 {
+		return nil
+	}
+	l := &jsonResumeLocation{City: parts[0]}
+	rest := parts[1:]
+	if n := len(rest); n > 0 && len(rest[n-1]) == 2 && strings.ToUpper(rest[n-1]) == rest[n-1] {
+		l.CountryCode = rest[n-1]
+		rest = rest[:n-1]
+	}
+	l.Region = strings.Join(rest, ", ")
+	return l
+}
+
+var jsonResumeDateLayouts = []struct {
+	layout string
+	format string
+}{
+	{"2006-01-02", "Jan 2006"},
+	{"2006-01", "Jan 2006"},
+	{"2006", "2006"},
+}
+
+// formatPeriod renders ISO 8601 start and end dates as a period such as
+// "Jan 2023 - Present".
+func formatPeriod(start, end string) string {
+	start, end = formatDate(start), formatDate(end)
+	switch {
+	case start == "":
+		return end
+	case end == "":
+		return start + " - Present"
+	case start == end:
+		return start
+	default:
+		return start + " - " + end
+	}
+}
+
+func formatDate(date string) string {
+	for _, l := range jsonResumeDateLayouts {
+		if t, err := time.Parse(l.layout, date); err == nil {
+			return t.Format(l.format)
+		}
+	}
+	return date
+}
+
+var (
+	periodSeparator = regexp.MustCompile(`\s*[–—]\s*|\s+-\s+|\s+to\s+`)
+	periodYear      = regexp.MustCompile(`\b(19|20)\d{2}\b`)
+)
+
+var periodLayouts = []struct {
+	layout string
+	format string
+}{
+	{"2006-01-02", "2006-01-02"},
+	{"2006-01", "2006-01"},
+	{"2006", "2006"},
+	{"Jan 2006", "2006-01"},
+	{"January 2006", "2006-01"},
+	{"01/2006", "2006-01"},
+}
+
+// parsePeriod reads a free-form period such as "2023–present" or
+// "Aug 2022 - May 2024" back into ISO 8601 dates. An open-ended period has
+// no end date, a single date is both start and end, and anything
+// unreadable is left out.
+func parsePeriod(period string) (start, end string) {
+	parts := periodSeparator.Split(strings.TrimSpace(period), 2)
+	start = parseDate(parts[0])
+	if len(parts) == 1 {
+		return start, start
+	}
+	return start, parseDate(parts[1])
+}
+
+func parseDate(date string) string {
+	date = strings.TrimSpace(date)
+	for _, l := range periodLayouts {
+		if t, err := time.Parse(l.layout, date); err == nil {
+			return t.Format(l.format)
+		}
+	}
+	// Seasons and other loose forms keep only their year.
+	return periodYear.FindString(date)
+}
+
+func jsonResumeYear(date string) string {
+	if len(date) >= 4 && periodYear.MatchString(date[:4]) {
+		return date[:4]
+	}
+	return ""
+}
+
+// markdownBullets returns the items of the bullet lists in src.
+func markdownBullets(src string) []string {
+	var items []string
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		for _, marker := range []string{"- ", "* ", "+ "} {
+			if strings.HasPrefix(line, marker) {
+				items = append(items, strings.TrimSpace(line[len(marker):]))
+				break
+			}
+		}
+	}
+	return items
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,105 @@
+package content
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testJSONResume = `{
+  "basics": {
+    "name": "Jane Doe",
+    "label": "Backend Engineer",
+    "email": "jane@example.com",
+    "url": "https://jane.dev",
+    "summary": "I build **distributed** systems.",
+    "location": {"city": "Berlin", "countryCode": "DE"},
+    "profiles": [{"network": "GitHub", "username": "jane", "url": "https://github.com/jane"}]
+  },
+  "work": [{"name": "Acme", "position": "Engineer", "startDate": "2021-03", "summary": "Payments"}],
+  "education": [{"institution": "TU Berlin", "area": "Computer Science", "studyType": "B.Sc.", "startDate": "2016", "endDate": "2020"}],
+  "projects": [{"name": "queue", "description": "A queue.", "highlights": ["Fast", "Small"], "keywords": ["Go", "Raft"], "startDate": "2022-01-01", "url": "https://github.com/jane/queue"}],
+  "skills": [{"name": "Go"}]
+}`
+
+func TestLoadJSONResume(t *testing.T) {
+	path := writeContent(t, "resume: resume.json\nexperience:\n  - role: Founder\n    company: Own\n")
+	resume := filepath.Join(filepath.Dir(path), "resume.json")
+	if err := os.WriteFile(resume, []byte(testJSONResume), 0o644); err != nil {
+		t.Fatalf("write resume: %v", err)
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if c.Resume != resume {
+		t.Fatalf("resume = %q, want %q", c.Resume, resume)
+	}
+	if c.Profile.Name != "Jane Doe" || c.Profile.Location != "Berlin, DE" || c.Profile.Phone != "" {
+		t.Fatalf("profile = %#v, want imported basics", c.Profile)
+	}
+	if len(c.Experience) != 1 || c.Experience[0].Company != "Own" {
+		t.Fatalf("experience = %#v, want the content file to take precedence", c.Experience)
+	}
+	if e := c.Education[0]; len(c.Education) != 1 || e.Role != "Computer Science, B.Sc." || e.Period != "2016 - 2020" {
+		t.Fatalf("education = %#v, want imported entry", c.Education)
+	}
+	if p := c.Projects[0]; p.Tech != "Go, Raft" || p.Details != "- Fast\n- Small" || p.Year != "2022" {
+		t.Fatalf("project = %#v, want imported entry", p)
+	}
+	if len(c.Contact.Groups) != 2 || c.Contact.Groups[1].Links[0].URL != "https://github.com/jane" {
+		t.Fatalf("contact = %#v, want contacts and social groups", c.Contact.Groups)
+	}
+}
+
+func TestExportJSONResumeRoundTrip(t *testing.T) {
+	c := Default()
+	if err := ImportJSONResume([]byte(testJSONResume), c); err != nil {
+		t.Fatalf("ImportJSONResume() error = %v", err)
+	}
+	data, err := ExportJSONResume(c)
+	if err != nil {
+		t.Fatalf("ExportJSONResume() error = %v", err)
+	}
+
+	var r jsonResume
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatalf("exported JSON does not parse: %v", err)
+	}
+	if w := r.Work[0]; w.StartDate != "2021-03" || w.EndDate != "" || w.Summary != "Payments" {
+		t.Fatalf("work = %#v, want dates parsed back", w)
+	}
+	if e := r.Education[0]; e.Area != "Computer Science" || e.StudyType != "B.Sc." || e.EndDate != "2020" {
+		t.Fatalf("education = %#v, want area and study type split", e)
+	}
+	if p := r.Projects[0]; len(p.Highlights) != 2 || len(p.Keywords) != 2 {
+		t.Fatalf("project = %#v, want highlights and keywords", p)
+	}
+	if len(r.Basics.Profiles) != 1 || r.Basics.Location.CountryCode != "DE" {
+		t.Fatalf("basics = %#v, want one profile and a country code", r.Basics)
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	cases := []struct {
+		period     string
+		start, end string
+	}{
+		{period: "2025–present", start: "2025", end: ""},
+		{period: "2024 - 2025", start: "2024", end: "2025"},
+		{period: "Aug 2022 - May 2024", start: "2022-08", end: "2024-05"},
+		{period: "Fall 2023", start: "2023", end: "2023"},
+		{period: "someday", start: "", end: ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.period, func(t *testing.T) {
+			start, end := parsePeriod(tc.period)
+			if start != tc.start || end != tc.end {
+				t.Fatalf("parsePeriod(%q) = %q, %q, want %q, %q", tc.period, start, end, tc.start, tc.end)
+			}
+		})
+	}
+}
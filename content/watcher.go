@@ -0,0 +1,131 @@
+package content
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+// Loader loads a fresh content set and reports the files it was built from
+// so the watcher knows what to poll next.
+type Loader func() (c *Content, paths []string, err error)
+
+// Watcher polls the files behind a content set and reloads it when any of
+// them change. A failed reload keeps the previous content.
+type Watcher struct {
+	load     Loader
+	interval time.Duration
+
+	mu      sync.RWMutex
+	current *Content
+	stamps  map[string]fileStamp
+}
+
+type fileStamp struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+// NewWatcher performs the initial load and returns a watcher for it.
+func NewWatcher(load Loader, interval time.Duration) (*Watcher, error) {
+	c, paths, err := load()
+	if err != nil {
+		return nil, err
+	}
+	return &Watcher{
+		load:     load,
+		interval: interval,
+		current:  c,
+		stamps:   statAll(paths),
+	}, nil
+}
+
+func (w *Watcher) Current() *Content {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.current
+}
+
+// Poll reloads the content if any watched file changed since the last poll.
+// It returns the new content and true on a successful reload.
+func (w *Watcher) Poll() (*Content, bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	changed := false
+	for path, stamp := range w.stamps {
+		if statFile(path) != stamp {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return w.current, false, nil
+	}
+
+	c, paths, err := w.load()
+	if err != nil {
+		// Remember the broken version so the error is reported once per edit.
+		for path := range w.stamps {
+			w.stamps[path] = statFile(path)
+		}
+		return w.current, false, err
+	}
+
+	w.current = c
+	w.stamps = statAll(paths)
+	return c, true, nil
+}
+
+// Run polls until ctx is cancelled, calling onReload after each successful
+// reload and onError when a changed file fails to load or validate.
+func (w *Watcher) Run(ctx context.Context, onReload func(*Content), onError func(error)) {
+	if w.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c, reloaded, err := w.Poll()
+			if err != nil {
+				if onError != nil {
+					onError(err)
+				}
+				continue
+			}
+			if reloaded && onReload != nil {
+				onReload(c)
+			}
+		}
+	}
+}
+
+func statAll(paths []string) map[string]fileStamp {
+	stamps := make(map[string]fileStamp, len(paths))
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		stamps[path] = statFile(path)
+	}
+	return stamps
+}
+
+func statFile(path string) fileStamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}
+	}
+	return fileStamp{
+		exists:  true,
+		size:    info.Size(),
+		modTime: info.ModTime(),
+	}
+}
@@ -0,0 +1,60 @@
+package content
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWatcherReloadsAndKeepsPreviousOnError(t *testing.T) {
+	path := writeContent(t, "projects:\n  - name: First\n")
+	load := func() (*Content, []string, error) {
+		c, err := Load(path)
+		return c, []string{path}, err
+	}
+
+	w, err := NewWatcher(load, time.Second)
+	if err != nil {
+		t.Fatalf("NewWatcher() error = %v", err)
+	}
+
+	if _, reloaded, err := w.Poll(); reloaded || err != nil {
+		t.Fatalf("Poll() without changes = %v, %v", reloaded, err)
+	}
+
+	rewrite(t, path, "projects:\n  - name: Second\n")
+	c, reloaded, err := w.Poll()
+	if err != nil || !reloaded {
+		t.Fatalf("Poll() after edit = %v, %v", reloaded, err)
+	}
+	if c.Projects[0].Name != "Second" {
+		t.Fatalf("project = %q, want Second", c.Projects[0].Name)
+	}
+
+	rewrite(t, path, "projects:\n  - desc: broken edit\n")
+	if _, reloaded, err := w.Poll(); err == nil || reloaded {
+		t.Fatalf("Poll() after invalid edit = %v, %v, want error", reloaded, err)
+	}
+	if got := w.Current().Projects[0].Name; got != "Second" {
+		t.Fatalf("current project = %q, want previous content kept", got)
+	}
+	if _, _, err := w.Poll(); err != nil {
+		t.Fatalf("Poll() repeated the error for an unchanged file: %v", err)
+	}
+}
+
+var rewrites int
+
+func rewrite(t *testing.T, path string, body string) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("write content: %v", err)
+	}
+	// Push the mtime forward so the change is visible on coarse filesystems.
+	rewrites++
+	future := time.Now().Add(time.Duration(rewrites) * time.Second)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatalf("touch content: %v", err)
+	}
+}
@@ -0,0 +1,266 @@
+package counter
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"modernc.org/sqlite"
+
+	"github.com/andatoshiki/termfolio/metrics"
+)
+
+// driverName is the SQLite driver stores open their database with. It
+// counts the statements that fail in sqliteErrors.
+const driverName = "sqlite-observed"
+
+var sqliteErrors = metrics.NewCounter("termfolio_sqlite_errors_total", "SQLite statements, transactions and row reads that failed.")
+
+func init() {
+	sql.Register(driverName, observedDriver{&sqlite.Driver{}})
+}
+
+// sqliteConn is the part of the SQLite connection database/sql uses.
+type sqliteConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+}
+
+// sqliteStmt is the part of a prepared SQLite statement database/sql uses.
+type sqliteStmt interface {
+	driver.Stmt
+	driver.StmtExecContext
+	driver.StmtQueryContext
+}
+
+// The wrappers forward the optional interfaces database/sql looks for, so
+// observing errors does not change how it talks to the driver.
+var (
+	_ driver.Pinger                         = observedConn{}
+	_ driver.NamedValueChecker              = observedConn{}
+	_ driver.SessionResetter                = observedConn{}
+	_ driver.Validator                      = observedConn{}
+	_ driver.NamedValueChecker              = observedStmt{}
+	_ driver.RowsColumnTypeScanType         = observedRows{}
+	_ driver.RowsColumnTypeDatabaseTypeName = observedRows{}
+	_ driver.RowsColumnTypeLength           = observedRows{}
+	_ driver.RowsColumnTypeNullable         = observedRows{}
+	_ driver.RowsColumnTypePrecisionScale   = observedRows{}
+	_ driver.RowsNextResultSet              = observedRows{}
+)
+
+type observedDriver struct {
+	driver.Driver
+}
+
+type observedConn struct {
+	sqliteConn
+}
+
+type observedStmt struct {
+	sqliteStmt
+	conn observedConn
+}
+
+type observedTx struct {
+	driver.Tx
+}
+
+type observedRows struct {
+	driver.Rows
+}
+
+func (d observedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, observe(err)
+	}
+	c, ok := conn.(sqliteConn)
+	if !ok {
+		_ = conn.Close()
+		return nil, fmt.Errorf("sqlite connection %T lacks context methods", conn)
+	}
+	return observedConn{c}, nil
+}
+
+func (c observedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	tx, err := c.sqliteConn.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, observe(err)
+	}
+	return observedTx{tx}, nil
+}
+
+func (c observedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.sqliteConn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, observe(err)
+	}
+	s, ok := stmt.(sqliteStmt)
+	if !ok {
+		_ = stmt.Close()
+		return nil, fmt.Errorf("sqlite statement %T lacks context methods", stmt)
+	}
+	return observedStmt{s, c}, nil
+}
+
+func (c observedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	result, err := c.sqliteConn.ExecContext(ctx, query, args)
+	return result, observe(err)
+}
+
+func (c observedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.sqliteConn.QueryContext(ctx, query, args)
+	if err != nil {
+		return nil, observe(err)
+	}
+	return observedRows{rows}, nil
+}
+
+func (c observedConn) Ping(ctx context.Context) error {
+	if p, ok := c.sqliteConn.(driver.Pinger); ok {
+		return observe(p.Ping(ctx))
+	}
+	return nil
+}
+
+func (c observedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.sqliteConn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func (c observedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.sqliteConn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c observedConn) IsValid() bool {
+	if v, ok := c.sqliteConn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (s observedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	result, err := s.sqliteStmt.Exec(args)
+	return result, observe(err)
+}
+
+func (s observedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	rows, err := s.sqliteStmt.Query(args)
+	if err != nil {
+		return nil, observe(err)
+	}
+	return observedRows{rows}, nil
+}
+
+func (s observedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	result, err := s.sqliteStmt.ExecContext(ctx, args)
+	return result, observe(err)
+}
+
+func (s observedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := s.sqliteStmt.QueryContext(ctx, args)
+	if err != nil {
+		return nil, observe(err)
+	}
+	return observedRows{rows}, nil
+}
+
+// CheckNamedValue falls back to the connection's checker, which
+// database/sql no longer asks once the statement has one.
+func (s observedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.sqliteStmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return s.conn.CheckNamedValue(nv)
+}
+
+func (t observedTx) Commit() error {
+	return observe(t.Tx.Commit())
+}
+
+func (t observedTx) Rollback() error {
+	return observe(t.Tx.Rollback())
+}
+
+func (r observedRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	if err == io.EOF {
+		return err
+	}
+	return observe(err)
+}
+
+func (r observedRows) ColumnTypeScanType(index int) reflect.Type {
+	if c, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return c.ColumnTypeScanType(index)
+	}
+	return reflect.TypeFor[any]()
+}
+
+func (r observedRows) ColumnTypeDatabaseTypeName(index int) string {
+	if c, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return c.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+func (r observedRows) ColumnTypeLength(index int) (int64, bool) {
+	if c, ok := r.Rows.(driver.RowsColumnTypeLength); ok {
+		return c.ColumnTypeLength(index)
+	}
+	return 0, false
+}
+
+func (r observedRows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	if c, ok := r.Rows.(driver.RowsColumnTypeNullable); ok {
+		return c.ColumnTypeNullable(index)
+	}
+	return false, false
+}
+
+func (r observedRows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	if c, ok := r.Rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return c.ColumnTypePrecisionScale(index)
+	}
+	return 0, 0, false
+}
+
+func (r observedRows) HasNextResultSet() bool {
+	if n, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return n.HasNextResultSet()
+	}
+	return false
+}
+
+func (r observedRows) NextResultSet() error {
+	if n, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		err := n.NextResultSet()
+		if err == io.EOF {
+			return err
+		}
+		return observe(err)
+	}
+	return io.EOF
+}
+
+// observe counts err unless it is nil or asks database/sql to fall back to
+// another method.
+func observe(err error) error {
+	if err != nil && !errors.Is(err, driver.ErrSkip) {
+		sqliteErrors.Inc()
+	}
+	return err
+}
@@ -0,0 +1,95 @@
+package counter
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestSQLiteErrorsCounted(t *testing.T) {
+	store := openTestStore(t)
+
+	cases := []struct {
+		name  string
+		query func() error
+		count uint64
+	}{
+		{
+			name: "ok",
+			query: func() error {
+				_, err := store.db.Exec(`DELETE FROM visitors;`)
+				return err
+			},
+		},
+		{
+			name: "no rows",
+			query: func() error {
+				var id string
+				err := store.db.QueryRow(`SELECT id FROM visitors;`).Scan(&id)
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil
+				}
+				return err
+			},
+		},
+		{
+			name: "ping",
+			query: func() error {
+				return store.db.Ping()
+			},
+		},
+		{
+			name: "prepared statement",
+			query: func() error {
+				stmt, err := store.db.Prepare(`INSERT INTO opt_out (id, opted_out_at) VALUES (?, 0);`)
+				if err != nil {
+					return err
+				}
+				defer stmt.Close()
+				if _, err := stmt.Exec("ip:a"); err != nil {
+					return err
+				}
+				if _, err := stmt.Exec("ip:a"); err == nil {
+					t.Fatalf("Exec() of a duplicate error = nil, want error")
+				}
+				return nil
+			},
+			count: 1,
+		},
+		{
+			name: "prepared query",
+			query: func() error {
+				stmt, err := store.db.Prepare(`SELECT id FROM opt_out WHERE id = ?;`)
+				if err != nil {
+					return err
+				}
+				defer stmt.Close()
+				var id string
+				return stmt.QueryRow("ip:a").Scan(&id)
+			},
+		},
+		{
+			name: "missing table",
+			query: func() error {
+				_, err := store.db.Exec(`DELETE FROM missing;`)
+				if err == nil {
+					t.Fatalf("Exec() error = nil, want error")
+				}
+				return nil
+			},
+			count: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			before := sqliteErrors.Value()
+			if err := tc.query(); err != nil {
+				t.Fatalf("query error = %v", err)
+			}
+			if got := sqliteErrors.Value() - before; got != tc.count {
+				t.Fatalf("sqlite errors = %d, want %d", got, tc.count)
+			}
+		})
+	}
+}
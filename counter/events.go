@@ -0,0 +1,286 @@
+package counter
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Kinds of navigation events.
+const (
+	EventStart = "start"
+	EventEnter = "enter"
+	EventLeave = "leave"
+	EventEnd   = "end"
+)
+
+// Reasons a visit ends, recorded with its end event.
+const (
+	EndQuit       = "quit"
+	EndDisconnect = "disconnect"
+)
+
+// funnelDepth is the number of steps of NavigationStats.Funnel.
+const funnelDepth = 5
+
+// Visit records the navigation of one session as events. They are tied
+// together by a random ID, not to the visitor, and only recorded while the
+// visitor allows tracking. The methods are called from the Bubble Tea
+// update loop, so they only queue the writes, which run in order on their
+// own goroutine. Errors are reported there through onError.
+type Visit struct {
+	store   *Store
+	id      string
+	onError func(error)
+
+	mu       sync.Mutex
+	tracking bool
+	page     string
+	ended    bool
+	// pending holds the writes not yet started; writing is set while a
+	// goroutine drains them.
+	pending []func()
+	writing bool
+}
+
+// PageViews is how often a page was entered and how long visitors stayed.
+type PageViews struct {
+	Page    string
+	Views   int
+	AvgTime time.Duration
+}
+
+// NavigationStats summarise the visits since a point in time.
+type NavigationStats struct {
+	Visits     int
+	AvgVisit   time.Duration
+	Pages      []PageViews
+	EndReasons map[string]int
+	// Funnel counts the visits that entered at least one page, at least
+	// two and so on, so each step shows how many left before the next.
+	Funnel []int
+}
+
+// StartVisit begins recording a session, from now if tracking is allowed.
+func (s *Store) StartVisit(tracking bool, onError func(error)) *Visit {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	v := &Visit{store: s, id: hex.EncodeToString(id), onError: onError}
+	v.SetTracking(tracking)
+	return v
+}
+
+// SetTracking starts or stops recording. Stopping forgets the events of the
+// visit so far; starting records it as a new start on the current page.
+func (v *Visit) SetTracking(tracking bool) {
+	if v == nil {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.ended || tracking == v.tracking {
+		return
+	}
+	v.tracking = tracking
+	if !tracking {
+		v.enqueue(func() {
+			if _, err := v.store.db.Exec(`DELETE FROM events WHERE visit = ?;`, v.id); err != nil {
+				v.onError(fmt.Errorf("forget visit: %w", err))
+			}
+		})
+		return
+	}
+	v.record(EventStart, "", "")
+	if v.page != "" {
+		v.record(EventEnter, v.page, "")
+	}
+}
+
+// Enter records leaving the current page for page.
+func (v *Visit) Enter(page string) {
+	if v == nil {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.ended || page == v.page {
+		return
+	}
+	if v.page != "" {
+		v.record(EventLeave, v.page, "")
+	}
+	v.page = page
+	v.record(EventEnter, page, "")
+}
+
+// End records leaving the current page and the end of the visit, for
+// reason. Only the first call counts.
+func (v *Visit) End(reason string) {
+	if v == nil {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.ended {
+		return
+	}
+	v.ended = true
+	if v.page != "" {
+		v.record(EventLeave, v.page, "")
+	}
+	v.record(EventEnd, "", reason)
+}
+
+// record queues an event while tracking, stamped with the current time.
+// The caller holds mu.
+func (v *Visit) record(kind string, page string, detail string) {
+	if !v.tracking {
+		return
+	}
+	at := time.Now().UnixMilli()
+	v.enqueue(func() {
+		if _, err := v.store.db.Exec(`INSERT INTO events (visit, kind, page, detail, at_ms) VALUES (?, ?, ?, ?, ?);`,
+			v.id, kind, page, detail, at); err != nil {
+			v.onError(fmt.Errorf("record %s event: %w", kind, err))
+		}
+	})
+}
+
+// enqueue schedules write after the writes queued before it, starting a
+// goroutine to run them unless one is already running. The caller holds mu.
+func (v *Visit) enqueue(write func()) {
+	v.pending = append(v.pending, write)
+	if !v.writing {
+		v.writing = true
+		go v.drain()
+	}
+}
+
+// drain runs the pending writes until none are left.
+func (v *Visit) drain() {
+	for {
+		v.mu.Lock()
+		writes := v.pending
+		v.pending = nil
+		if len(writes) == 0 {
+			v.writing = false
+			v.mu.Unlock()
+			return
+		}
+		v.mu.Unlock()
+
+		for _, write := range writes {
+			write()
+		}
+	}
+}
+
+// NavigationStats summarises the visits started since since. Time on a page
+// runs until the next event of the visit, and a visit lasts from its first
+// event to its last.
+func (s *Store) NavigationStats(since time.Time) (NavigationStats, error) {
+	if s == nil || s.db == nil {
+		return NavigationStats{}, fmt.Errorf("counter store is nil")
+	}
+
+	stats := NavigationStats{EndReasons: make(map[string]int)}
+	// visits are those with a start since since, events of older ones are
+	// left out even when they fall in the window
+	const visits = `
+WITH visits AS (SELECT DISTINCT visit FROM events WHERE kind = 'start' AND at_ms >= ?1),
+visit_events AS (SELECT rowid AS seq, * FROM events WHERE visit IN (SELECT visit FROM visits))
+`
+	var avgMillis float64
+	if err := s.db.QueryRow(visits+`
+SELECT COUNT(*), COALESCE(AVG(last - first), 0) FROM (
+	SELECT MIN(at_ms) AS first, MAX(at_ms) AS last FROM visit_events GROUP BY visit
+);
+`, since.UnixMilli()).Scan(&stats.Visits, &avgMillis); err != nil {
+		return NavigationStats{}, fmt.Errorf("read visit lengths: %w", err)
+	}
+	stats.AvgVisit = time.Duration(avgMillis) * time.Millisecond
+
+	rows, err := s.db.Query(visits+`
+SELECT page, COUNT(*) AS views, COALESCE(AVG(next_at - at_ms), 0) FROM (
+	SELECT kind, page, at_ms, LEAD(at_ms) OVER (PARTITION BY visit ORDER BY at_ms, seq) AS next_at FROM visit_events
+)
+WHERE kind = 'enter'
+GROUP BY page
+ORDER BY views DESC, page;
+`, since.UnixMilli())
+	if err != nil {
+		return NavigationStats{}, fmt.Errorf("read page views: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var p PageViews
+		var avg float64
+		if err := rows.Scan(&p.Page, &p.Views, &avg); err != nil {
+			return err
+		}
+		p.AvgTime = time.Duration(avg) * time.Millisecond
+		stats.Pages = append(stats.Pages, p)
+		return nil
+	})
+	if err != nil {
+		return NavigationStats{}, fmt.Errorf("read page views: %w", err)
+	}
+
+	rows, err = s.db.Query(visits+`
+SELECT detail, COUNT(*) FROM visit_events WHERE kind = 'end' GROUP BY detail;
+`, since.UnixMilli())
+	if err != nil {
+		return NavigationStats{}, fmt.Errorf("read end reasons: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var reason string
+		var count int
+		if err := rows.Scan(&reason, &count); err != nil {
+			return err
+		}
+		stats.EndReasons[reason] = count
+		return nil
+	})
+	if err != nil {
+		return NavigationStats{}, fmt.Errorf("read end reasons: %w", err)
+	}
+
+	stats.Funnel = make([]int, funnelDepth)
+	rows, err = s.db.Query(visits+`
+SELECT COUNT(DISTINCT CASE WHEN kind = 'enter' THEN page END) FROM visit_events GROUP BY visit;
+`, since.UnixMilli())
+	if err != nil {
+		return NavigationStats{}, fmt.Errorf("read funnel: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var pages int
+		if err := rows.Scan(&pages); err != nil {
+			return err
+		}
+		for i := 0; i < min(pages, funnelDepth); i++ {
+			stats.Funnel[i]++
+		}
+		return nil
+	})
+	if err != nil {
+		return NavigationStats{}, fmt.Errorf("read funnel: %w", err)
+	}
+	return stats, nil
+}
+
+// PurgeEvents deletes the events older than cutoff and returns how many.
+func (s *Store) PurgeEvents(cutoff time.Time) (int, error) {
+	if s == nil || s.db == nil {
+		return 0, fmt.Errorf("counter store is nil")
+	}
+	result, err := s.db.Exec(`DELETE FROM events WHERE at_ms < ?;`, cutoff.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("purge expired events: %w", err)
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("purge expired events: %w", err)
+	}
+	return int(purged), nil
+}
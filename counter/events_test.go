@@ -0,0 +1,138 @@
+package counter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestVisitEvents(t *testing.T) {
+	store := openTestStore(t)
+	onError := func(err error) { t.Errorf("visit error = %v", err) }
+
+	visit := store.StartVisit(true, onError)
+	visit.Enter("splash")
+	visit.Enter("menu")
+	visit.Enter("menu")
+	visit.End(EndQuit)
+	visit.End(EndDisconnect)
+	visit.Enter("about")
+
+	want := []string{"start/", "enter/splash", "leave/splash", "enter/menu", "leave/menu", "end/quit"}
+	if got := visitEvents(t, store, visit); !reflect.DeepEqual(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+
+	// Opting out forgets the visit, opting back in starts it again
+	optOut := store.StartVisit(true, onError)
+	optOut.Enter("privacy")
+	optOut.SetTracking(false)
+	optOut.Enter("menu")
+	if got := visitEvents(t, store, optOut); len(got) != 0 {
+		t.Fatalf("events after opting out = %v", got)
+	}
+	optOut.SetTracking(true)
+	optOut.End(EndDisconnect)
+	want = []string{"start/", "enter/menu", "leave/menu", "end/disconnect"}
+	if got := visitEvents(t, store, optOut); !reflect.DeepEqual(got, want) {
+		t.Fatalf("events after opting in = %v, want %v", got, want)
+	}
+
+	untracked := store.StartVisit(false, onError)
+	untracked.Enter("splash")
+	untracked.End(EndQuit)
+	if got := visitEvents(t, store, untracked); len(got) != 0 {
+		t.Fatalf("events of an untracked visit = %v", got)
+	}
+}
+
+func TestNavigationStats(t *testing.T) {
+	store := openTestStore(t)
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// a quits at the splash after 10s; b reads about for a minute and
+	// disconnects; c started before the window
+	events := []struct {
+		visit, kind, page, detail string
+		at                        time.Duration
+	}{
+		{"a", EventStart, "", "", 0},
+		{"a", EventEnter, "splash", "", 0},
+		{"a", EventLeave, "splash", "", 10 * time.Second},
+		{"a", EventEnd, "", EndQuit, 10 * time.Second},
+		{"b", EventStart, "", "", 0},
+		{"b", EventEnter, "splash", "", 0},
+		{"b", EventLeave, "splash", "", 2 * time.Second},
+		{"b", EventEnter, "menu", "", 2 * time.Second},
+		{"b", EventLeave, "menu", "", 10 * time.Second},
+		{"b", EventEnter, "about", "", 10 * time.Second},
+		{"b", EventLeave, "about", "", 70 * time.Second},
+		{"b", EventEnd, "", EndDisconnect, 70 * time.Second},
+		{"c", EventStart, "", "", -time.Hour},
+		{"c", EventEnter, "projects", "", -time.Hour},
+	}
+	for _, e := range events {
+		if _, err := store.db.Exec(`INSERT INTO events (visit, kind, page, detail, at_ms) VALUES (?, ?, ?, ?, ?);`,
+			e.visit, e.kind, e.page, e.detail, start.Add(e.at).UnixMilli()); err != nil {
+			t.Fatalf("insert event: %v", err)
+		}
+	}
+
+	stats, err := store.NavigationStats(start)
+	if err != nil {
+		t.Fatalf("NavigationStats() error = %v", err)
+	}
+	if stats.Visits != 2 || stats.AvgVisit != 40*time.Second {
+		t.Fatalf("NavigationStats() visits = %d, avg %s; want 2, 40s", stats.Visits, stats.AvgVisit)
+	}
+	wantPages := []PageViews{
+		{Page: "splash", Views: 2, AvgTime: 6 * time.Second},
+		{Page: "about", Views: 1, AvgTime: time.Minute},
+		{Page: "menu", Views: 1, AvgTime: 8 * time.Second},
+	}
+	if !reflect.DeepEqual(stats.Pages, wantPages) {
+		t.Fatalf("NavigationStats() pages = %+v, want %+v", stats.Pages, wantPages)
+	}
+	if want := map[string]int{EndQuit: 1, EndDisconnect: 1}; !reflect.DeepEqual(stats.EndReasons, want) {
+		t.Fatalf("NavigationStats() end reasons = %v, want %v", stats.EndReasons, want)
+	}
+	if want := []int{2, 1, 1, 0, 0}; !reflect.DeepEqual(stats.Funnel, want) {
+		t.Fatalf("NavigationStats() funnel = %v, want %v", stats.Funnel, want)
+	}
+
+	if purged, err := store.PurgeEvents(start); err != nil || purged != 2 {
+		t.Fatalf("PurgeEvents() = %d, %v; want 2", purged, err)
+	}
+}
+
+func visitEvents(t *testing.T, store *Store, visit *Visit) []string {
+	t.Helper()
+
+	flushVisit(visit)
+	rows, err := store.db.Query(`SELECT kind, page, detail FROM events WHERE visit = ? ORDER BY at_ms, rowid;`, visit.id)
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	var events []string
+	err = scanRows(rows, func() error {
+		var kind, page, detail string
+		if err := rows.Scan(&kind, &page, &detail); err != nil {
+			return err
+		}
+		events = append(events, kind+"/"+page+detail)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("read events: %v", err)
+	}
+	return events
+}
+
+// flushVisit waits until the writes the visit queued so far have run.
+func flushVisit(v *Visit) {
+	done := make(chan struct{})
+	v.mu.Lock()
+	v.enqueue(func() { close(done) })
+	v.mu.Unlock()
+	<-done
+}
@@ -0,0 +1,181 @@
+package counter
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/andatoshiki/termfolio/textutil"
+)
+
+const (
+	GuestbookPending  = "pending"
+	GuestbookApproved = "approved"
+	GuestbookRejected = "rejected"
+
+	GuestbookMaxName    = 32
+	GuestbookMaxMessage = 280
+
+	// guestbookLimit entries per IP are accepted every guestbookWindow.
+	guestbookLimit  = 3
+	guestbookWindow = time.Hour
+)
+
+var (
+	ErrGuestbookName        = fmt.Errorf("name must be 1-%d characters", GuestbookMaxName)
+	ErrGuestbookMessage     = fmt.Errorf("message must be 1-%d characters", GuestbookMaxMessage)
+	ErrGuestbookProfanity   = errors.New("please keep it friendly")
+	ErrGuestbookRateLimited = errors.New("too many messages, try again later")
+	ErrGuestbookNotFound    = errors.New("guestbook entry not found")
+)
+
+// GuestbookEntry is a signed message. New entries are pending until
+// approved.
+type GuestbookEntry struct {
+	ID        int64
+	Name      string
+	Message   string
+	Status    string
+	CreatedAt time.Time
+}
+
+// SignGuestbook stores a pending entry after cleaning and checking it.
+// Errors the visitor can fix are returned as the ErrGuestbook values.
+func (s *Store) SignGuestbook(ip string, name string, message string) (GuestbookEntry, error) {
+	if s == nil || s.db == nil {
+		return GuestbookEntry{}, fmt.Errorf("counter store is nil")
+	}
+
+	name = textutil.Clean(name)
+	message = textutil.Clean(message)
+	if name == "" || utf8.RuneCountInString(name) > GuestbookMaxName {
+		return GuestbookEntry{}, ErrGuestbookName
+	}
+	if message == "" || utf8.RuneCountInString(message) > GuestbookMaxMessage {
+		return GuestbookEntry{}, ErrGuestbookMessage
+	}
+	if containsProfanity(name) || containsProfanity(message) {
+		return GuestbookEntry{}, ErrGuestbookProfanity
+	}
+
+	ipHash := s.hashIP(ip)
+	now := time.Now()
+	var recent int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM guestbook WHERE ip_hash = ? AND created_at > ?;`,
+		ipHash, now.Add(-guestbookWindow).Unix()).Scan(&recent); err != nil {
+		return GuestbookEntry{}, fmt.Errorf("read guestbook rate: %w", err)
+	}
+	if recent >= guestbookLimit {
+		return GuestbookEntry{}, ErrGuestbookRateLimited
+	}
+
+	result, err := s.db.Exec(`
+INSERT INTO guestbook (name, message, ip_hash, status, created_at)
+VALUES (?, ?, ?, ?, ?);
+`, name, message, ipHash, GuestbookPending, now.Unix())
+	if err != nil {
+		return GuestbookEntry{}, fmt.Errorf("sign guestbook: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return GuestbookEntry{}, fmt.Errorf("sign guestbook: %w", err)
+	}
+
+	return GuestbookEntry{
+		ID:        id,
+		Name:      name,
+		Message:   message,
+		Status:    GuestbookPending,
+		CreatedAt: time.Unix(now.Unix(), 0),
+	}, nil
+}
+
+// GuestbookEntries returns a page of entries with the given status, newest
+// first, and the total number of such entries.
+func (s *Store) GuestbookEntries(status string, offset int, limit int) ([]GuestbookEntry, int, error) {
+	if s == nil || s.db == nil {
+		return nil, 0, fmt.Errorf("counter store is nil")
+	}
+
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM guestbook WHERE status = ?;`, status).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count guestbook: %w", err)
+	}
+
+	rows, err := s.db.Query(`
+SELECT id, name, message, status, created_at FROM guestbook
+WHERE status = ?
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?;
+`, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read guestbook: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var entries []GuestbookEntry
+	for rows.Next() {
+		var e GuestbookEntry
+		var created int64
+		if err := rows.Scan(&e.ID, &e.Name, &e.Message, &e.Status, &created); err != nil {
+			return nil, 0, fmt.Errorf("scan guestbook: %w", err)
+		}
+		e.CreatedAt = time.Unix(created, 0)
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate guestbook: %w", err)
+	}
+	return entries, total, nil
+}
+
+// SetGuestbookStatus moderates an entry.
+func (s *Store) SetGuestbookStatus(id int64, status string) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("counter store is nil")
+	}
+	switch status {
+	case GuestbookPending, GuestbookApproved, GuestbookRejected:
+	default:
+		return fmt.Errorf("unknown guestbook status %q", status)
+	}
+
+	result, err := s.db.Exec(`UPDATE guestbook SET status = ? WHERE id = ?;`, status, id)
+	if err != nil {
+		return fmt.Errorf("update guestbook: %w", err)
+	}
+	if !rowsChanged(result) {
+		var exists int
+		if err := s.db.QueryRow(`SELECT 1 FROM guestbook WHERE id = ?;`, id).Scan(&exists); err == sql.ErrNoRows {
+			return ErrGuestbookNotFound
+		}
+	}
+	return nil
+}
+
+var profanity = map[string]bool{
+	"fuck": true, "fucker": true, "fucking": true, "shit": true, "shitty": true,
+	"bitch": true, "cunt": true, "asshole": true, "dick": true, "dickhead": true,
+	"bastard": true, "whore": true, "slut": true, "wanker": true, "twat": true,
+}
+
+// containsProfanity reports whether text has a word from the blocklist,
+// ignoring case and common digit substitutions.
+func containsProfanity(text string) bool {
+	normalize := strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "$", "s", "@", "a")
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '$' && r != '@'
+	})
+	for _, word := range words {
+		if profanity[normalize.Replace(word)] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package counter
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testSalts = Salts{Current: []byte("test salt")}
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	store, err := Open(filepath.Join(t.TempDir(), "visitors.db"), "", testSalts)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestSignGuestbook(t *testing.T) {
+	store := openTestStore(t)
+
+	cases := []struct {
+		name    string
+		visitor string
+		message string
+		want    error
+	}{
+		{name: "ok", visitor: "Ann", message: "Lovely site!"},
+		{name: "empty name", visitor: " \x1b[31m ", message: "hi", want: ErrGuestbookName},
+		{name: "long message", visitor: "Ann", message: strings.Repeat("a", GuestbookMaxMessage+1), want: ErrGuestbookMessage},
+		{name: "profanity", visitor: "Ann", message: "this is sh1t", want: ErrGuestbookProfanity},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := store.SignGuestbook("192.0.2.1", tc.visitor, tc.message)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("SignGuestbook() error = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestGuestbookModerationAndRateLimit(t *testing.T) {
+	store := openTestStore(t)
+
+	var ids []int64
+	for i := 0; i < guestbookLimit; i++ {
+		entry, err := store.SignGuestbook("192.0.2.1", "Ann", "hello")
+		if err != nil {
+			t.Fatalf("SignGuestbook() error = %v", err)
+		}
+		ids = append(ids, entry.ID)
+	}
+	if _, err := store.SignGuestbook("192.0.2.1", "Ann", "again"); !errors.Is(err, ErrGuestbookRateLimited) {
+		t.Fatalf("SignGuestbook() error = %v, want rate limited", err)
+	}
+	if _, err := store.SignGuestbook("192.0.2.2", "Bob", "hi"); err != nil {
+		t.Fatalf("SignGuestbook() from another IP error = %v", err)
+	}
+
+	entries, total, err := store.GuestbookEntries(GuestbookApproved, 0, 10)
+	if err != nil || total != 0 || len(entries) != 0 {
+		t.Fatalf("approved entries = %v, %d, %v, want none before moderation", entries, total, err)
+	}
+
+	for _, id := range ids[:2] {
+		if err := store.SetGuestbookStatus(id, GuestbookApproved); err != nil {
+			t.Fatalf("SetGuestbookStatus() error = %v", err)
+		}
+	}
+	entries, total, err = store.GuestbookEntries(GuestbookApproved, 0, 1)
+	if err != nil || total != 2 || len(entries) != 1 || entries[0].ID != ids[1] {
+		t.Fatalf("approved entries = %v, %d, %v, want newest of two", entries, total, err)
+	}
+	if err := store.SetGuestbookStatus(999, GuestbookApproved); !errors.Is(err, ErrGuestbookNotFound) {
+		t.Fatalf("SetGuestbookStatus(missing) error = %v, want not found", err)
+	}
+}
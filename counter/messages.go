@@ -0,0 +1,383 @@
+package counter
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	xansi "github.com/charmbracelet/x/ansi"
+
+	"github.com/andatoshiki/termfolio/textutil"
+)
+
+const (
+	MessageMaxName    = 64
+	MessageMaxReplyTo = 254
+	MessageMaxBody    = 2000
+
+	// messageLimit messages per IP are accepted every messageWindow.
+	messageLimit  = 5
+	messageWindow = time.Hour
+)
+
+// Delivery states of a message, per sink and overall. A message without
+// sinks is stored only.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+	DeliveryStored    = "stored"
+)
+
+var (
+	ErrMessageName        = fmt.Errorf("name must be 1-%d characters", MessageMaxName)
+	ErrMessageReplyTo     = errors.New("reply-to must be an email address")
+	ErrMessageBody        = fmt.Errorf("message must be 1-%d characters", MessageMaxBody)
+	ErrMessageRateLimited = errors.New("too many messages, try again later")
+	ErrMessageNotFound    = errors.New("message not found")
+)
+
+// Message is a note a visitor sent to the portfolio owner.
+type Message struct {
+	ID        int64
+	Name      string
+	ReplyTo   string
+	Body      string
+	CreatedAt time.Time
+}
+
+// InboxMessage is a message with its overall delivery state, as listed to
+// the owner. LastError is the most recent error of a sink that has not
+// delivered it.
+type InboxMessage struct {
+	Message
+	State     string
+	LastError string
+}
+
+// Delivery is a message waiting to be sent through one sink.
+type Delivery struct {
+	Message
+	Sink     string
+	Attempts int
+}
+
+// MessageStatus summarises the deliveries of a message across its sinks.
+type MessageStatus struct {
+	State       string
+	Attempts    int
+	NextAttempt time.Time
+}
+
+// SaveMessage stores a message after cleaning and checking it, with a
+// pending delivery for each sink. Errors the visitor can fix are returned as
+// the ErrMessage values.
+func (s *Store) SaveMessage(ip string, name string, replyTo string, body string, sinks []string) (Message, error) {
+	if s == nil || s.db == nil {
+		return Message{}, fmt.Errorf("counter store is nil")
+	}
+
+	name = textutil.Clean(name)
+	replyTo = strings.TrimSpace(replyTo)
+	body = cleanMessageBody(body)
+	if name == "" || utf8.RuneCountInString(name) > MessageMaxName {
+		return Message{}, ErrMessageName
+	}
+	if replyTo != "" {
+		addr, err := mail.ParseAddress(replyTo)
+		if err != nil || len(addr.Address) > MessageMaxReplyTo {
+			return Message{}, ErrMessageReplyTo
+		}
+		replyTo = addr.Address
+	}
+	if body == "" || utf8.RuneCountInString(body) > MessageMaxBody {
+		return Message{}, ErrMessageBody
+	}
+
+	ipHash := s.hashIP(ip)
+	now := time.Now()
+	var recent int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM messages WHERE ip_hash = ? AND created_at > ?;`,
+		ipHash, now.Add(-messageWindow).Unix()).Scan(&recent); err != nil {
+		return Message{}, fmt.Errorf("read message rate: %w", err)
+	}
+	if recent >= messageLimit {
+		return Message{}, ErrMessageRateLimited
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return Message{}, fmt.Errorf("begin message tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	result, err := tx.Exec(`
+INSERT INTO messages (name, reply_to, body, ip_hash, created_at)
+VALUES (?, ?, ?, ?, ?);
+`, name, replyTo, body, ipHash, now.Unix())
+	if err != nil {
+		return Message{}, fmt.Errorf("save message: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return Message{}, fmt.Errorf("save message: %w", err)
+	}
+	for _, sink := range sinks {
+		if _, err := tx.Exec(`
+INSERT INTO message_deliveries (message_id, sink, status, attempts, next_attempt_at)
+VALUES (?, ?, ?, 0, ?);
+`, id, sink, DeliveryPending, now.Unix()); err != nil {
+			return Message{}, fmt.Errorf("queue message: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return Message{}, fmt.Errorf("commit message tx: %w", err)
+	}
+
+	return Message{ID: id, Name: name, ReplyTo: replyTo, Body: body, CreatedAt: time.Unix(now.Unix(), 0)}, nil
+}
+
+// DueDeliveries returns pending deliveries whose next attempt is due.
+func (s *Store) DueDeliveries(now time.Time, limit int) ([]Delivery, error) {
+	if s == nil || s.db == nil {
+		return nil, fmt.Errorf("counter store is nil")
+	}
+
+	rows, err := s.db.Query(`
+SELECT m.id, m.name, m.reply_to, m.body, m.created_at, d.sink, d.attempts
+FROM message_deliveries d JOIN messages m ON m.id = d.message_id
+WHERE d.status = ? AND d.next_attempt_at <= ?
+ORDER BY d.next_attempt_at, m.id
+LIMIT ?;
+`, DeliveryPending, now.Unix(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("read deliveries: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var deliveries []Delivery
+	for rows.Next() {
+		var d Delivery
+		var created int64
+		if err := rows.Scan(&d.ID, &d.Name, &d.ReplyTo, &d.Body, &created, &d.Sink, &d.Attempts); err != nil {
+			return nil, fmt.Errorf("scan delivery: %w", err)
+		}
+		d.CreatedAt = time.Unix(created, 0)
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// RecordDelivery stores the outcome of one delivery attempt. A failed
+// attempt is retried at next unless final is set.
+func (s *Store) RecordDelivery(messageID int64, sink string, deliveryErr error, next time.Time, final bool) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("counter store is nil")
+	}
+
+	status, lastError := DeliveryDelivered, ""
+	if deliveryErr != nil {
+		status, lastError = DeliveryPending, deliveryErr.Error()
+		if final {
+			status = DeliveryFailed
+		}
+	}
+	if _, err := s.db.Exec(`
+UPDATE message_deliveries
+SET status = ?, attempts = attempts + 1, last_error = ?, next_attempt_at = ?
+WHERE message_id = ? AND sink = ?;
+`, status, lastError, next.Unix(), messageID, sink); err != nil {
+		return fmt.Errorf("record delivery: %w", err)
+	}
+	return nil
+}
+
+// MessageStatus reports how far delivery of a message has got. It is
+// delivered once every sink succeeded and failed once any sink gave up.
+func (s *Store) MessageStatus(id int64) (MessageStatus, error) {
+	if s == nil || s.db == nil {
+		return MessageStatus{}, fmt.Errorf("counter store is nil")
+	}
+
+	var exists int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM messages WHERE id = ?;`, id).Scan(&exists); err != nil {
+		return MessageStatus{}, fmt.Errorf("read message: %w", err)
+	}
+	if exists == 0 {
+		return MessageStatus{}, ErrMessageNotFound
+	}
+
+	rows, err := s.db.Query(`SELECT status, attempts, next_attempt_at FROM message_deliveries WHERE message_id = ?;`, id)
+	if err != nil {
+		return MessageStatus{}, fmt.Errorf("read message status: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	status := MessageStatus{State: DeliveryStored}
+	pending, failed, total := 0, 0, 0
+	for rows.Next() {
+		var state string
+		var attempts int
+		var next int64
+		if err := rows.Scan(&state, &attempts, &next); err != nil {
+			return MessageStatus{}, fmt.Errorf("scan message status: %w", err)
+		}
+		total++
+		status.Attempts = max(status.Attempts, attempts)
+		switch state {
+		case DeliveryPending:
+			pending++
+			if t := time.Unix(next, 0); status.NextAttempt.IsZero() || t.Before(status.NextAttempt) {
+				status.NextAttempt = t
+			}
+		case DeliveryFailed:
+			failed++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return MessageStatus{}, fmt.Errorf("iterate message status: %w", err)
+	}
+
+	switch {
+	case total == 0:
+		status.State = DeliveryStored
+	case failed > 0:
+		status.State = DeliveryFailed
+	case pending > 0:
+		status.State = DeliveryPending
+	default:
+		status.State = DeliveryDelivered
+	}
+	return status, nil
+}
+
+// Inbox returns a page of messages, newest first, with their delivery
+// state, and the total number of messages.
+func (s *Store) Inbox(offset int, limit int) ([]InboxMessage, int, error) {
+	if s == nil || s.db == nil {
+		return nil, 0, fmt.Errorf("counter store is nil")
+	}
+
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM messages;`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count messages: %w", err)
+	}
+
+	rows, err := s.db.Query(`
+SELECT m.id, m.name, m.reply_to, m.body, m.created_at,
+	COUNT(d.sink),
+	COALESCE(SUM(d.status = ?), 0),
+	COALESCE(SUM(d.status = ?), 0),
+	COALESCE(MAX(CASE WHEN d.status != ? THEN d.last_error END), '')
+FROM messages m LEFT JOIN message_deliveries d ON d.message_id = m.id
+GROUP BY m.id
+ORDER BY m.created_at DESC, m.id DESC
+LIMIT ? OFFSET ?;
+`, DeliveryPending, DeliveryFailed, DeliveryDelivered, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read messages: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var messages []InboxMessage
+	for rows.Next() {
+		var m InboxMessage
+		var created int64
+		var sinks, pending, failed int
+		if err := rows.Scan(&m.ID, &m.Name, &m.ReplyTo, &m.Body, &created, &sinks, &pending, &failed, &m.LastError); err != nil {
+			return nil, 0, fmt.Errorf("scan message: %w", err)
+		}
+		m.CreatedAt = time.Unix(created, 0)
+		switch {
+		case sinks == 0:
+			m.State = DeliveryStored
+		case failed > 0:
+			m.State = DeliveryFailed
+		case pending > 0:
+			m.State = DeliveryPending
+		default:
+			m.State = DeliveryDelivered
+		}
+		messages = append(messages, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate messages: %w", err)
+	}
+	return messages, total, nil
+}
+
+// RetryMessage gives the failed deliveries of a message a fresh set of
+// attempts, starting at now.
+func (s *Store) RetryMessage(id int64, now time.Time) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("counter store is nil")
+	}
+
+	result, err := s.db.Exec(`
+UPDATE message_deliveries
+SET status = ?, attempts = 0, next_attempt_at = ?
+WHERE message_id = ? AND status = ?;
+`, DeliveryPending, now.Unix(), id, DeliveryFailed)
+	if err != nil {
+		return fmt.Errorf("retry message: %w", err)
+	}
+	if !rowsChanged(result) {
+		return ErrMessageNotFound
+	}
+	return nil
+}
+
+// DeleteMessage removes a message and its deliveries.
+func (s *Store) DeleteMessage(id int64) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("counter store is nil")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin delete message tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.Exec(`DELETE FROM message_deliveries WHERE message_id = ?;`, id); err != nil {
+		return fmt.Errorf("delete deliveries: %w", err)
+	}
+	result, err := tx.Exec(`DELETE FROM messages WHERE id = ?;`, id)
+	if err != nil {
+		return fmt.Errorf("delete message: %w", err)
+	}
+	if !rowsChanged(result) {
+		return ErrMessageNotFound
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit delete message tx: %w", err)
+	}
+	return nil
+}
+
+// cleanMessageBody strips escape sequences and control characters like
+// textutil.Clean but keeps line breaks.
+func cleanMessageBody(body string) string {
+	lines := strings.Split(strings.ReplaceAll(xansi.Strip(body), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRightFunc(textutil.Clean(line), unicode.IsSpace)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
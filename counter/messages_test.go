@@ -0,0 +1,143 @@
+package counter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveMessage(t *testing.T) {
+	store := openTestStore(t)
+
+	cases := []struct {
+		name    string
+		from    string
+		replyTo string
+		body    string
+		want    error
+	}{
+		{name: "valid", from: "Ada", replyTo: "Ada <ada@example.org>", body: "hello\r\nthere"},
+		{name: "no reply-to", from: "Ada", body: "hello"},
+		{name: "empty name", from: " \t", body: "hello", want: ErrMessageName},
+		{name: "bad reply-to", from: "Ada", replyTo: "not an address", body: "hello", want: ErrMessageReplyTo},
+		{name: "empty body", from: "Ada", body: "\x1b[2J", want: ErrMessageBody},
+		{name: "long body", from: "Ada", body: strings.Repeat("a", MessageMaxBody+1), want: ErrMessageBody},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := store.SaveMessage("203.0.113.1", tc.from, tc.replyTo, tc.body, nil)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("SaveMessage() error = %v, want %v", err, tc.want)
+			}
+		})
+	}
+
+	msg, err := store.SaveMessage("203.0.113.2", "Ada", "Ada <ada@example.org>", "hello\r\n\x1b[31mthere  \n", []string{"smtp"})
+	if err != nil {
+		t.Fatalf("SaveMessage() error = %v", err)
+	}
+	if msg.ReplyTo != "ada@example.org" || msg.Body != "hello\nthere" {
+		t.Fatalf("message = %+v", msg)
+	}
+}
+
+func TestMessageStatusAndRateLimit(t *testing.T) {
+	store := openTestStore(t)
+
+	stored, err := store.SaveMessage("203.0.113.1", "Ada", "", "hello", nil)
+	if err != nil {
+		t.Fatalf("SaveMessage() error = %v", err)
+	}
+	if status, _ := store.MessageStatus(stored.ID); status.State != DeliveryStored {
+		t.Fatalf("status without sinks = %q, want %q", status.State, DeliveryStored)
+	}
+
+	msg, err := store.SaveMessage("203.0.113.1", "Ada", "", "hello", []string{"smtp", "webhook"})
+	if err != nil {
+		t.Fatalf("SaveMessage() error = %v", err)
+	}
+	now := time.Now()
+	if err := store.RecordDelivery(msg.ID, "smtp", nil, now, false); err != nil {
+		t.Fatalf("RecordDelivery() error = %v", err)
+	}
+	if status, _ := store.MessageStatus(msg.ID); status.State != DeliveryPending {
+		t.Fatalf("status with one sink pending = %q, want %q", status.State, DeliveryPending)
+	}
+	if err := store.RecordDelivery(msg.ID, "webhook", errors.New("boom"), now, true); err != nil {
+		t.Fatalf("RecordDelivery() error = %v", err)
+	}
+	if status, _ := store.MessageStatus(msg.ID); status.State != DeliveryFailed {
+		t.Fatalf("status after giving up = %q, want %q", status.State, DeliveryFailed)
+	}
+	if _, err := store.MessageStatus(msg.ID + 100); !errors.Is(err, ErrMessageNotFound) {
+		t.Fatalf("MessageStatus() error = %v, want %v", err, ErrMessageNotFound)
+	}
+
+	for i := 2; i < messageLimit; i++ {
+		if _, err := store.SaveMessage("203.0.113.1", "Ada", "", "hello", nil); err != nil {
+			t.Fatalf("SaveMessage() #%d error = %v", i+1, err)
+		}
+	}
+	if _, err := store.SaveMessage("203.0.113.1", "Ada", "", "hello", nil); !errors.Is(err, ErrMessageRateLimited) {
+		t.Fatalf("SaveMessage() error = %v, want %v", err, ErrMessageRateLimited)
+	}
+}
+
+func TestInboxRetryAndDelete(t *testing.T) {
+	store := openTestStore(t)
+
+	stored, err := store.SaveMessage("203.0.113.1", "Ada", "", "first", nil)
+	if err != nil {
+		t.Fatalf("SaveMessage() error = %v", err)
+	}
+	failed, err := store.SaveMessage("203.0.113.1", "Bob", "", "second\nline", []string{"smtp", "webhook"})
+	if err != nil {
+		t.Fatalf("SaveMessage() error = %v", err)
+	}
+	now := time.Now()
+	if err := store.RecordDelivery(failed.ID, "smtp", nil, now, false); err != nil {
+		t.Fatalf("RecordDelivery() error = %v", err)
+	}
+	if err := store.RecordDelivery(failed.ID, "webhook", errors.New("502 Bad Gateway"), now, true); err != nil {
+		t.Fatalf("RecordDelivery() error = %v", err)
+	}
+
+	inbox, total, err := store.Inbox(0, 10)
+	if err != nil {
+		t.Fatalf("Inbox() error = %v", err)
+	}
+	if total != 2 || len(inbox) != 2 {
+		t.Fatalf("Inbox() = %d of %d, want 2 of 2", len(inbox), total)
+	}
+	if inbox[0].ID != failed.ID || inbox[0].State != DeliveryFailed || inbox[0].LastError != "502 Bad Gateway" {
+		t.Fatalf("Inbox()[0] = %+v", inbox[0])
+	}
+	if inbox[1].ID != stored.ID || inbox[1].State != DeliveryStored {
+		t.Fatalf("Inbox()[1] = %+v", inbox[1])
+	}
+
+	if err := store.RetryMessage(failed.ID, now); err != nil {
+		t.Fatalf("RetryMessage() error = %v", err)
+	}
+	due, err := store.DueDeliveries(now, 10)
+	if err != nil || len(due) != 1 || due[0].Sink != "webhook" || due[0].Attempts != 0 {
+		t.Fatalf("DueDeliveries() = %+v, %v; want the webhook delivery again", due, err)
+	}
+	if err := store.RetryMessage(stored.ID, now); !errors.Is(err, ErrMessageNotFound) {
+		t.Fatalf("RetryMessage() without failures error = %v, want %v", err, ErrMessageNotFound)
+	}
+
+	if err := store.DeleteMessage(failed.ID); err != nil {
+		t.Fatalf("DeleteMessage() error = %v", err)
+	}
+	if _, err := store.MessageStatus(failed.ID); !errors.Is(err, ErrMessageNotFound) {
+		t.Fatalf("MessageStatus() after delete error = %v, want %v", err, ErrMessageNotFound)
+	}
+	if due, _ := store.DueDeliveries(now, 10); len(due) != 0 {
+		t.Fatalf("DueDeliveries() after delete = %+v", due)
+	}
+	if err := store.DeleteMessage(failed.ID); !errors.Is(err, ErrMessageNotFound) {
+		t.Fatalf("DeleteMessage() twice error = %v, want %v", err, ErrMessageNotFound)
+	}
+}
@@ -0,0 +1,204 @@
+package counter
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// migrations upgrade databases written by older versions, oldest first.
+// PRAGMA user_version counts the ones that have run; new databases are
+// created with the current schema and start after the last.
+var migrations = []func(s *Store, tx *sql.Tx) error{
+	// Visitors and opt-outs are keyed on a visitor ID instead of the IP, so
+	// key fingerprints can be stored too. Existing rows become IP visitors.
+	execMigration(`
+ALTER TABLE visitors RENAME TO visitors_v0;
+CREATE TABLE visitors (
+	id TEXT PRIMARY KEY,
+	ip TEXT NOT NULL,
+	first_seen INTEGER NOT NULL
+);
+INSERT INTO visitors (id, ip, first_seen) SELECT 'ip:' || ip, ip, first_seen FROM visitors_v0;
+DROP TABLE visitors_v0;
+ALTER TABLE opt_out RENAME TO opt_out_v0;
+CREATE TABLE opt_out (
+	id TEXT PRIMARY KEY,
+	opted_out_at INTEGER NOT NULL
+);
+INSERT INTO opt_out (id, opted_out_at) SELECT 'ip:' || ip, opted_out_at FROM opt_out_v0;
+DROP TABLE opt_out_v0;
+`),
+	hashStoredIPs,
+	// Visitors remember their last visit, so returning visitors are counted
+	// once a day and week in the visit series.
+	execMigration(`
+ALTER TABLE visitors ADD COLUMN last_seen INTEGER NOT NULL DEFAULT 0;
+UPDATE visitors SET last_seen = first_seen;
+`),
+}
+
+func execMigration(query string) func(*Store, *sql.Tx) error {
+	return func(_ *Store, tx *sql.Tx) error {
+		_, err := tx.Exec(query)
+		return err
+	}
+}
+
+// migrate brings an existing database up to date. It is a no-op for a new
+// one, which init creates with the current schema.
+func (s *Store) migrate() error {
+	var version int
+	if err := s.db.QueryRow(`PRAGMA user_version;`).Scan(&version); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	var existing int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'visitors';`).Scan(&existing); err != nil {
+		return fmt.Errorf("read schema: %w", err)
+	}
+	if existing == 0 {
+		return nil
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return fmt.Errorf("begin migration %d: %w", i+1, err)
+		}
+		if err := migrations[i](s, tx); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d;`, i+1)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("commit migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// hashStoredIPs replaces every stored IP with its hash. Visitors keep their
+// country, looked up before the IP is dropped; without a GeoLite2 database
+// it stays unknown.
+func hashStoredIPs(s *Store, tx *sql.Tx) error {
+	type visitor struct {
+		id        string
+		ip        string
+		firstSeen int64
+	}
+	var visitors []visitor
+	rows, err := tx.Query(`SELECT id, ip, first_seen FROM visitors;`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var v visitor
+		if err := rows.Scan(&v.id, &v.ip, &v.firstSeen); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		visitors = append(visitors, v)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+DROP TABLE visitors;
+CREATE TABLE visitors (
+	id TEXT PRIMARY KEY,
+	country TEXT NOT NULL DEFAULT '',
+	first_seen INTEGER NOT NULL
+);
+`); err != nil {
+		return err
+	}
+	salt := s.salts().Current
+	for _, v := range visitors {
+		id := v.id
+		if strings.HasPrefix(id, ipIDPrefix) {
+			id = ipIDPrefix + hashIP(salt, strings.TrimPrefix(id, ipIDPrefix))
+		}
+		// Two spellings of one IPv6 address become one visitor
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO visitors (id, country, first_seen) VALUES (?, ?, ?);`,
+			id, s.country(v.ip), v.firstSeen); err != nil {
+			return err
+		}
+	}
+
+	var optOuts []string
+	rows, err = tx.Query(`SELECT id FROM opt_out WHERE id LIKE 'ip:%';`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		optOuts = append(optOuts, id)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	for _, id := range optOuts {
+		hashed := ipIDPrefix + hashIP(salt, strings.TrimPrefix(id, ipIDPrefix))
+		if _, err := tx.Exec(`UPDATE OR IGNORE opt_out SET id = ? WHERE id = ?;`, hashed, id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`DELETE FROM opt_out WHERE id = ?;`, id); err != nil {
+			return err
+		}
+	}
+
+	// Guestbook entries and messages keep the IP only for rate limiting
+	for _, table := range []string{"guestbook", "messages"} {
+		if err := hashColumn(tx, table, salt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hashColumn hashes the ip column of table and renames it ip_hash. Tables
+// from before the feature existed are left for init to create.
+func hashColumn(tx *sql.Tx, table string, salt []byte) error {
+	var existing int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?;`, table).Scan(&existing); err != nil {
+		return err
+	}
+	if existing == 0 {
+		return nil
+	}
+
+	var ips []string
+	rows, err := tx.Query(`SELECT DISTINCT ip FROM ` + table + `;`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var ip string
+		if err := rows.Scan(&ip); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		ips = append(ips, ip)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`ALTER TABLE ` + table + ` RENAME COLUMN ip TO ip_hash;`); err != nil {
+		return err
+	}
+	for _, ip := range ips {
+		if _, err := tx.Exec(`UPDATE `+table+` SET ip_hash = ? WHERE ip_hash = ?;`, hashIP(salt, ip), ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package counter
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// janitorInterval is how often RunJanitor purges expired visitors.
+const janitorInterval = time.Hour
+
+// PurgeVisitors forgets the visitors last seen before cutoff, so returning
+// visitors are kept. Each is added to the anonymous total of the day and
+// country they were first seen, so the visitor count and country stats keep
+// including them. It returns the number of visitors purged.
+func (s *Store) PurgeVisitors(cutoff time.Time) (int, error) {
+	if s == nil || s.db == nil {
+		return 0, fmt.Errorf("counter store is nil")
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin purge tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.Exec(`
+INSERT INTO daily_visitors (day, country, visitors)
+SELECT date(first_seen, 'unixepoch'), country, COUNT(*) FROM visitors
+WHERE last_seen < ?
+GROUP BY 1, 2
+ON CONFLICT (day, country) DO UPDATE SET visitors = visitors + excluded.visitors;
+`, cutoff.Unix()); err != nil {
+		return 0, fmt.Errorf("aggregate expired visitors: %w", err)
+	}
+	result, err := tx.Exec(`DELETE FROM visitors WHERE last_seen < ?;`, cutoff.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("purge expired visitors: %w", err)
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("purge expired visitors: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit purge tx: %w", err)
+	}
+
+	if purged > 0 {
+		s.invalidateStatsCache()
+	}
+	return int(purged), nil
+}
+
+// RunJanitor purges the visitors and navigation events older than retention
+// every hour until ctx is cancelled. Purged visitors are reported through
+// onPurge and errors through onError.
+func (s *Store) RunJanitor(ctx context.Context, retention time.Duration, onPurge func(int), onError func(error)) {
+	ticker := time.NewTicker(janitorInterval)
+	defer ticker.Stop()
+
+	for {
+		cutoff := time.Now().Add(-retention)
+		purged, err := s.PurgeVisitors(cutoff)
+		if err != nil {
+			onError(err)
+		} else if purged > 0 {
+			onPurge(purged)
+		}
+		if _, err := s.PurgeEvents(cutoff); err != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package counter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPurgeVisitors(t *testing.T) {
+	store := openTestStore(t)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	seen := map[string]time.Time{
+		"203.0.113.1": now.AddDate(0, 0, -40),
+		"203.0.113.2": now.AddDate(0, 0, -40).Add(time.Hour),
+		"203.0.113.3": now.AddDate(0, 0, -35),
+		"203.0.113.4": now.AddDate(0, 0, -1),
+	}
+	for ip, at := range seen {
+		v := Visitor{IP: ip}
+		if _, err := store.RecordVisit(v); err != nil {
+			t.Fatalf("RecordVisit(%s) error = %v", ip, err)
+		}
+		if _, err := store.db.Exec(`UPDATE visitors SET first_seen = ?1, last_seen = ?1 WHERE id = ?2;`, at.Unix(), v.ipID(testSalts.Current)); err != nil {
+			t.Fatalf("backdate %s: %v", ip, err)
+		}
+	}
+
+	purged, err := store.PurgeVisitors(now.AddDate(0, 0, -40).Add(30 * time.Minute))
+	if err != nil || purged != 1 {
+		t.Fatalf("PurgeVisitors() = %d, %v; want 1", purged, err)
+	}
+	// The second visitor of the same day is added to its total
+	if purged, err := store.PurgeVisitors(now.AddDate(0, 0, -30)); err != nil || purged != 2 {
+		t.Fatalf("PurgeVisitors() = %d, %v; want 2", purged, err)
+	}
+	if count, _ := store.Count(); count != 4 {
+		t.Fatalf("Count() after purge = %d, want 4", count)
+	}
+
+	var total int
+	day := now.AddDate(0, 0, -40).Format("2006-01-02")
+	if err := store.db.QueryRow(`SELECT visitors FROM daily_visitors WHERE day = ?;`, day).Scan(&total); err != nil || total != 2 {
+		t.Fatalf("daily total of %s = %d, %v; want 2", day, total, err)
+	}
+	var remaining int
+	if err := store.db.QueryRow(`SELECT COUNT(*) FROM visitors;`).Scan(&remaining); err != nil || remaining != 1 {
+		t.Fatalf("visitors after purge = %d, %v; want 1", remaining, err)
+	}
+	if purged, _ := store.PurgeVisitors(now.AddDate(0, 0, -30)); purged != 0 {
+		t.Fatalf("PurgeVisitors() again = %d, want 0", purged)
+	}
+
+	// A purged visitor is not recognised when they return
+	if count, _ := store.RecordVisit(Visitor{IP: "203.0.113.1"}); count != 5 {
+		t.Fatalf("RecordVisit() of a purged visitor = %d, want 5", count)
+	}
+}
+
+func TestPurgeKeepsReturningVisitors(t *testing.T) {
+	store := openTestStore(t)
+	now := time.Now()
+	v := Visitor{IP: "203.0.113.1"}
+	id := v.ipID(testSalts.Current)
+
+	if _, err := store.RecordVisit(v); err != nil {
+		t.Fatalf("RecordVisit() error = %v", err)
+	}
+	// First seen long ago, back yesterday
+	if _, err := store.db.Exec(`UPDATE visitors SET first_seen = ?, last_seen = ? WHERE id = ?;`,
+		now.AddDate(0, 0, -40).Unix(), now.AddDate(0, 0, -1).Unix(), id); err != nil {
+		t.Fatalf("backdate visitor: %v", err)
+	}
+
+	cutoff := now.AddDate(0, 0, -30)
+	if purged, err := store.PurgeVisitors(cutoff); err != nil || purged != 0 {
+		t.Fatalf("PurgeVisitors() = %d, %v; want the returning visitor kept", purged, err)
+	}
+	// Returning again is still one visitor
+	if count, err := store.RecordVisit(v); err != nil || count != 1 {
+		t.Fatalf("RecordVisit() after purge = %d, %v; want 1", count, err)
+	}
+
+	// Once they stay away past the cutoff they are purged
+	if _, err := store.db.Exec(`UPDATE visitors SET last_seen = ? WHERE id = ?;`, now.AddDate(0, 0, -31).Unix(), id); err != nil {
+		t.Fatalf("backdate last visit: %v", err)
+	}
+	if purged, err := store.PurgeVisitors(cutoff); err != nil || purged != 1 {
+		t.Fatalf("PurgeVisitors() = %d, %v; want 1", purged, err)
+	}
+	if count, _ := store.Count(); count != 1 {
+		t.Fatalf("Count() after purge = %d, want 1", count)
+	}
+}
@@ -0,0 +1,125 @@
+package counter
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const saltSize = 32
+
+// Salts are the server secrets IP addresses are hashed with before they are
+// stored. Previous is the salt before the last rotation; rows hashed with it
+// are moved to Current the next time their visitor connects.
+type Salts struct {
+	Current   []byte
+	Previous  []byte
+	RotatedAt time.Time
+}
+
+type saltsFile struct {
+	Current   string    `json:"current"`
+	Previous  string    `json:"previous,omitempty"`
+	RotatedAt time.Time `json:"rotatedAt"`
+}
+
+// LoadSalts reads the salt file at path, creating it with a random salt
+// when it does not exist. When rotation is positive and the current salt is
+// older than that, a new salt replaces it and the file is rewritten.
+func LoadSalts(path string, rotation time.Duration, now time.Time) (Salts, error) {
+	if path == "" {
+		return Salts{}, fmt.Errorf("salt path is empty")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return Salts{}, fmt.Errorf("read salt file: %w", err)
+	}
+
+	var salts Salts
+	if err == nil {
+		var raw saltsFile
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return Salts{}, fmt.Errorf("parse salt file: %w", err)
+		}
+		if salts.Current, err = hex.DecodeString(raw.Current); err != nil || len(salts.Current) == 0 {
+			return Salts{}, fmt.Errorf("parse salt file: invalid current salt")
+		}
+		if salts.Previous, err = hex.DecodeString(raw.Previous); err != nil {
+			return Salts{}, fmt.Errorf("parse salt file: invalid previous salt")
+		}
+		if len(salts.Previous) == 0 {
+			salts.Previous = nil
+		}
+		salts.RotatedAt = raw.RotatedAt
+		if rotation <= 0 || now.Sub(salts.RotatedAt) < rotation {
+			return salts, nil
+		}
+	}
+
+	next := make([]byte, saltSize)
+	if _, err := rand.Read(next); err != nil {
+		return Salts{}, fmt.Errorf("generate salt: %w", err)
+	}
+	salts = Salts{Current: next, Previous: salts.Current, RotatedAt: now}
+	if err := writeSalts(path, salts); err != nil {
+		return Salts{}, err
+	}
+	return salts, nil
+}
+
+// writeSalts replaces the salt file, readable only by its owner, without
+// leaving it half written.
+func writeSalts(path string, salts Salts) error {
+	data, err := json.MarshalIndent(saltsFile{
+		Current:   hex.EncodeToString(salts.Current),
+		Previous:  hex.EncodeToString(salts.Previous),
+		RotatedAt: salts.RotatedAt.UTC(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode salt file: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create salt dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".salt-*")
+	if err != nil {
+		return fmt.Errorf("write salt file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write salt file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write salt file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write salt file: %w", err)
+	}
+	return nil
+}
+
+// hashIP is the keyed hash an IP address is stored as. Addresses are
+// normalised first so every spelling of an IPv6 address hashes the same.
+func hashIP(salt []byte, ip string) string {
+	ip = strings.TrimSpace(ip)
+	if parsed := net.ParseIP(ip); parsed != nil {
+		ip = parsed.String()
+	}
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(ip))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
@@ -0,0 +1,67 @@
+package counter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadSalts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "ip-salt.json")
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	created, err := LoadSalts(path, 24*time.Hour, start)
+	if err != nil {
+		t.Fatalf("LoadSalts() error = %v", err)
+	}
+	if len(created.Current) != saltSize || created.Previous != nil {
+		t.Fatalf("LoadSalts() created %+v", created)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("salt file mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+
+	tests := []struct {
+		name    string
+		rotate  time.Duration
+		now     time.Time
+		rotated bool
+	}{
+		{name: "not due", rotate: 24 * time.Hour, now: start.Add(23 * time.Hour)},
+		{name: "never", rotate: 0, now: start.Add(1000 * time.Hour)},
+		{name: "due", rotate: 24 * time.Hour, now: start.Add(24 * time.Hour), rotated: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			salts, err := LoadSalts(path, tc.rotate, tc.now)
+			if err != nil {
+				t.Fatalf("LoadSalts() error = %v", err)
+			}
+			if !tc.rotated {
+				if !bytes.Equal(salts.Current, created.Current) || !salts.RotatedAt.Equal(start) {
+					t.Fatalf("LoadSalts() = %+v, want the created salt", salts)
+				}
+				return
+			}
+			if bytes.Equal(salts.Current, created.Current) || !bytes.Equal(salts.Previous, created.Current) || !salts.RotatedAt.Equal(tc.now) {
+				t.Fatalf("LoadSalts() = %+v, want a rotated salt", salts)
+			}
+			reloaded, err := LoadSalts(path, tc.rotate, tc.now)
+			if err != nil || !bytes.Equal(reloaded.Current, salts.Current) || !bytes.Equal(reloaded.Previous, salts.Previous) {
+				t.Fatalf("LoadSalts() after rotation = %+v, %v; want the rotated salts", reloaded, err)
+			}
+		})
+	}
+}
+
+func TestHashIP(t *testing.T) {
+	salt := []byte("salt")
+	if hashIP(salt, "2001:db8::1") != hashIP(salt, "2001:0db8:0:0::1") {
+		t.Fatalf("hashIP() differs between spellings of one address")
+	}
+	if hashIP(salt, "203.0.113.1") == hashIP([]byte("other"), "203.0.113.1") {
+		t.Fatalf("hashIP() ignores the salt")
+	}
+}
@@ -6,17 +6,26 @@ import (
 	"net"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/oschwald/geoip2-golang"
-	_ "modernc.org/sqlite"
+
+	"github.com/andatoshiki/termfolio/metrics"
+)
+
+var (
+	visitsRecorded = metrics.NewCounter("termfolio_visits_recorded_total", "Visits counted by the visitor counter.")
+	optOuts        = metrics.NewCounter("termfolio_opt_outs_total", "Visitors who opted out of visit tracking.")
 )
 
 type Store struct {
 	db *sql.DB
 
+	saltMu     sync.RWMutex
+	saltValues Salts
+
 	statsMu     sync.Mutex
 	statsDirty  bool
 	statsPath   string
@@ -36,10 +45,24 @@ type CountryCount struct {
 	Visitors int
 }
 
-func Open(path string) (*Store, error) {
+// OptOut is a visitor who asked not to be counted. ID is their visitor ID,
+// "key:" and the key fingerprint or "ip:" and the hash of the IP.
+type OptOut struct {
+	ID string
+	At time.Time
+}
+
+// Open opens the counter database at path, creating or upgrading it. IPs
+// are stored hashed with salts, and the country of each visitor is looked
+// up in the GeoLite2 database at geoLiteDBPath, if there is one, when they
+// are first counted.
+func Open(path string, geoLiteDBPath string, salts Salts) (*Store, error) {
 	if path == "" {
 		return nil, fmt.Errorf("counter db path is empty")
 	}
+	if len(salts.Current) == 0 {
+		return nil, fmt.Errorf("ip salt is empty")
+	}
 
 	dir := filepath.Dir(path)
 	if dir != "." && dir != "" {
@@ -48,14 +71,16 @@ func Open(path string) (*Store, error) {
 		}
 	}
 
-	db, err := sql.Open("sqlite", path)
+	db, err := sql.Open(driverName, path+connectionParams)
 	if err != nil {
 		return nil, fmt.Errorf("open counter db: %w", err)
 	}
 
 	store := &Store{
 		db:         db,
+		saltValues: salts,
 		statsDirty: true,
+		statsPath:  geoLiteDBPath,
 	}
 	if err := store.init(); err != nil {
 		_ = db.Close()
@@ -65,37 +90,116 @@ func Open(path string) (*Store, error) {
 	return store, nil
 }
 
+// connectionParams let sessions, the janitor and the outbox share the
+// database: writers wait up to five seconds for each other instead of
+// failing with SQLITE_BUSY, readers do not block the writer, and
+// transactions take the write lock when they begin, as one that upgrades
+// from reading later would fail without waiting.
+const connectionParams = "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+
 func (s *Store) init() error {
 	if s == nil || s.db == nil {
 		return fmt.Errorf("counter store is nil")
 	}
 
+	if err := s.migrate(); err != nil {
+		return err
+	}
+
 	_, err := s.db.Exec(`
 CREATE TABLE IF NOT EXISTS visitors (
-	ip TEXT PRIMARY KEY,
-	first_seen INTEGER NOT NULL
+	id TEXT PRIMARY KEY,
+	country TEXT NOT NULL DEFAULT '',
+	first_seen INTEGER NOT NULL,
+	last_seen INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS visitors_first_seen ON visitors (first_seen);
+CREATE INDEX IF NOT EXISTS visitors_last_seen ON visitors (last_seen);
+CREATE TABLE IF NOT EXISTS daily_visitors (
+	day TEXT NOT NULL,
+	country TEXT NOT NULL,
+	visitors INTEGER NOT NULL,
+	PRIMARY KEY (day, country)
+);
+CREATE TABLE IF NOT EXISTS visit_days (
+	day TEXT PRIMARY KEY,
+	visits INTEGER NOT NULL,
+	visitors INTEGER NOT NULL,
+	new_visitors INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS visit_weeks (
+	week TEXT PRIMARY KEY,
+	visitors INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS visit_hours (
+	day TEXT NOT NULL,
+	hour INTEGER NOT NULL,
+	visits INTEGER NOT NULL,
+	PRIMARY KEY (day, hour)
+);
+CREATE TABLE IF NOT EXISTS events (
+	visit TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	page TEXT NOT NULL,
+	detail TEXT NOT NULL,
+	at_ms INTEGER NOT NULL
 );
+CREATE INDEX IF NOT EXISTS events_visit_at ON events (visit, at_ms);
+CREATE INDEX IF NOT EXISTS events_at ON events (at_ms);
 CREATE TABLE IF NOT EXISTS opt_out (
-	ip TEXT PRIMARY KEY,
+	id TEXT PRIMARY KEY,
 	opted_out_at INTEGER NOT NULL
 );
+CREATE TABLE IF NOT EXISTS guestbook (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	message TEXT NOT NULL,
+	ip_hash TEXT NOT NULL,
+	status TEXT NOT NULL,
+	created_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS guestbook_status_created ON guestbook (status, created_at);
+CREATE INDEX IF NOT EXISTS guestbook_ip_created ON guestbook (ip_hash, created_at);
+CREATE TABLE IF NOT EXISTS messages (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	reply_to TEXT NOT NULL,
+	body TEXT NOT NULL,
+	ip_hash TEXT NOT NULL,
+	created_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS messages_ip_created ON messages (ip_hash, created_at);
+CREATE TABLE IF NOT EXISTS message_deliveries (
+	message_id INTEGER NOT NULL REFERENCES messages (id),
+	sink TEXT NOT NULL,
+	status TEXT NOT NULL,
+	attempts INTEGER NOT NULL,
+	last_error TEXT NOT NULL DEFAULT '',
+	next_attempt_at INTEGER NOT NULL,
+	PRIMARY KEY (message_id, sink)
+);
+CREATE INDEX IF NOT EXISTS message_deliveries_due ON message_deliveries (status, next_attempt_at);
 `)
 	if err != nil {
 		return fmt.Errorf("init counter db: %w", err)
 	}
+	if _, err := s.db.Exec(fmt.Sprintf(`PRAGMA user_version = %d;`, len(migrations))); err != nil {
+		return fmt.Errorf("init counter db: %w", err)
+	}
 	return nil
 }
 
-func (s *Store) IsOptedOut(ip string) (bool, error) {
+func (s *Store) IsOptedOut(v Visitor) (bool, error) {
 	if s == nil || s.db == nil {
 		return false, fmt.Errorf("counter store is nil")
 	}
-	if ip == "" {
-		return false, nil
+	id, err := s.visitorID(v)
+	if err != nil || id == "" {
+		return false, err
 	}
 
 	var exists int
-	if err := s.db.QueryRow(`SELECT 1 FROM opt_out WHERE ip = ? LIMIT 1;`, ip).Scan(&exists); err != nil {
+	if err := s.db.QueryRow(`SELECT 1 FROM opt_out WHERE id = ? LIMIT 1;`, id).Scan(&exists); err != nil {
 		if err == sql.ErrNoRows {
 			return false, nil
 		}
@@ -104,12 +208,14 @@ func (s *Store) IsOptedOut(ip string) (bool, error) {
 	return true, nil
 }
 
-func (s *Store) RecordVisit(ip string) (int, error) {
+// RecordVisit counts the visit unless the visitor opted out, and returns the
+// number of unique visitors.
+func (s *Store) RecordVisit(v Visitor) (int, error) {
 	if s == nil || s.db == nil {
 		return 0, fmt.Errorf("counter store is nil")
 	}
 
-	optedOut, err := s.IsOptedOut(ip)
+	optedOut, err := s.IsOptedOut(v)
 	if err != nil {
 		return 0, err
 	}
@@ -117,30 +223,35 @@ func (s *Store) RecordVisit(ip string) (int, error) {
 		return s.Count()
 	}
 
-	if ip != "" {
-		result, err := s.db.Exec(`
-INSERT OR IGNORE INTO visitors (ip, first_seen)
-VALUES (?, strftime('%s','now'));
-`, ip)
-		if err != nil {
-			return 0, fmt.Errorf("record visit: %w", err)
-		}
-		if rowsChanged(result) {
-			s.invalidateStatsCache()
+	if id := v.storedID(s.salts().Current); id != "" {
+		if err := s.recordVisit(id, v.IP, time.Now()); err != nil {
+			return 0, err
 		}
+		visitsRecorded.Inc()
 	}
 
 	return s.Count()
 }
 
-func (s *Store) SetOptOut(ip string, optOut bool) (int, error) {
+// SetOptOut records the visitor's choice and returns the number of unique
+// visitors. Opting out forgets their visit; opting back in counts it.
+func (s *Store) SetOptOut(v Visitor, optOut bool) (int, error) {
 	if s == nil || s.db == nil {
 		return 0, fmt.Errorf("counter store is nil")
 	}
-	if ip == "" {
+	id, err := s.visitorID(v)
+	if err != nil {
+		return 0, err
+	}
+	if id == "" {
 		return s.Count()
 	}
 
+	country := ""
+	if !optOut {
+		country = s.country(v.IP)
+	}
+
 	tx, err := s.db.Begin()
 	if err != nil {
 		return 0, fmt.Errorf("begin privacy tx: %w", err)
@@ -149,27 +260,30 @@ func (s *Store) SetOptOut(ip string, optOut bool) (int, error) {
 		_ = tx.Rollback()
 	}()
 	visitorChanged := false
+	optedOut := false
 
 	if optOut {
-		if _, err := tx.Exec(`
-INSERT OR IGNORE INTO opt_out (ip, opted_out_at)
+		optResult, err := tx.Exec(`
+INSERT OR IGNORE INTO opt_out (id, opted_out_at)
 VALUES (?, strftime('%s','now'));
-`, ip); err != nil {
+`, id)
+		if err != nil {
 			return 0, fmt.Errorf("opt-out insert: %w", err)
 		}
-		delResult, err := tx.Exec(`DELETE FROM visitors WHERE ip = ?;`, ip)
+		optedOut = rowsChanged(optResult)
+		delResult, err := tx.Exec(`DELETE FROM visitors WHERE id = ?;`, id)
 		if err != nil {
 			return 0, fmt.Errorf("opt-out delete: %w", err)
 		}
 		visitorChanged = rowsChanged(delResult)
 	} else {
-		if _, err := tx.Exec(`DELETE FROM opt_out WHERE ip = ?;`, ip); err != nil {
+		if _, err := tx.Exec(`DELETE FROM opt_out WHERE id = ?;`, id); err != nil {
 			return 0, fmt.Errorf("opt-out clear: %w", err)
 		}
 		insResult, err := tx.Exec(`
-INSERT OR IGNORE INTO visitors (ip, first_seen)
-VALUES (?, strftime('%s','now'));
-`, ip)
+INSERT OR IGNORE INTO visitors (id, country, first_seen, last_seen)
+VALUES (?, ?, strftime('%s','now'), strftime('%s','now'));
+`, id, country)
 		if err != nil {
 			return 0, fmt.Errorf("opt-in insert: %w", err)
 		}
@@ -177,7 +291,7 @@ VALUES (?, strftime('%s','now'));
 	}
 
 	var count int
-	if err := tx.QueryRow(`SELECT COUNT(*) FROM visitors;`).Scan(&count); err != nil {
+	if err := tx.QueryRow(countVisitorsQuery).Scan(&count); err != nil {
 		return 0, fmt.Errorf("read counter: %w", err)
 	}
 
@@ -188,121 +302,155 @@ VALUES (?, strftime('%s','now'));
 	if visitorChanged {
 		s.invalidateStatsCache()
 	}
+	if optedOut {
+		optOuts.Inc()
+	}
 
 	return count, nil
 }
 
+// OptOuts returns a page of opt-out records, newest first, and the total
+// number of records.
+func (s *Store) OptOuts(offset int, limit int) ([]OptOut, int, error) {
+	if s == nil || s.db == nil {
+		return nil, 0, fmt.Errorf("counter store is nil")
+	}
+
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM opt_out;`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count opt-outs: %w", err)
+	}
+
+	rows, err := s.db.Query(`
+SELECT id, opted_out_at FROM opt_out
+ORDER BY opted_out_at DESC, id
+LIMIT ? OFFSET ?;
+`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read opt-outs: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var records []OptOut
+	for rows.Next() {
+		var r OptOut
+		var at int64
+		if err := rows.Scan(&r.ID, &at); err != nil {
+			return nil, 0, fmt.Errorf("scan opt-out: %w", err)
+		}
+		r.At = time.Unix(at, 0)
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate opt-outs: %w", err)
+	}
+	return records, total, nil
+}
+
+// DeleteOptOut forgets an opt-out record, by visitor ID, without counting
+// the visitor. The visitor is counted again on their next visit.
+func (s *Store) DeleteOptOut(id string) error {
+	if s == nil || s.db == nil {
+		return fmt.Errorf("counter store is nil")
+	}
+	if _, err := s.db.Exec(`DELETE FROM opt_out WHERE id = ?;`, id); err != nil {
+		return fmt.Errorf("delete opt-out: %w", err)
+	}
+	return nil
+}
+
+// countVisitorsQuery counts the unique visitors, including those purged
+// into daily totals.
+const countVisitorsQuery = `
+SELECT (SELECT COUNT(*) FROM visitors) + (SELECT COALESCE(SUM(visitors), 0) FROM daily_visitors);
+`
+
 func (s *Store) Count() (int, error) {
 	if s == nil || s.db == nil {
 		return 0, fmt.Errorf("counter store is nil")
 	}
 
 	var count int
-	if err := s.db.QueryRow(`SELECT COUNT(*) FROM visitors;`).Scan(&count); err != nil {
+	if err := s.db.QueryRow(countVisitorsQuery).Scan(&count); err != nil {
 		return 0, fmt.Errorf("read counter: %w", err)
 	}
 	return count, nil
 }
 
-func (s *Store) CountryStats(geoLiteDBPath string) (CountryStats, error) {
+// SetSalts replaces the salts IPs are hashed with, after a rotation.
+func (s *Store) SetSalts(salts Salts) {
+	if s == nil || len(salts.Current) == 0 {
+		return
+	}
+	s.saltMu.Lock()
+	s.saltValues = salts
+	s.saltMu.Unlock()
+}
+
+func (s *Store) salts() Salts {
+	s.saltMu.RLock()
+	defer s.saltMu.RUnlock()
+	return s.saltValues
+}
+
+// hashIP is how IPs are stored outside the visitor tables, where they are
+// only compared within the rate limit windows.
+func (s *Store) hashIP(ip string) string {
+	return hashIP(s.salts().Current, ip)
+}
+
+// CountryStats summarises the countries recorded for the visitors. It fails
+// when there is no usable GeoLite2 database, as none have been recorded.
+func (s *Store) CountryStats() (CountryStats, error) {
 	if s == nil || s.db == nil {
 		return CountryStats{}, fmt.Errorf("counter store is nil")
 	}
-	if strings.TrimSpace(geoLiteDBPath) == "" {
-		return CountryStats{}, fmt.Errorf("geolite db path is empty")
-	}
-	if _, err := os.Stat(geoLiteDBPath); err != nil {
-		if os.IsNotExist(err) {
-			return CountryStats{}, fmt.Errorf("geolite db not found")
-		}
-		return CountryStats{}, fmt.Errorf("geolite db is not accessible")
-	}
 
 	s.statsMu.Lock()
 	defer s.statsMu.Unlock()
 
-	if geoLiteDBPath != s.statsPath {
-		if s.statsReader != nil {
-			_ = s.statsReader.Close()
-			s.statsReader = nil
-		}
-		s.statsPath = geoLiteDBPath
-		s.statsDirty = true
+	if _, err := s.geoReader(); err != nil {
+		return CountryStats{}, err
 	}
-
 	if !s.statsDirty {
 		return s.statsCache, nil
 	}
 
-	if s.statsReader == nil {
-		reader, err := geoip2.Open(geoLiteDBPath)
-		if err != nil {
-			return CountryStats{}, fmt.Errorf("geolite db is invalid or unreadable")
-		}
-		s.statsReader = reader
-	}
-
 	totalVisitors, err := s.Count()
 	if err != nil {
 		return CountryStats{}, err
 	}
 
-	rows, err := s.db.Query(`SELECT ip FROM visitors;`)
+	rows, err := s.db.Query(`
+SELECT country, SUM(visitors) AS visitors FROM (
+	SELECT country, 1 AS visitors FROM visitors
+	UNION ALL
+	SELECT country, visitors FROM daily_visitors
+)
+WHERE country != ''
+GROUP BY country
+ORDER BY visitors DESC, country
+LIMIT 5;
+`)
 	if err != nil {
-		return CountryStats{}, fmt.Errorf("query visitor IPs: %w", err)
+		return CountryStats{}, fmt.Errorf("query visitor countries: %w", err)
 	}
 	defer func() {
 		_ = rows.Close()
 	}()
 
-	countryCounts := make(map[string]int)
+	topCountries := make([]CountryCount, 0, 5)
 	for rows.Next() {
-		var ipValue string
-		if err := rows.Scan(&ipValue); err != nil {
-			return CountryStats{}, fmt.Errorf("scan visitor IP: %w", err)
-		}
-
-		parsedIP := net.ParseIP(strings.TrimSpace(ipValue))
-		if parsedIP == nil {
-			continue
-		}
-
-		record, err := s.statsReader.Country(parsedIP)
-		if err != nil || record == nil {
-			continue
+		var c CountryCount
+		if err := rows.Scan(&c.Name, &c.Visitors); err != nil {
+			return CountryStats{}, fmt.Errorf("scan visitor country: %w", err)
 		}
-
-		country := strings.TrimSpace(record.Country.Names["en"])
-		if country == "" {
-			country = strings.TrimSpace(record.Country.IsoCode)
-		}
-		if country == "" {
-			continue
-		}
-		countryCounts[country]++
+		topCountries = append(topCountries, c)
 	}
-
 	if err := rows.Err(); err != nil {
-		return CountryStats{}, fmt.Errorf("iterate visitor IPs: %w", err)
-	}
-
-	topCountries := make([]CountryCount, 0, len(countryCounts))
-	for country, count := range countryCounts {
-		topCountries = append(topCountries, CountryCount{
-			Name:     country,
-			Visitors: count,
-		})
-	}
-
-	sort.Slice(topCountries, func(i, j int) bool {
-		if topCountries[i].Visitors == topCountries[j].Visitors {
-			return topCountries[i].Name < topCountries[j].Name
-		}
-		return topCountries[i].Visitors > topCountries[j].Visitors
-	})
-
-	if len(topCountries) > 5 {
-		topCountries = topCountries[:5]
+		return CountryStats{}, fmt.Errorf("iterate visitor countries: %w", err)
 	}
 
 	topCountry := "N/A"
@@ -316,13 +464,63 @@ func (s *Store) CountryStats(geoLiteDBPath string) (CountryStats, error) {
 		TotalVisitors:      totalVisitors,
 		TopCountry:         topCountry,
 		TopCountryVisitors: topCountryVisitors,
-		TopCountries:       append([]CountryCount(nil), topCountries...),
+		TopCountries:       topCountries,
 	}
 	s.statsCache = stats
 	s.statsDirty = false
 	return stats, nil
 }
 
+// country looks up the country an IP is in, or "" when it is unknown or
+// there is no GeoLite2 database.
+func (s *Store) country(ip string) string {
+	parsedIP := net.ParseIP(strings.TrimSpace(ip))
+	if parsedIP == nil {
+		return ""
+	}
+
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+
+	reader, err := s.geoReader()
+	if err != nil {
+		return ""
+	}
+	record, err := reader.Country(parsedIP)
+	if err != nil || record == nil {
+		return ""
+	}
+
+	country := strings.TrimSpace(record.Country.Names["en"])
+	if country == "" {
+		country = strings.TrimSpace(record.Country.IsoCode)
+	}
+	return country
+}
+
+// geoReader opens the GeoLite2 database the first time it is needed. The
+// caller holds statsMu.
+func (s *Store) geoReader() (*geoip2.Reader, error) {
+	if s.statsReader != nil {
+		return s.statsReader, nil
+	}
+	if strings.TrimSpace(s.statsPath) == "" {
+		return nil, fmt.Errorf("geolite db path is empty")
+	}
+	if _, err := os.Stat(s.statsPath); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("geolite db not found")
+		}
+		return nil, fmt.Errorf("geolite db is not accessible")
+	}
+	reader, err := geoip2.Open(s.statsPath)
+	if err != nil {
+		return nil, fmt.Errorf("geolite db is invalid or unreadable")
+	}
+	s.statsReader = reader
+	return reader, nil
+}
+
 func (s *Store) Close() error {
 	if s == nil || s.db == nil {
 		return nil
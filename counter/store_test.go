@@ -0,0 +1,242 @@
+package counter
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOptOuts(t *testing.T) {
+	store := openTestStore(t)
+
+	for _, ip := range []string{"203.0.113.1", "203.0.113.2"} {
+		if _, err := store.RecordVisit(Visitor{IP: ip}); err != nil {
+			t.Fatalf("RecordVisit(%s) error = %v", ip, err)
+		}
+	}
+	if _, err := store.SetOptOut(Visitor{IP: "203.0.113.2"}, true); err != nil {
+		t.Fatalf("SetOptOut() error = %v", err)
+	}
+
+	records, total, err := store.OptOuts(0, 10)
+	if err != nil {
+		t.Fatalf("OptOuts() error = %v", err)
+	}
+	id := Visitor{IP: "203.0.113.2"}.ipID(testSalts.Current)
+	if total != 1 || len(records) != 1 || records[0].ID != id || records[0].At.IsZero() {
+		t.Fatalf("OptOuts() = %+v, %d", records, total)
+	}
+
+	if err := store.DeleteOptOut(id); err != nil {
+		t.Fatalf("DeleteOptOut() error = %v", err)
+	}
+	if optedOut, _ := store.IsOptedOut(Visitor{IP: "203.0.113.2"}); optedOut {
+		t.Fatalf("IsOptedOut() after DeleteOptOut = true")
+	}
+	if count, _ := store.Count(); count != 1 {
+		t.Fatalf("Count() after DeleteOptOut = %d, want 1 until the next visit", count)
+	}
+}
+
+func TestVisitorsByKey(t *testing.T) {
+	store := openTestStore(t)
+	laptop := Visitor{Fingerprint: "SHA256:laptop", IP: "203.0.113.1"}
+
+	// The same key from another network is the same visitor
+	for _, ip := range []string{"203.0.113.1", "198.51.100.7", "2001:db8::1"} {
+		if _, err := store.RecordVisit(Visitor{Fingerprint: laptop.Fingerprint, IP: ip}); err != nil {
+			t.Fatalf("RecordVisit() error = %v", err)
+		}
+	}
+	// Keyless visitors fall back to their IP, even one a key was seen from
+	if _, err := store.RecordVisit(Visitor{IP: "203.0.113.1"}); err != nil {
+		t.Fatalf("RecordVisit() error = %v", err)
+	}
+	if count, _ := store.Count(); count != 2 {
+		t.Fatalf("Count() = %d, want 2", count)
+	}
+
+	count, err := store.SetOptOut(Visitor{Fingerprint: laptop.Fingerprint, IP: "198.51.100.7"}, true)
+	if err != nil || count != 1 {
+		t.Fatalf("SetOptOut() = %d, %v; want 1", count, err)
+	}
+	if optedOut, _ := store.IsOptedOut(laptop); !optedOut {
+		t.Fatalf("IsOptedOut(key) = false after opting out from another IP")
+	}
+	if optedOut, _ := store.IsOptedOut(Visitor{IP: "203.0.113.1"}); optedOut {
+		t.Fatalf("IsOptedOut(ip) = true, the opt-out belongs to the key")
+	}
+}
+
+func TestIPRowsMoveToKey(t *testing.T) {
+	store := openTestStore(t)
+
+	if _, err := store.RecordVisit(Visitor{IP: "203.0.113.1"}); err != nil {
+		t.Fatalf("RecordVisit() error = %v", err)
+	}
+	if _, err := store.SetOptOut(Visitor{IP: "203.0.113.2"}, true); err != nil {
+		t.Fatalf("SetOptOut() error = %v", err)
+	}
+
+	// A visitor counted by IP who now offers a key is not counted twice
+	count, err := store.RecordVisit(Visitor{Fingerprint: "SHA256:a", IP: "203.0.113.1"})
+	if err != nil || count != 1 {
+		t.Fatalf("RecordVisit() = %d, %v; want 1", count, err)
+	}
+	// but the visit moves only once, as another key behind the same NAT
+	// is someone else
+	count, err = store.RecordVisit(Visitor{Fingerprint: "SHA256:c", IP: "203.0.113.1"})
+	if err != nil || count != 2 {
+		t.Fatalf("RecordVisit() of a second key = %d, %v; want 2", count, err)
+	}
+
+	// An IP opt-out is copied to the key and kept for the IP
+	if optedOut, _ := store.IsOptedOut(Visitor{Fingerprint: "SHA256:b", IP: "203.0.113.2"}); !optedOut {
+		t.Fatalf("IsOptedOut() = false, want the IP opt-out to carry over")
+	}
+	if optedOut, _ := store.IsOptedOut(Visitor{IP: "203.0.113.2"}); !optedOut {
+		t.Fatalf("IsOptedOut(ip) = false after a key was seen from it")
+	}
+	if optedOut, _ := store.IsOptedOut(Visitor{Fingerprint: "SHA256:d", IP: "203.0.113.2"}); !optedOut {
+		t.Fatalf("IsOptedOut() of a second key = false, want the IP opt-out to carry over")
+	}
+	if _, total, _ := store.OptOuts(0, 10); total != 3 {
+		t.Fatalf("OptOuts() total = %d, want 3", total)
+	}
+}
+
+func TestMigrateIPTables(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "visitors.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	now := time.Now().Unix()
+	if _, err := db.Exec(`
+CREATE TABLE visitors (ip TEXT PRIMARY KEY, first_seen INTEGER NOT NULL);
+CREATE TABLE opt_out (ip TEXT PRIMARY KEY, opted_out_at INTEGER NOT NULL);
+CREATE TABLE guestbook (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL, message TEXT NOT NULL, ip TEXT NOT NULL, status TEXT NOT NULL, created_at INTEGER NOT NULL);
+CREATE INDEX guestbook_ip_created ON guestbook (ip, created_at);
+INSERT INTO visitors VALUES ('203.0.113.1', 1700000000), ('203.0.113.2', 1700000001), ('2001:db8::1', 1700000002), ('2001:0db8::0001', 1700000003);
+INSERT INTO opt_out VALUES ('203.0.113.3', 1700000004);
+INSERT INTO guestbook (name, message, ip, status, created_at) VALUES ('Ann', 'hi', '203.0.113.1', 'pending', ?1), ('Ann', 'hi', '203.0.113.1', 'pending', ?1), ('Ann', 'hi', '203.0.113.1', 'pending', ?1);
+`, now); err != nil {
+		t.Fatalf("create v0 schema: %v", err)
+	}
+	_ = db.Close()
+
+	store, err := Open(path, "", testSalts)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	// Both spellings of the IPv6 address are one visitor
+	if count, _ := store.Count(); count != 3 {
+		t.Fatalf("Count() after migration = %d, want 3", count)
+	}
+	if optedOut, _ := store.IsOptedOut(Visitor{IP: "203.0.113.3"}); !optedOut {
+		t.Fatalf("IsOptedOut() after migration = false")
+	}
+	if count, _ := store.RecordVisit(Visitor{IP: "203.0.113.1"}); count != 3 {
+		t.Fatalf("RecordVisit() of a migrated visitor = %d, want 3", count)
+	}
+	if _, err := store.SignGuestbook("203.0.113.1", "Ann", "again"); !errors.Is(err, ErrGuestbookRateLimited) {
+		t.Fatalf("SignGuestbook() after migration error = %v, want rate limited", err)
+	}
+	assertNoRawIPs(t, store, "203.0.113.", "2001:")
+
+	// Opening again must not run the migrations twice
+	_ = store.Close()
+	store, err = Open(path, "", testSalts)
+	if err != nil {
+		t.Fatalf("Open() again error = %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	if count, _ := store.Count(); count != 3 {
+		t.Fatalf("Count() after reopening = %d, want 3", count)
+	}
+}
+
+func TestIPsAreHashed(t *testing.T) {
+	store := openTestStore(t)
+
+	if _, err := store.RecordVisit(Visitor{IP: "203.0.113.1"}); err != nil {
+		t.Fatalf("RecordVisit() error = %v", err)
+	}
+	if _, err := store.SetOptOut(Visitor{IP: "203.0.113.2"}, true); err != nil {
+		t.Fatalf("SetOptOut() error = %v", err)
+	}
+	if _, err := store.SignGuestbook("203.0.113.3", "Ann", "hello"); err != nil {
+		t.Fatalf("SignGuestbook() error = %v", err)
+	}
+	if _, err := store.SaveMessage("203.0.113.4", "Ann", "", "hello", nil); err != nil {
+		t.Fatalf("SaveMessage() error = %v", err)
+	}
+	assertNoRawIPs(t, store, "203.0.113.")
+}
+
+func TestSaltRotation(t *testing.T) {
+	store := openTestStore(t)
+	old := Salts{Current: []byte("old salt")}
+	store.SetSalts(old)
+
+	if _, err := store.RecordVisit(Visitor{IP: "203.0.113.1"}); err != nil {
+		t.Fatalf("RecordVisit() error = %v", err)
+	}
+	if _, err := store.SetOptOut(Visitor{IP: "203.0.113.2"}, true); err != nil {
+		t.Fatalf("SetOptOut() error = %v", err)
+	}
+
+	store.SetSalts(Salts{Current: []byte("new salt"), Previous: old.Current})
+	if count, err := store.RecordVisit(Visitor{IP: "203.0.113.1"}); err != nil || count != 1 {
+		t.Fatalf("RecordVisit() after rotation = %d, %v; want 1", count, err)
+	}
+	if optedOut, _ := store.IsOptedOut(Visitor{IP: "203.0.113.2"}); !optedOut {
+		t.Fatalf("IsOptedOut() after rotation = false")
+	}
+	records, _, _ := store.OptOuts(0, 10)
+	if len(records) != 1 || records[0].ID != (Visitor{IP: "203.0.113.2"}).ipID([]byte("new salt")) {
+		t.Fatalf("OptOuts() = %+v, want the opt-out under the new salt", records)
+	}
+
+	// Two rotations later a visitor is no longer recognised
+	store.SetSalts(Salts{Current: []byte("newer salt"), Previous: []byte("new salt")})
+	store.SetSalts(Salts{Current: []byte("newest salt"), Previous: []byte("newer salt")})
+	if count, _ := store.RecordVisit(Visitor{IP: "203.0.113.1"}); count != 2 {
+		t.Fatalf("RecordVisit() after two rotations = %d, want 2", count)
+	}
+}
+
+// assertNoRawIPs fails when a text column of the store contains one of the
+// prefixes.
+func assertNoRawIPs(t *testing.T, store *Store, prefixes ...string) {
+	t.Helper()
+
+	for _, query := range []string{
+		`SELECT id FROM visitors;`,
+		`SELECT id FROM opt_out;`,
+		`SELECT ip_hash FROM guestbook;`,
+		`SELECT ip_hash FROM messages;`,
+	} {
+		rows, err := store.db.Query(query)
+		if err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		for rows.Next() {
+			var value string
+			if err := rows.Scan(&value); err != nil {
+				t.Fatalf("%s: %v", query, err)
+			}
+			for _, prefix := range prefixes {
+				if strings.Contains(value, prefix) {
+					t.Fatalf("%s returned %q, a raw IP", query, value)
+				}
+			}
+		}
+		_ = rows.Close()
+	}
+}
@@ -0,0 +1,230 @@
+package counter
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const dayFormat = "2006-01-02"
+
+// VisitPeriod is the traffic of a day or a week.
+type VisitPeriod struct {
+	Start  time.Time
+	Visits int
+	// Visitors counts each visitor once per period; NewVisitors of them were
+	// first seen in it.
+	Visitors    int
+	NewVisitors int
+}
+
+// ReturningVisitors are the visitors of the period seen before it.
+func (p VisitPeriod) ReturningVisitors() int {
+	return p.Visitors - p.NewVisitors
+}
+
+// VisitTrends are visit series ending with the current day and week, oldest
+// first. Days and weeks start at midnight UTC, weeks on Monday.
+type VisitTrends struct {
+	Days  []VisitPeriod
+	Weeks []VisitPeriod
+	// Hours are the visits by hour of day, UTC, over Days.
+	Hours [24]int
+}
+
+// recordVisit counts a visit by the visitor stored under id, adding them to
+// the visitors the first time and to the day, week and hour totals.
+func (s *Store) recordVisit(id string, ip string, now time.Time) error {
+	country := s.country(ip)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin visit tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var lastSeen int64
+	err = tx.QueryRow(`SELECT last_seen FROM visitors WHERE id = ?;`, id).Scan(&lastSeen)
+	isNew := err == sql.ErrNoRows
+	if err != nil && !isNew {
+		return fmt.Errorf("read visitor: %w", err)
+	}
+	if isNew {
+		_, err = tx.Exec(`INSERT INTO visitors (id, country, first_seen, last_seen) VALUES (?, ?, ?, ?);`,
+			id, country, now.Unix(), now.Unix())
+	} else {
+		_, err = tx.Exec(`UPDATE visitors SET last_seen = ? WHERE id = ?;`, now.Unix(), id)
+	}
+	if err != nil {
+		return fmt.Errorf("record visit: %w", err)
+	}
+
+	last := time.Unix(lastSeen, 0)
+	newToDay := isNew || dayStart(last) != dayStart(now)
+	newToWeek := isNew || weekStart(last) != weekStart(now)
+	if _, err := tx.Exec(`
+INSERT INTO visit_days (day, visits, visitors, new_visitors) VALUES (?, 1, ?, ?)
+ON CONFLICT (day) DO UPDATE SET
+	visits = visits + 1,
+	visitors = visitors + excluded.visitors,
+	new_visitors = new_visitors + excluded.new_visitors;
+`, dayStart(now).Format(dayFormat), boolInt(newToDay), boolInt(isNew)); err != nil {
+		return fmt.Errorf("record daily visit: %w", err)
+	}
+	if _, err := tx.Exec(`
+INSERT INTO visit_weeks (week, visitors) VALUES (?, ?)
+ON CONFLICT (week) DO UPDATE SET visitors = visitors + excluded.visitors;
+`, weekStart(now).Format(dayFormat), boolInt(newToWeek)); err != nil {
+		return fmt.Errorf("record weekly visit: %w", err)
+	}
+	if _, err := tx.Exec(`
+INSERT INTO visit_hours (day, hour, visits) VALUES (?, ?, 1)
+ON CONFLICT (day, hour) DO UPDATE SET visits = visits + 1;
+`, dayStart(now).Format(dayFormat), now.UTC().Hour()); err != nil {
+		return fmt.Errorf("record hourly visit: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit visit tx: %w", err)
+	}
+	if isNew {
+		s.invalidateStatsCache()
+	}
+	return nil
+}
+
+// VisitTrends returns the last days days and weeks weeks of visits up to
+// now.
+func (s *Store) VisitTrends(now time.Time, days int, weeks int) (VisitTrends, error) {
+	if s == nil || s.db == nil {
+		return VisitTrends{}, fmt.Errorf("counter store is nil")
+	}
+
+	trends := VisitTrends{
+		Days:  make([]VisitPeriod, days),
+		Weeks: make([]VisitPeriod, weeks),
+	}
+	firstDay := dayStart(now).AddDate(0, 0, -(days - 1))
+	for i := range trends.Days {
+		trends.Days[i].Start = firstDay.AddDate(0, 0, i)
+	}
+	firstWeek := weekStart(now).AddDate(0, 0, -7*(weeks-1))
+	for i := range trends.Weeks {
+		trends.Weeks[i].Start = firstWeek.AddDate(0, 0, 7*i)
+	}
+	since := firstDay
+	if firstWeek.Before(since) {
+		since = firstWeek
+	}
+
+	// Weekly visits and new visitors add up the days; only the visitors of
+	// a week are stored apart, as a visitor may return on several days
+	rows, err := s.db.Query(`
+SELECT day, visits, visitors, new_visitors FROM visit_days WHERE day >= ?;
+`, since.Format(dayFormat))
+	if err != nil {
+		return VisitTrends{}, fmt.Errorf("read daily visits: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var day string
+		var p VisitPeriod
+		if err := rows.Scan(&day, &p.Visits, &p.Visitors, &p.NewVisitors); err != nil {
+			return err
+		}
+		start, err := time.Parse(dayFormat, day)
+		if err != nil {
+			return err
+		}
+		if i := int(start.Sub(firstDay).Hours() / 24); i >= 0 && i < days {
+			trends.Days[i].Visits = p.Visits
+			trends.Days[i].Visitors = p.Visitors
+			trends.Days[i].NewVisitors = p.NewVisitors
+		}
+		if i := int(weekStart(start).Sub(firstWeek).Hours() / (24 * 7)); i >= 0 && i < weeks {
+			trends.Weeks[i].Visits += p.Visits
+			trends.Weeks[i].NewVisitors += p.NewVisitors
+		}
+		return nil
+	})
+	if err != nil {
+		return VisitTrends{}, fmt.Errorf("read daily visits: %w", err)
+	}
+
+	rows, err = s.db.Query(`SELECT week, visitors FROM visit_weeks WHERE week >= ?;`, firstWeek.Format(dayFormat))
+	if err != nil {
+		return VisitTrends{}, fmt.Errorf("read weekly visitors: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var week string
+		var visitors int
+		if err := rows.Scan(&week, &visitors); err != nil {
+			return err
+		}
+		start, err := time.Parse(dayFormat, week)
+		if err != nil {
+			return err
+		}
+		if i := int(start.Sub(firstWeek).Hours() / (24 * 7)); i >= 0 && i < weeks {
+			trends.Weeks[i].Visitors = visitors
+		}
+		return nil
+	})
+	if err != nil {
+		return VisitTrends{}, fmt.Errorf("read weekly visitors: %w", err)
+	}
+
+	rows, err = s.db.Query(`
+SELECT hour, SUM(visits) FROM visit_hours WHERE day >= ? GROUP BY hour;
+`, firstDay.Format(dayFormat))
+	if err != nil {
+		return VisitTrends{}, fmt.Errorf("read hourly visits: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var hour, visits int
+		if err := rows.Scan(&hour, &visits); err != nil {
+			return err
+		}
+		if hour >= 0 && hour < len(trends.Hours) {
+			trends.Hours[hour] = visits
+		}
+		return nil
+	})
+	if err != nil {
+		return VisitTrends{}, fmt.Errorf("read hourly visits: %w", err)
+	}
+	return trends, nil
+}
+
+// scanRows calls scan for each row and closes rows.
+func scanRows(rows *sql.Rows, scan func() error) error {
+	defer func() {
+		_ = rows.Close()
+	}()
+	for rows.Next() {
+		if err := scan(); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// dayStart is midnight UTC of the day t falls on.
+func dayStart(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// weekStart is midnight UTC of the Monday of the week t falls on.
+func weekStart(t time.Time) time.Time {
+	day := dayStart(t)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,98 @@
+package counter
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestVisitTrends(t *testing.T) {
+	store := openTestStore(t)
+	// Wednesday; the week started on Monday the 9th
+	now := time.Date(2026, 3, 11, 15, 30, 0, 0, time.UTC)
+
+	visits := []struct {
+		id string
+		at time.Time
+	}{
+		{"key:a", now.AddDate(0, 0, -7)},                  // previous week
+		{"key:a", now.Add(-26 * time.Hour)},               // Tuesday, returning
+		{"key:a", now.Add(-25 * time.Hour)},               // Tuesday again
+		{"key:a", now.Add(-time.Hour)},                    // today, same week
+		{"key:b", now.Add(-30 * time.Minute)},             // today, new
+		{"key:c", now.AddDate(0, 0, -2).Add(time.Minute)}, // Monday, new
+	}
+	for _, v := range visits {
+		if err := store.recordVisit(v.id, "", v.at); err != nil {
+			t.Fatalf("recordVisit(%s, %s) error = %v", v.id, v.at, err)
+		}
+	}
+
+	trends, err := store.VisitTrends(now, 3, 2)
+	if err != nil {
+		t.Fatalf("VisitTrends() error = %v", err)
+	}
+
+	wantDays := []VisitPeriod{
+		{Start: time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), Visits: 1, Visitors: 1, NewVisitors: 1},
+		{Start: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), Visits: 2, Visitors: 1},
+		{Start: time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC), Visits: 2, Visitors: 2, NewVisitors: 1},
+	}
+	if len(trends.Days) != len(wantDays) {
+		t.Fatalf("VisitTrends() days = %+v", trends.Days)
+	}
+	for i, want := range wantDays {
+		if got := trends.Days[i]; got != want {
+			t.Fatalf("day %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	wantWeeks := []VisitPeriod{
+		{Start: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), Visits: 1, Visitors: 1, NewVisitors: 1},
+		// a visited on two days this week and counts once
+		{Start: time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), Visits: 5, Visitors: 3, NewVisitors: 2},
+	}
+	for i, want := range wantWeeks {
+		if got := trends.Weeks[i]; got != want {
+			t.Fatalf("week %d = %+v, want %+v", i, got, want)
+		}
+	}
+	if got := trends.Weeks[1].ReturningVisitors(); got != 1 {
+		t.Fatalf("ReturningVisitors() = %d, want 1", got)
+	}
+
+	// Hours cover the three days only
+	if want := [24]int{13: 1, 14: 2, 15: 2}; trends.Hours != want {
+		t.Fatalf("VisitTrends() hours = %v", trends.Hours)
+	}
+}
+
+func TestConcurrentVisits(t *testing.T) {
+	store := openTestStore(t)
+
+	const workers, visits = 5, 20
+	errs := make(chan error, workers*visits)
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range visits {
+				v := Visitor{IP: fmt.Sprintf("203.0.113.%d", w*visits+i)}
+				if _, err := store.RecordVisit(v); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	if err, failed := <-errs; failed {
+		t.Fatalf("RecordVisit() under contention error = %v, and %d more", err, len(errs))
+	}
+	if count, err := store.Count(); err != nil || count != workers*visits {
+		t.Fatalf("Count() = %d, %v; want %d", count, err, workers*visits)
+	}
+}
@@ -0,0 +1,146 @@
+package counter
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	keyIDPrefix = "key:"
+	ipIDPrefix  = "ip:"
+)
+
+// Visitor identifies a connection for counting and opt-outs: by the
+// fingerprint of the public key the client offered, or by IP without one.
+type Visitor struct {
+	// Fingerprint is the SHA256 fingerprint of the client's public key.
+	Fingerprint string
+	IP          string
+}
+
+// ID tells visitors apart within the running server, or is "" when neither
+// the key nor the IP is known. The store never keeps it as is: IPs are
+// stored hashed.
+func (v Visitor) ID() string {
+	if v.Fingerprint != "" {
+		return keyIDPrefix + v.Fingerprint
+	}
+	if v.IP == "" {
+		return ""
+	}
+	return ipIDPrefix + v.IP
+}
+
+// storedID is the ID the visitor's rows are stored under with salt.
+func (v Visitor) storedID(salt []byte) string {
+	if v.Fingerprint != "" {
+		return keyIDPrefix + v.Fingerprint
+	}
+	return v.ipID(salt)
+}
+
+func (v Visitor) ipID(salt []byte) string {
+	if v.IP == "" || len(salt) == 0 {
+		return ""
+	}
+	return ipIDPrefix + hashIP(salt, v.IP)
+}
+
+// visitorID returns the ID the visitor's rows are stored under. The first
+// time it is seen, rows recorded under an older ID of the same visitor are
+// taken over, so they are not counted twice and keep their opt-out: those
+// hashed with the salt before the last rotation, and for a key, those
+// recorded under its IP before keys were recognised. Other people behind
+// the same NAT share that IP, so a key takes its visit only once and copies
+// its opt-out, leaving the IP opted out too.
+func (s *Store) visitorID(v Visitor) (string, error) {
+	salts := s.salts()
+	id := v.storedID(salts.Current)
+	if id == "" || v.IP == "" {
+		return id, nil
+	}
+
+	var older []string
+	if v.Fingerprint != "" {
+		older = append(older, v.ipID(salts.Current))
+	}
+	if previous := v.ipID(salts.Previous); previous != "" {
+		older = append(older, previous)
+	}
+	if len(older) == 0 {
+		return id, nil
+	}
+
+	// Most lookups find nothing to take over, so they only read
+	if adopt, err := needsAdoption(s.db, id, older); err != nil || !adopt {
+		return id, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("begin adopt tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	// Another session of the visitor may have got here first
+	if adopt, err := needsAdoption(tx, id, older); err != nil || !adopt {
+		return id, err
+	}
+	queries := []string{
+		`UPDATE OR IGNORE visitors SET id = ?1 WHERE id = ?2;`,
+		`DELETE FROM visitors WHERE id = ?2;`,
+		`UPDATE OR IGNORE opt_out SET id = ?1 WHERE id = ?2;`,
+		`DELETE FROM opt_out WHERE id = ?2;`,
+	}
+	if v.Fingerprint != "" {
+		queries = []string{
+			`UPDATE OR IGNORE visitors SET id = ?1 WHERE id = ?2;`,
+			`DELETE FROM visitors WHERE id = ?2;`,
+			`INSERT OR IGNORE INTO opt_out (id, opted_out_at) SELECT ?1, opted_out_at FROM opt_out WHERE id = ?2;`,
+		}
+	}
+	// An IP seen both before and after a rotation is one visitor, so only
+	// the first of its rows is kept
+	for _, old := range older {
+		for _, query := range queries {
+			if _, err := tx.Exec(query, id, old); err != nil {
+				return "", fmt.Errorf("adopt visitor: %w", err)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("commit adopt tx: %w", err)
+	}
+	s.invalidateStatsCache()
+	return id, nil
+}
+
+type queryer interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// needsAdoption reports whether id has no rows yet while one of the older
+// IDs does.
+func needsAdoption(q queryer, id string, older []string) (bool, error) {
+	if known, err := hasRows(q, id); err != nil || known {
+		return false, err
+	}
+	for _, old := range older {
+		if stale, err := hasRows(q, old); err != nil || stale {
+			return stale, err
+		}
+	}
+	return false, nil
+}
+
+// hasRows reports whether a visit or opt-out is stored under id.
+func hasRows(q queryer, id string) (bool, error) {
+	var exists bool
+	if err := q.QueryRow(`
+SELECT EXISTS (SELECT 1 FROM visitors WHERE id = ?1) OR EXISTS (SELECT 1 FROM opt_out WHERE id = ?1);
+`, id).Scan(&exists); err != nil {
+		return false, fmt.Errorf("read visitor: %w", err)
+	}
+	return exists, nil
+}
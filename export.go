@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/andatoshiki/termfolio/content"
+)
+
+// exportResume implements the export-resume subcommand, which writes the
+// portfolio content as a JSON Resume document.
+func exportResume(args []string) error {
+	flags := flag.NewFlagSet("export-resume", flag.ExitOnError)
+	configPath := flags.String("c", "config.yaml", "Path to configuration file")
+	user := flags.String("u", "", "Export the portfolio of this tenant username")
+	output := flags.String("o", "", "Write to this file instead of stdout")
+	flags.Parse(args)
+
+	userProvidedPath := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "c" {
+			userProvidedPath = true
+		}
+	})
+
+	portfolio, _, err := contentLoader(*configPath, userProvidedPath, *user)()
+	if err != nil {
+		return err
+	}
+	data, err := content.ExportJSONResume(portfolio)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*output, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write JSON Resume to %s: %w", *output, err)
+	}
+	return nil
+}
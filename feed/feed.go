@@ -0,0 +1,123 @@
+// Package feed fetches the RSS feed shown on the Feed page.
+package feed
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+
+	"github.com/andatoshiki/termfolio/metrics"
+)
+
+const (
+	URL          = "https://note.toshiki.dev/feed.xml"
+	FetchTimeout = 5 * time.Second
+	CacheTTL     = 15 * time.Minute
+	MaxItems     = 25
+)
+
+var (
+	fetchDuration = metrics.NewHistogram("termfolio_feed_fetch_duration_seconds", "Time taken to download and parse the feed.", metrics.DurationBuckets)
+	fetchErrors   = metrics.NewCounter("termfolio_feed_fetch_errors_total", "Feed fetches that failed.")
+)
+
+type Item struct {
+	Title string
+	Link  string
+	Date  string
+}
+
+// Fetch downloads and parses the feed, returning at most MaxItems entries.
+func Fetch(ctx context.Context) ([]Item, error) {
+	start := time.Now()
+	items, err := fetch(ctx)
+	fetchDuration.ObserveSince(start)
+	if err != nil {
+		fetchErrors.Inc()
+	}
+	return items, err
+}
+
+func fetch(ctx context.Context) ([]Item, error) {
+	ctx, cancel := context.WithTimeout(ctx, FetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("feed request failed: %s", resp.Status)
+	}
+
+	parser := gofeed.NewParser()
+	parsed, err := parser.Parse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(parsed.Items))
+	for _, item := range parsed.Items {
+		if item == nil {
+			continue
+		}
+		title := normalizeText(item.Title)
+		if title == "" {
+			title = "Untitled"
+		}
+		link := normalizeText(item.Link)
+		if link == "" {
+			link = normalizeText(parsed.Link)
+		}
+		date := ""
+		if item.PublishedParsed != nil {
+			date = item.PublishedParsed.Format("01-02-2006")
+		} else if item.UpdatedParsed != nil {
+			date = item.UpdatedParsed.Format("01-02-2006")
+		}
+		items = append(items, Item{
+			Title: title,
+			Link:  link,
+			Date:  date,
+		})
+		if len(items) >= MaxItems {
+			break
+		}
+	}
+
+	return items, nil
+}
+
+func normalizeText(value string) string {
+	if value == "" {
+		return value
+	}
+	value = unescapeHTMLEntities(value)
+	value = strings.ReplaceAll(value, "\u00a0", " ")
+	value = strings.ReplaceAll(value, "\u200b", "")
+	return value
+}
+
+func unescapeHTMLEntities(value string) string {
+	const maxPasses = 3
+	for i := 0; i < maxPasses; i++ {
+		unescaped := html.UnescapeString(value)
+		if unescaped == value {
+			break
+		}
+		value = unescaped
+	}
+	return value
+}
@@ -0,0 +1,72 @@
+// Package files generates the documents visitors can download over SCP and
+// SFTP, such as a résumé and vCard, from the portfolio content.
+package files
+
+import (
+	"io/fs"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andatoshiki/termfolio/content"
+)
+
+const (
+	ResumePDF     = "resume.pdf"
+	ResumeText    = "resume.txt"
+	ContactCard   = "contact.vcf"
+	AboutMarkdown = "about.md"
+)
+
+// resumeTextWidth is the column width of resume.txt.
+const resumeTextWidth = 78
+
+// New returns a read-only filesystem holding the generated documents for c,
+// all stamped with modTime.
+func New(c *content.Content, modTime time.Time) fs.FS {
+	r := buildResume(c)
+	return &memFS{
+		files: map[string][]byte{
+			ResumePDF:     resumePDF(r),
+			ResumeText:    []byte(resumeText(r, resumeTextWidth)),
+			ContactCard:   vCard(c),
+			AboutMarkdown: aboutMarkdown(c),
+		},
+		modTime: modTime,
+	}
+}
+
+// Cache holds the filesystem generated for the latest content, so the
+// documents are built once per content reload instead of per download.
+type Cache struct {
+	mu      sync.Mutex
+	content *content.Content
+	fsys    fs.FS
+}
+
+// FS returns the documents for c, generating them only when c is not the
+// content of the previous call. A reload replaces the content pointer, so
+// the documents follow it.
+func (cache *Cache) FS(c *content.Content) fs.FS {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.fsys == nil || cache.content != c {
+		cache.content = c
+		cache.fsys = New(c, time.Now())
+	}
+	return cache.fsys
+}
+
+func aboutMarkdown(c *content.Content) []byte {
+	var b strings.Builder
+	b.WriteString("# ")
+	b.WriteString(c.Profile.Name)
+	b.WriteString("\n\n")
+	if c.Profile.Title != "" {
+		b.WriteString("*" + c.Profile.Title + "*\n\n")
+	}
+	b.WriteString(strings.TrimSpace(c.About.Intro))
+	b.WriteString("\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,138 @@
+package files
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/andatoshiki/termfolio/content"
+)
+
+func TestNewFS(t *testing.T) {
+	fsys := New(content.Default(), time.Unix(1700000000, 0))
+	if err := fstest.TestFS(fsys, ResumePDF, ResumeText, ContactCard, AboutMarkdown); err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := fs.ReadFile(fsys, ResumeText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"ANDA TOSHIKI", "EXPERIENCE", "Software Engineer, Microsoft", "2025 - Present"} {
+		if !bytes.Contains(text, []byte(want)) {
+			t.Fatalf("resume.txt is missing %q:\n%s", want, text)
+		}
+	}
+	for _, line := range strings.Split(string(text), "\n") {
+		if n := len([]rune(line)); n > resumeTextWidth {
+			t.Fatalf("resume.txt line is %d runes wide, want at most %d: %q", n, resumeTextWidth, line)
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	var cache Cache
+	c := content.Default()
+
+	first := cache.FS(c)
+	if again := cache.FS(c); again != first {
+		t.Fatalf("FS() rebuilt the documents for the same content")
+	}
+	reloaded := content.Default()
+	reloaded.Profile.Name = "Ada Lovelace"
+	fsys := cache.FS(reloaded)
+	if fsys == first {
+		t.Fatalf("FS() kept the documents of the previous content")
+	}
+	text, err := fs.ReadFile(fsys, ResumeText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(text, []byte("ADA LOVELACE")) {
+		t.Fatalf("resume.txt after reload is missing the new name:\n%s", text)
+	}
+}
+
+func TestResumePDFCrossReference(t *testing.T) {
+	pdf := resumePDF(buildResume(content.Default()))
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatalf("missing PDF header or trailer")
+	}
+
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	if startxref == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	if !bytes.HasPrefix(pdf[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xref:], -1)
+	if len(entries) == 0 {
+		t.Fatal("empty xref table")
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		want := fmt.Sprintf("%d 0 obj\n", i+1)
+		if !bytes.HasPrefix(pdf[offset:], []byte(want)) {
+			t.Fatalf("xref entry %d points at %q, want %q", i+1, pdf[offset:offset+10], want)
+		}
+	}
+}
+
+func TestPDFString(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{in: "plain", want: "(plain)"},
+		{in: `a (b) \c`, want: `(a \(b\) \\c)`},
+		{in: "Maho ShouJo (魔法少女) who", want: "(Maho ShouJo who)"},
+		{in: "2024 • café — x", want: `(2024 \225 caf\351 \227 x)`},
+	}
+
+	for _, tc := range cases {
+		if got := pdfString(tc.in); got != tc.want {
+			t.Fatalf("pdfString(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestVCard(t *testing.T) {
+	card := string(vCard(content.Default()))
+
+	for _, want := range []string{"BEGIN:VCARD\r\n", "FN:Anda Toshiki\r\n", "N:Toshiki;Anda;;;\r\n", "EMAIL;TYPE=INTERNET:hi@tosh1ki.de\r\n", "END:VCARD\r\n"} {
+		if !strings.Contains(card, want) {
+			t.Fatalf("vCard is missing %q:\n%s", want, card)
+		}
+	}
+	for _, line := range strings.Split(card, "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf("vCard line is %d octets, want at most 75: %q", len(line), line)
+		}
+	}
+}
+
+func TestCleanPath(t *testing.T) {
+	cases := map[string]string{
+		"resume.pdf":   "resume.pdf",
+		"/resume.pdf":  "resume.pdf",
+		"./about.md":   "about.md",
+		"../../etc/pw": "etc/pw",
+		"":             ".",
+		"/":            ".",
+	}
+	for in, want := range cases {
+		if got := cleanPath(in); got != want {
+			t.Fatalf("cleanPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
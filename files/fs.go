@@ -0,0 +1,123 @@
+package files
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"sort"
+	"time"
+)
+
+// memFS is a flat, read-only in-memory filesystem.
+type memFS struct {
+	files   map[string][]byte
+	modTime time.Time
+}
+
+var (
+	_ fs.ReadDirFS = (*memFS)(nil)
+	_ fs.StatFS    = (*memFS)(nil)
+)
+
+func (m *memFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		entries, _ := m.ReadDir(".")
+		return &memDir{info: m.dirInfo(), entries: entries}, nil
+	}
+	data, ok := m.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &memFile{info: m.fileInfo(name), Reader: bytes.NewReader(data)}, nil
+}
+
+func (m *memFS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return m.dirInfo(), nil
+	}
+	if _, ok := m.files[name]; !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return m.fileInfo(name), nil
+}
+
+func (m *memFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if name != "." {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	names := make([]string, 0, len(m.files))
+	for n := range m.files {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	entries := make([]fs.DirEntry, len(names))
+	for i, n := range names {
+		entries[i] = fs.FileInfoToDirEntry(m.fileInfo(n))
+	}
+	return entries, nil
+}
+
+func (m *memFS) fileInfo(name string) fileInfo {
+	return fileInfo{name: name, size: int64(len(m.files[name])), mode: 0o444, modTime: m.modTime}
+}
+
+func (m *memFS) dirInfo() fileInfo {
+	return fileInfo{name: ".", mode: fs.ModeDir | 0o555, modTime: m.modTime}
+}
+
+type fileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i fileInfo) Name() string       { return i.name }
+func (i fileInfo) Size() int64        { return i.size }
+func (i fileInfo) Mode() fs.FileMode  { return i.mode }
+func (i fileInfo) ModTime() time.Time { return i.modTime }
+func (i fileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i fileInfo) Sys() any           { return nil }
+
+type memFile struct {
+	*bytes.Reader
+	info fileInfo
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Close() error               { return nil }
+
+type memDir struct {
+	info    fileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+	return rest[:n], nil
+}
@@ -0,0 +1,240 @@
+package files
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// The résumé PDF is written by hand: US Letter pages set in the standard
+// Courier fonts, which every PDF reader ships and whose fixed 600/1000 em
+// advance makes wrapping exact without embedding font metrics.
+const (
+	pdfPageWidth  = 612.0
+	pdfPageHeight = 792.0
+	pdfMargin     = 54.0
+	pdfCharWidth  = 0.6
+
+	pdfNameSize    = 20.0
+	pdfSectionSize = 13.0
+	pdfBodySize    = 10.0
+	pdfIndent      = 12.0
+)
+
+type pdfFont int
+
+const (
+	pdfRegular pdfFont = iota
+	pdfBold
+)
+
+type pdfLine struct {
+	font pdfFont
+	size float64
+	x    float64
+	y    float64
+	text string
+}
+
+// pdfLayout flows lines down the page, starting a new page when one is full.
+type pdfLayout struct {
+	pages [][]pdfLine
+	y     float64
+}
+
+func (l *pdfLayout) line(font pdfFont, size float64, x float64, text string) {
+	leading := size * 1.3
+	if len(l.pages) == 0 || l.y-leading < pdfMargin {
+		l.pages = append(l.pages, nil)
+		l.y = pdfPageHeight - pdfMargin
+	}
+	page := len(l.pages) - 1
+	l.pages[page] = append(l.pages[page], pdfLine{font: font, size: size, x: x, y: l.y - size, text: text})
+	l.y -= leading
+}
+
+// right sets text right-aligned on the baseline of the previous line.
+func (l *pdfLayout) right(font pdfFont, size float64, text string) {
+	if len(l.pages) == 0 || text == "" {
+		return
+	}
+	page := len(l.pages) - 1
+	last := l.pages[page][len(l.pages[page])-1]
+	x := pdfPageWidth - pdfMargin - pdfTextWidth(text, size)
+	l.pages[page] = append(l.pages[page], pdfLine{font: font, size: size, x: x, y: last.y, text: text})
+}
+
+func (l *pdfLayout) gap(points float64) {
+	l.y -= points
+}
+
+func pdfTextWidth(text string, size float64) float64 {
+	return float64(utf8.RuneCountInString(text)) * pdfCharWidth * size
+}
+
+// pdfColumns is how many characters of the given size fit between x and the
+// right margin.
+func pdfColumns(x float64, size float64) int {
+	return int((pdfPageWidth - pdfMargin - x) / (pdfCharWidth * size))
+}
+
+func layoutResumePDF(r resume) *pdfLayout {
+	l := &pdfLayout{}
+
+	l.line(pdfBold, pdfNameSize, pdfMargin, pdfText(r.name))
+	if r.headline != "" {
+		l.line(pdfRegular, pdfBodySize+1, pdfMargin, pdfText(r.headline))
+	}
+	for _, line := range wrapText(pdfText(strings.Join(r.contact, " • ")), pdfColumns(pdfMargin, pdfBodySize)) {
+		l.line(pdfRegular, pdfBodySize, pdfMargin, line)
+	}
+
+	for _, section := range r.sections {
+		l.gap(pdfBodySize)
+		l.line(pdfBold, pdfSectionSize, pdfMargin, pdfText(strings.ToUpper(section.title)))
+		l.gap(2)
+		for i, entry := range section.entries {
+			if i > 0 {
+				l.gap(pdfBodySize / 2)
+			}
+			x := pdfMargin
+			if entry.heading != "" {
+				meta := pdfText(entry.meta)
+				headingColumns := pdfColumns(pdfMargin, pdfBodySize)
+				if meta != "" {
+					headingColumns -= utf8.RuneCountInString(meta) + 2
+				}
+				for j, line := range wrapText(pdfText(entry.heading), headingColumns) {
+					l.line(pdfBold, pdfBodySize, pdfMargin, line)
+					if j == 0 {
+						l.right(pdfRegular, pdfBodySize, meta)
+					}
+				}
+				x += pdfIndent
+			}
+			for _, para := range entry.lines {
+				if para == "" {
+					l.gap(pdfBodySize / 2)
+					continue
+				}
+				for _, line := range wrapText(pdfText(para), pdfColumns(x, pdfBodySize)) {
+					l.line(pdfRegular, pdfBodySize, x, line)
+				}
+			}
+		}
+	}
+
+	return l
+}
+
+// resumePDF renders the résumé as a PDF document.
+func resumePDF(r resume) []byte {
+	layout := layoutResumePDF(r)
+
+	var objects []string
+	add := func(obj string) int {
+		objects = append(objects, obj)
+		return len(objects)
+	}
+
+	catalog := add("")
+	pagesObj := add("")
+	regular := add("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	bold := add("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	info := add(fmt.Sprintf("<< /Title %s /Creator (termfolio) >>", pdfString(r.name+" - Resume")))
+
+	var kids []string
+	for _, lines := range layout.pages {
+		var stream strings.Builder
+		for _, line := range lines {
+			font := "F1"
+			if line.font == pdfBold {
+				font = "F2"
+			}
+			fmt.Fprintf(&stream, "BT /%s %.1f Tf %.2f %.2f Td %s Tj ET\n", font, line.size, line.x, line.y, pdfString(line.text))
+		}
+		contents := add(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", stream.Len(), stream.String()))
+		page := add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>",
+			pagesObj, pdfPageWidth, pdfPageHeight, regular, bold, contents))
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+	}
+	objects[catalog-1] = fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObj)
+	objects[pagesObj-1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, catalog, info, xref)
+
+	return b.Bytes()
+}
+
+// pdfString encodes text as a PDF literal string in WinAnsiEncoding.
+func pdfString(text string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range pdfText(text) {
+		c, ok := winAnsi(r)
+		if !ok {
+			continue
+		}
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			if c < 0x20 || c > 0x7e {
+				fmt.Fprintf(&b, "\\%03o", c)
+				continue
+			}
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// pdfText drops characters the standard fonts cannot show, such as CJK, and
+// tidies the gaps they leave behind.
+func pdfText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if _, ok := winAnsi(r); ok {
+			b.WriteRune(r)
+		}
+	}
+	out := b.String()
+	for _, empty := range []string{"()", "[]", "（）"} {
+		out = strings.ReplaceAll(out, empty, "")
+	}
+	return strings.Join(strings.Fields(out), " ")
+}
+
+// winAnsiExtras maps the characters of WinAnsiEncoding outside Latin-1.
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e, '‘': 0x91,
+	'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+	'™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+func winAnsi(r rune) (byte, bool) {
+	switch {
+	case r == '\t':
+		return ' ', true
+	case r >= 0x20 && r <= 0x7e, r >= 0xa0 && r <= 0xff:
+		return byte(r), true
+	}
+	c, ok := winAnsiExtras[r]
+	return c, ok
+}
@@ -0,0 +1,213 @@
+package files
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/andatoshiki/termfolio/content"
+	"github.com/andatoshiki/termfolio/view"
+)
+
+// resume is the layout shared by the text and PDF résumés.
+type resume struct {
+	name     string
+	headline string
+	contact  []string
+	sections []resumeSection
+}
+
+type resumeSection struct {
+	title   string
+	entries []resumeEntry
+}
+
+// resumeEntry is one item of a section. Lines are unwrapped paragraphs; an
+// empty line separates paragraphs.
+type resumeEntry struct {
+	heading string
+	meta    string
+	lines   []string
+}
+
+func buildResume(c *content.Content) resume {
+	r := resume{
+		name:     c.Profile.Name,
+		headline: c.Profile.Title,
+	}
+	for _, field := range []string{c.Profile.Email, c.Profile.Phone, c.Profile.Location, c.Profile.URL} {
+		if field != "" {
+			r.contact = append(r.contact, field)
+		}
+	}
+
+	if intro := strings.TrimSpace(c.About.Intro); intro != "" {
+		r.sections = append(r.sections, resumeSection{
+			title:   "About",
+			entries: []resumeEntry{{lines: strings.Split(view.MarkdownPlain(intro, 0), "\n")}},
+		})
+	}
+
+	if len(c.Experience) > 0 {
+		section := resumeSection{title: "Experience"}
+		for _, e := range c.Experience {
+			section.entries = append(section.entries, resumeEntry{
+				heading: joinNonEmpty(", ", e.Role, e.Company),
+				meta:    e.Period,
+				lines:   nonEmpty(e.Desc),
+			})
+		}
+		r.sections = append(r.sections, section)
+	}
+
+	if len(c.Education) > 0 {
+		section := resumeSection{title: "Education"}
+		for _, e := range c.Education {
+			section.entries = append(section.entries, resumeEntry{
+				heading: joinNonEmpty(", ", e.Role, e.Company),
+				meta:    e.Period,
+				lines:   nonEmpty(e.Desc, e.URL),
+			})
+		}
+		r.sections = append(r.sections, section)
+	}
+
+	if len(c.Projects) > 0 {
+		section := resumeSection{title: "Projects"}
+		for _, p := range c.Projects {
+			entry := resumeEntry{
+				heading: p.Name,
+				meta:    joinNonEmpty(" • ", p.Status, p.Year),
+			}
+			if desc := strings.TrimSpace(p.Desc); desc != "" {
+				entry.lines = append(entry.lines, strings.Split(view.MarkdownPlain(desc, 0), "\n")...)
+			}
+			if p.Tech != "" {
+				entry.lines = append(entry.lines, "Tech: "+p.Tech)
+			}
+			if p.Link != "" {
+				entry.lines = append(entry.lines, p.Link)
+			}
+			section.entries = append(section.entries, entry)
+		}
+		r.sections = append(r.sections, section)
+	}
+
+	var contact []string
+	for _, g := range c.Contact.Groups {
+		for _, l := range g.Links {
+			text := l.Text
+			if text == "" {
+				text = l.URL
+			}
+			contact = append(contact, l.Label+": "+text)
+		}
+	}
+	if len(contact) > 0 {
+		r.sections = append(r.sections, resumeSection{
+			title:   "Contact",
+			entries: []resumeEntry{{lines: contact}},
+		})
+	}
+
+	return r
+}
+
+// resumeText renders the résumé as plain text wrapped to width columns.
+func resumeText(r resume, width int) string {
+	var b strings.Builder
+
+	b.WriteString(strings.ToUpper(r.name))
+	b.WriteString("\n")
+	if r.headline != "" {
+		b.WriteString(r.headline)
+		b.WriteString("\n")
+	}
+	if len(r.contact) > 0 {
+		for _, line := range wrapText(strings.Join(r.contact, " • "), width) {
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
+
+	for _, section := range r.sections {
+		b.WriteString("\n")
+		b.WriteString(strings.ToUpper(section.title))
+		b.WriteString("\n")
+		b.WriteString(strings.Repeat("=", utf8.RuneCountInString(section.title)))
+		b.WriteString("\n")
+		for i, entry := range section.entries {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			indent := ""
+			if entry.heading != "" {
+				b.WriteString(alignRight(entry.heading, entry.meta, width))
+				b.WriteString("\n")
+				indent = "  "
+			}
+			for _, para := range entry.lines {
+				if para == "" {
+					b.WriteString("\n")
+					continue
+				}
+				for _, line := range wrapText(para, width-len(indent)) {
+					b.WriteString(indent + line)
+					b.WriteString("\n")
+				}
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// alignRight places right at the end of a width-column line after left, or
+// on the same line after a gap when it does not fit.
+func alignRight(left, right string, width int) string {
+	if right == "" {
+		return left
+	}
+	gap := width - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	if gap < 2 {
+		gap = 2
+	}
+	return left + strings.Repeat(" ", gap) + right
+}
+
+// wrapText greedily wraps text at spaces to width runes. Words longer than
+// width keep their own line.
+func wrapText(text string, width int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil
+	}
+	if width <= 0 {
+		return []string{strings.Join(words, " ")}
+	}
+
+	var lines []string
+	line := words[0]
+	for _, word := range words[1:] {
+		if utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+		line += " " + word
+	}
+	return append(lines, line)
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	return strings.Join(nonEmpty(parts...), sep)
+}
+
+func nonEmpty(parts ...string) []string {
+	var out []string
+	for _, part := range parts {
+		if strings.TrimSpace(part) != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
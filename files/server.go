@@ -0,0 +1,183 @@
+package files
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/charmbracelet/wish/scp"
+	"github.com/pkg/sftp"
+)
+
+// Source returns the documents served to a session, or nil when the
+// session's user has no portfolio.
+type Source func(s ssh.Session) fs.FS
+
+// SCPMiddleware serves downloads such as `scp host:resume.pdf .` from src.
+// Uploads are refused.
+func SCPMiddleware(src Source) wish.Middleware {
+	return scp.Middleware(&scpHandler{src: src}, nil)
+}
+
+type scpHandler struct {
+	src Source
+}
+
+func (h *scpHandler) open(s ssh.Session) (fs.FS, error) {
+	fsys := h.src(s)
+	if fsys == nil {
+		return nil, fmt.Errorf("no portfolio for user %q", s.User())
+	}
+	return fsys, nil
+}
+
+func (h *scpHandler) Glob(s ssh.Session, pattern string) ([]string, error) {
+	fsys, err := h.open(s)
+	if err != nil {
+		return nil, err
+	}
+	return fs.Glob(fsys, cleanPath(pattern))
+}
+
+func (h *scpHandler) WalkDir(s ssh.Session, root string, fn fs.WalkDirFunc) error {
+	fsys, err := h.open(s)
+	if err != nil {
+		return err
+	}
+	return fs.WalkDir(fsys, cleanPath(root), fn)
+}
+
+func (h *scpHandler) NewDirEntry(s ssh.Session, name string) (*scp.DirEntry, error) {
+	fsys, err := h.open(s)
+	if err != nil {
+		return nil, err
+	}
+	return scp.NewFSReadHandler(fsys).NewDirEntry(s, cleanPath(name))
+}
+
+func (h *scpHandler) NewFileEntry(s ssh.Session, name string) (*scp.FileEntry, func() error, error) {
+	fsys, err := h.open(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	return scp.NewFSReadHandler(fsys).NewFileEntry(s, cleanPath(name))
+}
+
+// SFTPHandler serves src read-only over the SFTP subsystem. Errors that end
+// a session abnormally are passed to onError with the session.
+func SFTPHandler(src Source, onError func(ssh.Session, error)) ssh.SubsystemHandler {
+	return func(s ssh.Session) {
+		fsys := src(s)
+		if fsys == nil {
+			fmt.Fprintf(s.Stderr(), "no portfolio for user %q\n", s.User())
+			_ = s.Exit(1)
+			return
+		}
+
+		h := &sftpHandler{fsys: fsys}
+		server := sftp.NewRequestServer(s, sftp.Handlers{FileGet: h, FilePut: h, FileCmd: h, FileList: h})
+		status := 0
+		if err := server.Serve(); err != nil && !errors.Is(err, io.EOF) {
+			status = 1
+			if onError != nil {
+				onError(s, err)
+			}
+		}
+		// Exit sends the status before closing the channel; closing the
+		// server first would drop it.
+		_ = s.Exit(status)
+		_ = server.Close()
+	}
+}
+
+type sftpHandler struct {
+	fsys fs.FS
+}
+
+func (h *sftpHandler) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	f, err := h.fsys.Open(cleanPath(r.Filepath))
+	if err != nil {
+		return nil, sftpError(err)
+	}
+	if readerAt, ok := f.(io.ReaderAt); ok {
+		return readerAt, nil
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+func (h *sftpHandler) Filewrite(*sftp.Request) (io.WriterAt, error) {
+	return nil, sftp.ErrSSHFxPermissionDenied
+}
+
+func (h *sftpHandler) Filecmd(*sftp.Request) error {
+	return sftp.ErrSSHFxPermissionDenied
+}
+
+func (h *sftpHandler) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	name := cleanPath(r.Filepath)
+	switch r.Method {
+	case "List":
+		entries, err := fs.ReadDir(h.fsys, name)
+		if err != nil {
+			return nil, sftpError(err)
+		}
+		infos := make(listerAt, 0, len(entries))
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil {
+				return nil, err
+			}
+			infos = append(infos, info)
+		}
+		return infos, nil
+	case "Stat":
+		info, err := fs.Stat(h.fsys, name)
+		if err != nil {
+			return nil, sftpError(err)
+		}
+		return listerAt{info}, nil
+	default:
+		return nil, sftp.ErrSSHFxOpUnsupported
+	}
+}
+
+type listerAt []os.FileInfo
+
+func (l listerAt) ListAt(out []os.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+	n := copy(out, l[offset:])
+	if n < len(out) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func sftpError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+		return sftp.ErrSSHFxNoSuchFile
+	}
+	return err
+}
+
+// cleanPath maps a client path such as "/resume.pdf" or "./about.md" to a
+// path inside the virtual filesystem.
+func cleanPath(name string) string {
+	cleaned := path.Clean("/" + name)[1:]
+	if cleaned == "" {
+		return "."
+	}
+	return cleaned
+}
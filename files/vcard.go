@@ -0,0 +1,109 @@
+package files
+
+import (
+	"strings"
+
+	"github.com/andatoshiki/termfolio/content"
+	"github.com/andatoshiki/termfolio/view"
+)
+
+// vCard renders the profile and web contact links as a vCard 3.0 card.
+func vCard(c *content.Content) []byte {
+	var lines []string
+	add := func(line string) {
+		lines = append(lines, foldVCardLine(line))
+	}
+
+	p := c.Profile
+	add("BEGIN:VCARD")
+	add("VERSION:3.0")
+	add("FN:" + escapeVCard(p.Name))
+	add("N:" + vCardName(p.Name))
+	if p.Title != "" {
+		add("TITLE:" + escapeVCard(p.Title))
+	}
+	if p.Email != "" {
+		add("EMAIL;TYPE=INTERNET:" + escapeVCard(p.Email))
+	}
+	if p.Phone != "" {
+		add("TEL;TYPE=CELL:" + escapeVCard(p.Phone))
+	}
+	if p.Location != "" {
+		add("ADR:;;;" + escapeVCard(p.Location) + ";;;")
+	}
+	if p.URL != "" {
+		add("URL:" + escapeVCard(p.URL))
+	}
+	for _, g := range c.Contact.Groups {
+		for _, l := range g.Links {
+			switch {
+			case strings.HasPrefix(l.URL, "mailto:"):
+				email := strings.TrimPrefix(l.URL, "mailto:")
+				if email != p.Email {
+					add("EMAIL;TYPE=INTERNET:" + escapeVCard(email))
+				}
+			case strings.HasPrefix(l.URL, "tel:"):
+				add("TEL:" + escapeVCard(strings.TrimPrefix(l.URL, "tel:")))
+			case l.URL != "" && l.URL != p.URL:
+				add("X-SOCIALPROFILE;TYPE=" + escapeVCardParam(l.Label) + ":" + escapeVCard(l.URL))
+			}
+		}
+	}
+	if intro := strings.TrimSpace(c.About.Intro); intro != "" {
+		add("NOTE:" + escapeVCard(view.MarkdownPlain(intro, 0)))
+	}
+	add("END:VCARD")
+
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+// vCardName splits a display name into the structured N property, taking
+// the last word as the family name.
+func vCardName(name string) string {
+	fields := strings.Fields(name)
+	if len(fields) < 2 {
+		return escapeVCard(name) + ";;;;"
+	}
+	family := fields[len(fields)-1]
+	given := strings.Join(fields[:len(fields)-1], " ")
+	return escapeVCard(family) + ";" + escapeVCard(given) + ";;;"
+}
+
+func escapeVCard(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(value)
+}
+
+func escapeVCardParam(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ';', ':', ',', '"':
+			return -1
+		}
+		return r
+	}, value)
+}
+
+// foldVCardLine folds lines longer than 75 octets, as RFC 2425 requires,
+// without splitting UTF-8 sequences.
+func foldVCardLine(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+
+	var b strings.Builder
+	width := 0
+	max := limit
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > max {
+			b.WriteString("\r\n ")
+			width = 0
+			max = limit - 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
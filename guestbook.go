@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/andatoshiki/termfolio/config"
+	"github.com/andatoshiki/termfolio/counter"
+)
+
+// moderateGuestbook implements the guestbook subcommand, which lists
+// entries and approves or rejects pending ones.
+func moderateGuestbook(args []string) error {
+	flags := flag.NewFlagSet("guestbook", flag.ExitOnError)
+	configPath := flags.String("c", "config.yaml", "Path to configuration file")
+	user := flags.String("u", "", "Moderate the guestbook of this tenant username")
+	status := flags.String("s", counter.GuestbookPending, "Status of the entries to list")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: termfolio guestbook [-c config] [-u user] [-s status] [list | approve <id> | reject <id>]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	userProvidedPath := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "c" {
+			userProvidedPath = true
+		}
+	})
+
+	cfg, err := config.Load(*configPath, userProvidedPath)
+	if err != nil {
+		return err
+	}
+	dbPath := cfg.Counter.DBPath
+	if *user != "" {
+		tc, ok := cfg.FindTenant(*user)
+		if !ok {
+			return fmt.Errorf("unknown tenant %q", *user)
+		}
+		dbPath = tc.DBPath
+	}
+	// The salt is only rotated by the server
+	salts, err := counter.LoadSalts(cfg.Counter.SaltPath, 0, time.Now())
+	if err != nil {
+		return err
+	}
+	store, err := counter.Open(dbPath, cfg.Stats.GeoLiteDBPath, salts)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	action := flags.Arg(0)
+	switch action {
+	case "", "list":
+		entries, total, err := store.GuestbookEntries(*status, 0, 100)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			fmt.Printf("%d\t%s\t%s\t%s\n", e.ID, e.CreatedAt.Format("2006-01-02 15:04"), e.Name, e.Message)
+		}
+		fmt.Fprintf(os.Stderr, "%d of %d %s entries\n", len(entries), total, *status)
+		return nil
+	case "approve", "reject":
+		id, err := strconv.ParseInt(flags.Arg(1), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid entry id %q", flags.Arg(1))
+		}
+		next := counter.GuestbookApproved
+		if action == "reject" {
+			next = counter.GuestbookRejected
+		}
+		return store.SetGuestbookStatus(id, next)
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown guestbook action %q", action)
+	}
+}
@@ -0,0 +1,151 @@
+// Package limit caps how quickly and how many SSH sessions visitors open,
+// per IP and in total.
+package limit
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+
+	"github.com/andatoshiki/termfolio/metrics"
+)
+
+// pruneInterval is how often addresses with no sessions and a full bucket
+// are forgotten.
+const pruneInterval = time.Minute
+
+var (
+	ErrRateLimited = errors.New("too many connections from your address")
+	ErrIPBusy      = errors.New("too many open sessions from your address")
+	ErrServerBusy  = errors.New("the server is at capacity")
+)
+
+var refused = metrics.NewCounter("termfolio_sessions_refused_total", "Sessions turned away by the connection limits.")
+
+// Config sets the limits; zero disables each. Every IP may open Burst
+// sessions at once, then Rate more per second.
+type Config struct {
+	Rate             float64
+	Burst            int
+	MaxSessionsPerIP int
+	MaxSessions      int
+}
+
+// Limiter tracks the sessions of each IP against a Config.
+type Limiter struct {
+	cfg Config
+	now func() time.Time
+
+	mu        sync.Mutex
+	ips       map[string]*address
+	active    int
+	lastPrune time.Time
+}
+
+type address struct {
+	tokens float64
+	refill time.Time
+	active int
+}
+
+func New(cfg Config) *Limiter {
+	return &Limiter{cfg: cfg, now: time.Now, ips: make(map[string]*address)}
+}
+
+// Acquire admits a session from ip, or returns why it is refused. release
+// must be called once the session ends.
+func (l *Limiter) Acquire(ip string) (release func(), err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastPrune) >= pruneInterval {
+		l.prune(now)
+		l.lastPrune = now
+	}
+
+	a, ok := l.ips[ip]
+	if !ok {
+		a = &address{tokens: float64(l.cfg.Burst), refill: now}
+		l.ips[ip] = a
+	}
+	l.refill(a, now)
+
+	switch {
+	case l.cfg.MaxSessions > 0 && l.active >= l.cfg.MaxSessions:
+		return nil, ErrServerBusy
+	case l.cfg.MaxSessionsPerIP > 0 && a.active >= l.cfg.MaxSessionsPerIP:
+		return nil, ErrIPBusy
+	case l.cfg.Rate > 0 && a.tokens < 1:
+		return nil, ErrRateLimited
+	}
+	if l.cfg.Rate > 0 {
+		a.tokens--
+	}
+	a.active++
+	l.active++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			a.active--
+			l.active--
+		})
+	}, nil
+}
+
+// Middleware runs next for the sessions Acquire admits and hands the others
+// to refuse. Sessions for which exempt is true, such as the owner's, skip
+// the limits.
+func (l *Limiter) Middleware(exempt func(ssh.Session) bool, refuse func(ssh.Session, error)) wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			if exempt != nil && exempt(s) {
+				next(s)
+				return
+			}
+			release, err := l.Acquire(remoteIP(s.RemoteAddr()))
+			if err != nil {
+				refused.Inc()
+				refuse(s, err)
+				return
+			}
+			defer release()
+			next(s)
+		}
+	}
+}
+
+// refill adds the tokens earned since the last refill, up to Burst. The
+// caller holds mu.
+func (l *Limiter) refill(a *address, now time.Time) {
+	a.tokens = min(float64(l.cfg.Burst), a.tokens+now.Sub(a.refill).Seconds()*l.cfg.Rate)
+	a.refill = now
+}
+
+// prune forgets idle addresses whose bucket is full again, as a new entry
+// would be the same. The caller holds mu.
+func (l *Limiter) prune(now time.Time) {
+	for ip, a := range l.ips {
+		l.refill(a, now)
+		if a.active == 0 && a.tokens >= float64(l.cfg.Burst) {
+			delete(l.ips, ip)
+		}
+	}
+}
+
+func remoteIP(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(addr.String()); err == nil {
+		return host
+	}
+	return addr.String()
+}
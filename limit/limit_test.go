@@ -0,0 +1,110 @@
+package limit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(cfg Config) (*Limiter, *time.Time) {
+	now := time.Unix(1700000000, 0)
+	l := New(cfg)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestAcquire(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  Config
+		// ips are acquired in order; want is the error of each
+		ips  []string
+		want []error
+	}{
+		{
+			name: "unlimited",
+			ips:  []string{"a", "a", "a"},
+			want: []error{nil, nil, nil},
+		},
+		{
+			name: "burst",
+			cfg:  Config{Rate: 1, Burst: 2},
+			ips:  []string{"a", "a", "a", "b"},
+			want: []error{nil, nil, ErrRateLimited, nil},
+		},
+		{
+			name: "per ip",
+			cfg:  Config{MaxSessionsPerIP: 2},
+			ips:  []string{"a", "a", "a", "b"},
+			want: []error{nil, nil, ErrIPBusy, nil},
+		},
+		{
+			name: "global",
+			cfg:  Config{MaxSessions: 2},
+			ips:  []string{"a", "b", "c"},
+			want: []error{nil, nil, ErrServerBusy},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			l, _ := newTestLimiter(tc.cfg)
+			for i, ip := range tc.ips {
+				_, err := l.Acquire(ip)
+				if !errors.Is(err, tc.want[i]) {
+					t.Fatalf("Acquire(%q) #%d error = %v, want %v", ip, i, err, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRelease(t *testing.T) {
+	l, _ := newTestLimiter(Config{MaxSessionsPerIP: 1, MaxSessions: 1})
+
+	release, err := l.Acquire("a")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	if _, err := l.Acquire("b"); !errors.Is(err, ErrServerBusy) {
+		t.Fatalf("Acquire() while full error = %v, want %v", err, ErrServerBusy)
+	}
+	release()
+	release()
+	if l.active != 0 {
+		t.Fatalf("active = %d after release, want 0", l.active)
+	}
+	if _, err := l.Acquire("a"); err != nil {
+		t.Fatalf("Acquire() after release error = %v", err)
+	}
+}
+
+func TestRefillAndPrune(t *testing.T) {
+	l, now := newTestLimiter(Config{Rate: 0.5, Burst: 1})
+
+	release, err := l.Acquire("a")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	release()
+	if _, err := l.Acquire("a"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Acquire() with empty bucket error = %v, want %v", err, ErrRateLimited)
+	}
+
+	*now = now.Add(2 * time.Second)
+	if _, err := l.Acquire("a"); err != nil {
+		t.Fatalf("Acquire() after refill error = %v", err)
+	}
+
+	// "a" still has a session open, "b" is idle once refilled
+	release, _ = l.Acquire("b")
+	release()
+	*now = now.Add(pruneInterval)
+	_, _ = l.Acquire("c")
+	if _, ok := l.ips["a"]; !ok {
+		t.Fatalf("address with an open session was pruned")
+	}
+	if _, ok := l.ips["b"]; ok {
+		t.Fatalf("idle address was not pruned")
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/charmbracelet/ssh"
+
+	"github.com/andatoshiki/termfolio/config"
+)
+
+// sessionIDLength is how many hex digits of the SSH session hash identify a
+// session in the logs.
+const sessionIDLength = 16
+
+// newLogger builds the server logger from the log config, which config.Load
+// has validated.
+func newLogger(cfg config.LogConfig, w io.Writer) *slog.Logger {
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(cfg.Level))
+	opts := &slog.HandlerOptions{Level: level}
+	if cfg.Format == "json" {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// sessionLogger tags every line with the SSH session it is about, so the
+// lines of one connection can be found together.
+func sessionLogger(logger *slog.Logger, s ssh.Session) *slog.Logger {
+	id := s.Context().SessionID()
+	if len(id) > sessionIDLength {
+		id = id[:sessionIDLength]
+	}
+	remoteAddr := ""
+	if addr := s.RemoteAddr(); addr != nil {
+		remoteAddr = addr.String()
+	}
+	return logger.With(
+		slog.String("session", id),
+		slog.String("remote", remoteAddr),
+		slog.String("user", s.User()),
+		slog.String("client", s.Context().ClientVersion()),
+	)
+}
+
+// fatal logs err and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "err", err)
+	os.Exit(1)
+}
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"log"
+	"io"
+	"io/fs"
+	"log/slog"
 	"net"
 	"os"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -13,14 +18,46 @@ import (
 	"github.com/charmbracelet/wish"
 	"github.com/charmbracelet/wish/bubbletea"
 	"github.com/muesli/termenv"
+	gossh "golang.org/x/crypto/ssh"
 
+	"github.com/andatoshiki/termfolio/chat"
 	"github.com/andatoshiki/termfolio/config"
+	"github.com/andatoshiki/termfolio/content"
 	"github.com/andatoshiki/termfolio/counter"
+	"github.com/andatoshiki/termfolio/files"
+	"github.com/andatoshiki/termfolio/limit"
+	"github.com/andatoshiki/termfolio/pages"
+	"github.com/andatoshiki/termfolio/session"
+	"github.com/andatoshiki/termfolio/tenant"
 	"github.com/andatoshiki/termfolio/ui"
 	"github.com/andatoshiki/termfolio/version"
 )
 
+// presenceInterval batches presence updates so a burst of connections or
+// page switches is pushed once.
+const presenceInterval = 500 * time.Millisecond
+
+// saltCheckInterval is how often the IP salt is checked for rotation, or
+// the rotation period when that is shorter.
+const saltCheckInterval = time.Hour
+
 func main() {
+	// Subcommands run instead of the server
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "export-resume":
+			if err := exportResume(os.Args[2:]); err != nil {
+				fatal("Failed to export JSON Resume", err)
+			}
+			return
+		case "guestbook":
+			if err := moderateGuestbook(os.Args[2:]); err != nil {
+				fatal("Failed to moderate guestbook", err)
+			}
+			return
+		}
+	}
+
 	// Parse CLI flags
 	configPath := flag.String("c", "config.yaml", "Path to configuration file")
 	showVersion := flag.Bool("v", false, "Show version information")
@@ -46,77 +83,404 @@ func main() {
 	// Load configuration
 	cfg, err := config.Load(*configPath, userProvidedPath)
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		fatal("Failed to load config", err)
+	}
+	slog.SetDefault(newLogger(cfg.Log, os.Stderr))
+
+	if cfg.Metrics.Enabled {
+		go func() {
+			slog.Info("Serving metrics", "url", "http://"+cfg.Metrics.Address+"/metrics")
+			fatal("Failed to serve metrics", serveMetrics(cfg.Metrics.Address))
+		}()
 	}
 
-	var counterStore *counter.Store
+	var salts counter.Salts
 	if cfg.Counter.Enabled {
-		store, err := counter.Open(cfg.Counter.DBPath)
+		salts, err = counter.LoadSalts(cfg.Counter.SaltPath, cfg.Counter.SaltRotation, time.Now())
 		if err != nil {
-			log.Fatalf("Failed to open counter db: %v", err)
+			fatal("Failed to load IP salt", err)
+		}
+	}
+
+	tenants, err := buildTenants(cfg, *configPath, userProvidedPath, salts)
+	if err != nil {
+		fatal("Failed to load portfolios", err)
+	}
+	if cfg.Counter.Enabled && cfg.Counter.SaltRotation > 0 {
+		go rotateSalts(context.Background(), cfg.Counter, tenants, min(cfg.Counter.SaltRotation, saltCheckInterval))
+	}
+	directory := directoryEntries(cfg, tenants)
+
+	sessions := session.NewRegistry()
+	for _, t := range tenants.All() {
+		t := t
+		logContentWarnings(t.Name, t.Content.Current())
+		go t.Content.Run(context.Background(), func(portfolio *content.Content) {
+			slog.Info("Reloaded content", "portfolio", t.Name)
+			logContentWarnings(t.Name, portfolio)
+			sessions.BroadcastWhere(func(info session.Info) bool {
+				return info.Tenant == t.User
+			}, ui.ContentMsg{Content: portfolio})
+		}, func(err error) {
+			slog.Error("Failed to reload content, keeping previous version", "portfolio", t.Name, "err", err)
+		})
+		if t.Counter != nil && cfg.Counter.RetentionDays > 0 {
+			go t.Counter.RunJanitor(context.Background(), time.Duration(cfg.Counter.RetentionDays)*24*time.Hour, func(purged int) {
+				slog.Info("Purged expired visitors", "portfolio", t.Name, "visitors", purged)
+			}, func(err error) {
+				slog.Error("Failed to purge expired visitors", "portfolio", t.Name, "err", err)
+			})
+		}
+		if t.Outbox != nil {
+			go t.Outbox.Run(context.Background(), func(err error) {
+				slog.Error("Failed to deliver message", "portfolio", t.Name, "err", err)
+			})
+		}
+		if cfg.Lobby.Enabled {
+			t.Lobby = chat.NewHub(func() {
+				sessions.BroadcastWhere(func(info session.Info) bool {
+					return info.Tenant == t.User && info.Page == pages.LobbyID
+				}, pages.LobbyMsg{})
+			})
+		}
+	}
+
+	adminKeys, err := parseAdminKeys("ssh.adminKeys", cfg.SSH.AdminKeys)
+	if err != nil {
+		fatal("Failed to load admin keys", err)
+	}
+	// isAdminKey reports whether key administers the portfolio user selects:
+	// it is one of the owner's server-wide keys or one of that tenant's
+	isAdminKey := func(user string, key ssh.PublicKey) bool {
+		if containsKey(adminKeys, key) {
+			return true
 		}
-		counterStore = store
+		t, ok := tenants.Lookup(user)
+		return ok && containsKey(t.AdminKeys, key)
+	}
+	isAdmin := func(s ssh.Session) bool {
+		key := s.PublicKey()
+		return key != nil && isAdminKey(s.User(), key)
 	}
 
+	// Push live presence to every session of a portfolio when people connect,
+	// leave or move between pages
+	go sessions.RunPresence(context.Background(), presenceInterval, func(tenantUser string, presence session.Presence) {
+		t, ok := tenants.Lookup(tenantUser)
+		if !ok || t.User != tenantUser {
+			return
+		}
+		visits := 0
+		if t.Counter != nil {
+			count, err := t.Counter.Count()
+			if err != nil {
+				slog.Error("Failed to read visitor count", "portfolio", t.Name, "err", err)
+			}
+			visits = count
+		}
+		sessions.BroadcastWhere(func(info session.Info) bool {
+			return info.Tenant == tenantUser
+		}, ui.PresenceMsg{Online: presence.Online, Visits: visits})
+	})
+
 	// Ensure host key exists (will prompt user to generate if needed)
 	if err := EnsureHostKey(cfg.SSH.HostKeyPath); err != nil {
-		log.Fatalf("Failed to ensure host key: %v", err)
+		fatal("Failed to ensure host key", err)
 	}
 
-	teaHandler := func(s ssh.Session) (tea.Model, []tea.ProgramOption) {
+	teaHandler := func(s ssh.Session, logger *slog.Logger, lobby *chat.Client, admin bool, reportPage func(id string)) (tea.Model, *counter.Visit, []tea.ProgramOption) {
+		t, ok := tenants.Lookup(s.User())
+		if !ok {
+			return ui.NewDirectoryModel(s.User(), directory), nil, []tea.ProgramOption{tea.WithAltScreen()}
+		}
+		if admin {
+			return ui.NewAdminModel(t, sessions, func() {
+				sessions.BroadcastWhere(func(info session.Info) bool {
+					return info.Tenant == t.User
+				}, ui.SettingsMsg{Stats: t.Settings.Stats.Load()})
+			}, func(reportPage func(id string)) tea.Model {
+				// The owner browses without being counted
+				visitorCount := 0
+				if t.Counter != nil {
+					count, err := t.Counter.Count()
+					if err != nil {
+						logger.Error("Failed to read visitor count", "err", err)
+					}
+					visitorCount = count
+				}
+				return ui.NewModelWithCounter(
+					t.Content.Current(),
+					t.Counter,
+					t.Outbox,
+					lobby,
+					visitorCount,
+					counter.Visitor{},
+					nil,
+					false,
+					t.Settings.Stats.Load(),
+					sessionColorProfile(s),
+					logger,
+					reportPage,
+				)
+			}, reportPage), nil, []tea.ProgramOption{tea.WithAltScreen()}
+		}
+		counterStore := t.Counter
+
 		visitorCount := 0
 		trackingEnabled := counterStore != nil
-		remoteIP := ""
+		visitor := sessionVisitor(s)
 		if counterStore != nil {
-			if addr := s.RemoteAddr(); addr != nil {
-				host, _, err := net.SplitHostPort(addr.String())
-				if err == nil {
-					remoteIP = host
-				} else {
-					remoteIP = addr.String()
-				}
-			}
-
-			if remoteIP != "" {
-				optedOut, err := counterStore.IsOptedOut(remoteIP)
+			if visitor.ID() != "" {
+				optedOut, err := counterStore.IsOptedOut(visitor)
 				if err != nil {
-					log.Printf("Failed to read privacy status: %v", err)
+					logger.Error("Failed to read privacy status", "err", err)
 				} else {
 					trackingEnabled = !optedOut
 				}
 			}
 
 			var err error
-			if trackingEnabled {
-				visitorCount, err = counterStore.RecordVisit(remoteIP)
+			if trackingEnabled && t.Settings.Counting.Load() {
+				visitorCount, err = counterStore.RecordVisit(visitor)
 			} else {
 				visitorCount, err = counterStore.Count()
 			}
 			if err != nil {
-				log.Printf("Failed to update counter: %v", err)
+				logger.Error("Failed to update counter", "err", err)
 			}
 		}
+		// Navigation is recorded like visits, unless the visitor opted out
+		var visit *counter.Visit
+		if counterStore != nil && t.Settings.Counting.Load() {
+			visit = counterStore.StartVisit(trackingEnabled, func(err error) {
+				logger.Error("Failed to record navigation", "err", err)
+			})
+		}
 		return ui.NewModelWithCounter(
+			t.Content.Current(),
 			counterStore,
+			t.Outbox,
+			lobby,
 			visitorCount,
-			remoteIP,
+			visitor,
+			visit,
 			trackingEnabled,
-			cfg.Stats.Enabled,
-			cfg.Stats.GeoLiteDBPath,
-		), []tea.ProgramOption{tea.WithAltScreen()}
+			t.Settings.Stats.Load(),
+			sessionColorProfile(s),
+			logger,
+			reportPage,
+		), visit, []tea.ProgramOption{tea.WithAltScreen()}
+	}
+
+	// Register every running program so content reloads can be pushed into
+	// it
+	programHandler := func(s ssh.Session) *tea.Program {
+		// The middleware would refuse a program without a terminal, so
+		// refuse the session before its visit is recorded or registered
+		if _, _, ok := s.Pty(); !ok {
+			wish.Fatalln(s, "no active terminal, skipping")
+			return nil
+		}
+		// The model reports page changes only once the program runs, after
+		// the session has been added and id is set
+		var id uint64
+		logger := sessionLogger(slog.Default(), s)
+		admin := isAdmin(s)
+		var lobby *chat.Client
+		if t, ok := tenants.Lookup(s.User()); ok && t.Lobby != nil {
+			lobby = t.Lobby.Client(sessionVisitor(s).ID(), admin)
+		}
+		m, visit, opts := teaHandler(s, logger, lobby, admin, func(page string) {
+			sessions.SetPage(id, page)
+		})
+		p := tea.NewProgram(m, append(opts, bubbletea.MakeOptions(s)...)...)
+		remoteAddr := ""
+		if addr := s.RemoteAddr(); addr != nil {
+			remoteAddr = addr.String()
+		}
+		tenantUser := ""
+		if t, ok := tenants.Lookup(s.User()); ok {
+			tenantUser = t.User
+		}
+		page := pages.SplashID
+		if admin {
+			page = ui.AdminPageID
+		}
+		id = sessions.Add(session.Info{Tenant: tenantUser, User: s.User(), RemoteAddr: remoteAddr, Page: page, Admin: admin}, p)
+		started := time.Now()
+		logger.Debug("Session started", "portfolio", tenantUser, "admin", admin)
+		sessionsActive.Inc()
+		sessionsTotal.Inc()
+		go func() {
+			<-s.Context().Done()
+			sessionsActive.Dec()
+			sessionDuration.ObserveSince(started)
+			sessions.Remove(id)
+			if lobby != nil {
+				lobby.Leave()
+			}
+			// After a quit the visit has ended already
+			visit.End(counter.EndDisconnect)
+			term := ""
+			if pty, _, ok := s.Pty(); ok {
+				term = pty.Term
+			}
+			logger.Info("Session ended",
+				"portfolio", tenantUser,
+				"admin", admin,
+				"term", term,
+				"duration_ms", time.Since(started).Milliseconds(),
+			)
+		}()
+		return p
 	}
 
+	// Documents generated from the portfolio, downloadable over SCP and
+	// SFTP. Each portfolio builds them once per content reload
+	downloadCache := make(map[*tenant.Tenant]*files.Cache)
+	for _, t := range tenants.All() {
+		downloadCache[t] = &files.Cache{}
+	}
+	downloads := func(s ssh.Session) fs.FS {
+		t, ok := tenants.Lookup(s.User())
+		if !ok {
+			return nil
+		}
+		return downloadCache[t].FS(t.Content.Current())
+	}
+
+	// Sessions beyond the limits see a busy notice instead of the TUI; the
+	// owner is never turned away
+	limiter := limit.New(limit.Config{
+		Rate:             float64(cfg.Limits.ConnectionsPerMinute) / 60,
+		Burst:            cfg.Limits.Burst,
+		MaxSessionsPerIP: cfg.Limits.MaxSessionsPerIP,
+		MaxSessions:      cfg.Limits.MaxSessions,
+	})
+	limited := limiter.Middleware(func(s ssh.Session) bool {
+		return isAdmin(s)
+	}, refuseSession)
+
 	s, err := wish.NewServer(
 		wish.WithAddress(cfg.SSH.ListenAddr()),
 		wish.WithHostKeyPath(cfg.SSH.HostKeyPath),
+		// Only admin keys are accepted, so a client tries each of its keys
+		// until one opens the admin TUI; visitors fall back to
+		// keyboard-interactive, which asks nothing. The first key offered
+		// is kept to tell visitors apart
+		wish.WithPublicKeyAuth(func(ctx ssh.Context, key ssh.PublicKey) bool {
+			if ctx.Value(offeredKeyContextKey{}) == nil {
+				ctx.SetValue(offeredKeyContextKey{}, key)
+			}
+			return isAdminKey(ctx.User(), key)
+		}),
+		wish.WithKeyboardInteractiveAuth(func(ssh.Context, gossh.KeyboardInteractiveChallenge) bool { return true }),
+		wish.WithSubsystem("sftp", ssh.SubsystemHandler(limited(ssh.Handler(files.SFTPHandler(downloads, func(s ssh.Session, err error) {
+			sessionLogger(slog.Default(), s).Error("SFTP session failed", "err", err)
+		}))))),
 		wish.WithMiddleware(
-			bubbletea.Middleware(teaHandler),
+			bubbletea.MiddlewareWithProgramHandler(programHandler, termenv.Ascii),
+			files.SCPMiddleware(downloads),
+			// Last, so it runs first
+			limited,
 		),
 	)
 	if err != nil {
-		log.Fatal(err)
+		fatal("Failed to create SSH server", err)
+	}
+
+	slog.Info("Listening", "addr", s.Addr)
+	fatal("SSH server stopped", s.ListenAndServe())
+}
+
+// parseAdminKeys reads a list of admin keys, one authorized_keys line each.
+// field names the list in errors.
+func parseAdminKeys(field string, lines []string) ([]ssh.PublicKey, error) {
+	keys := make([]ssh.PublicKey, 0, len(lines))
+	for i, line := range lines {
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: %w", field, i, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func containsKey(keys []ssh.PublicKey, key ssh.PublicKey) bool {
+	for _, k := range keys {
+		if ssh.KeysEqual(key, k) {
+			return true
+		}
 	}
+	return false
+}
 
-	log.Printf("Listening on %s\n", s.Addr)
-	log.Fatal(s.ListenAndServe())
+// offeredKeyContextKey holds the first public key the client offered, on
+// the connection's context.
+type offeredKeyContextKey struct{}
+
+// sessionVisitor identifies the visitor for counting, opt-outs and lobby
+// moderation: by the fingerprint of the key they authenticated with or,
+// as only admin keys are accepted, the first key their client offered, and
+// by IP without one. An offered key is not proven to be theirs, so it only
+// tells visitors apart and never grants anything.
+func sessionVisitor(s ssh.Session) counter.Visitor {
+	var v counter.Visitor
+	key := s.PublicKey()
+	if key == nil {
+		key, _ = s.Context().Value(offeredKeyContextKey{}).(ssh.PublicKey)
+	}
+	if key != nil {
+		v.Fingerprint = gossh.FingerprintSHA256(key)
+	}
+	if addr := s.RemoteAddr(); addr != nil {
+		if host, _, err := net.SplitHostPort(addr.String()); err == nil {
+			v.IP = host
+		} else {
+			v.IP = addr.String()
+		}
+	}
+	return v
+}
+
+// refuseSession tells a visitor turned away by the limits why, as a busy
+// screen in a terminal or a line on stderr for scp and sftp.
+func refuseSession(s ssh.Session, err error) {
+	sessionLogger(slog.Default(), s).Warn("Refused session", "err", err)
+	pty, _, ok := s.Pty()
+	if !ok {
+		wish.Fatalln(s, "Server busy: "+err.Error()+", please try again in a minute.")
+		return
+	}
+	screen := ui.RenderBusyScreen(err.Error(), pty.Window.Width)
+	_, _ = io.WriteString(s, strings.ReplaceAll(screen, "\n", "\r\n")+"\r\n")
+	_ = s.Exit(1)
+}
+
+// sessionColorProfile detects the client's color support from the TERM and
+// COLORTERM values it sent, without querying the terminal.
+func sessionColorProfile(s ssh.Session) termenv.Profile {
+	pty, _, ok := s.Pty()
+	if !ok || pty.Term == "" || pty.Term == "dumb" {
+		return termenv.Ascii
+	}
+	env := sessionEnviron(append(s.Environ(), "TERM="+pty.Term))
+	return termenv.NewOutput(io.Discard, termenv.WithEnvironment(env), termenv.WithUnsafe()).EnvColorProfile()
+}
+
+type sessionEnviron []string
+
+func (e sessionEnviron) Environ() []string {
+	return e
+}
+
+func (e sessionEnviron) Getenv(key string) string {
+	value := ""
+	for _, kv := range e {
+		if k, v, ok := strings.Cut(kv, "="); ok && k == key {
+			value = v
+		}
+	}
+	return value
 }
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/andatoshiki/termfolio/metrics"
+)
+
+var (
+	sessionsActive  = metrics.NewGauge("termfolio_sessions_active", "SSH sessions running the portfolio TUI.")
+	sessionsTotal   = metrics.NewCounter("termfolio_sessions_total", "SSH sessions that opened the portfolio TUI.")
+	sessionDuration = metrics.NewHistogram("termfolio_session_duration_seconds", "How long portfolio TUI sessions lasted.",
+		[]float64{5, 15, 30, 60, 120, 300, 600, 1800, 3600})
+)
+
+// serveMetrics serves /metrics on addr until the listener fails.
+func serveMetrics(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return server.ListenAndServe()
+}
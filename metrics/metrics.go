@@ -0,0 +1,239 @@
+// Package metrics keeps the server's counters, gauges and histograms and
+// serves them in the Prometheus text format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DurationBuckets are the default histogram bounds, in seconds.
+var DurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Default is the registry the package-level constructors add to and the
+// Handler serves.
+var Default = NewRegistry()
+
+// metric is a named series written in the text format.
+type metric interface {
+	write(w io.Writer, name string) error
+	kind() string
+}
+
+type entry struct {
+	name   string
+	help   string
+	metric metric
+}
+
+// Registry is a set of metrics with unique names.
+type Registry struct {
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+func NewRegistry() *Registry {
+	return &Registry{entries: make(map[string]entry)}
+}
+
+// Counter is a total that only goes up.
+type Counter struct {
+	value atomic.Uint64
+}
+
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+func (c *Counter) Add(n uint64) {
+	c.value.Add(n)
+}
+
+func (c *Counter) Value() uint64 {
+	return c.value.Load()
+}
+
+func (c *Counter) kind() string { return "counter" }
+
+func (c *Counter) write(w io.Writer, name string) error {
+	_, err := fmt.Fprintf(w, "%s %d\n", name, c.Value())
+	return err
+}
+
+// Gauge is a value that goes up and down.
+type Gauge struct {
+	value atomic.Int64
+}
+
+func (g *Gauge) Inc() {
+	g.value.Add(1)
+}
+
+func (g *Gauge) Dec() {
+	g.value.Add(-1)
+}
+
+func (g *Gauge) Set(v int64) {
+	g.value.Store(v)
+}
+
+func (g *Gauge) Value() int64 {
+	return g.value.Load()
+}
+
+func (g *Gauge) kind() string { return "gauge" }
+
+func (g *Gauge) write(w io.Writer, name string) error {
+	_, err := fmt.Fprintf(w, "%s %d\n", name, g.Value())
+	return err
+}
+
+// Histogram counts observations in buckets by upper bound.
+type Histogram struct {
+	bounds []float64
+
+	mu     sync.Mutex
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// Observe adds v to the bucket of the smallest bound not below it.
+func (h *Histogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if i := sort.SearchFloat64s(h.bounds, v); i < len(h.counts) {
+		h.counts[i]++
+	}
+	h.sum += v
+	h.count++
+}
+
+// ObserveSince observes the seconds elapsed since start.
+func (h *Histogram) ObserveSince(start time.Time) {
+	h.Observe(time.Since(start).Seconds())
+}
+
+func (h *Histogram) kind() string { return "histogram" }
+
+// write emits cumulative buckets, as the format expects.
+func (h *Histogram) write(w io.Writer, name string) error {
+	h.mu.Lock()
+	counts := append([]uint64(nil), h.counts...)
+	sum, count := h.sum, h.count
+	h.mu.Unlock()
+
+	var cumulative uint64
+	for i, bound := range h.bounds {
+		cumulative += counts[i]
+		if _, err := fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", name, formatFloat(bound), cumulative); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, count); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "%s_sum %s\n", name, formatFloat(sum)); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s_count %d\n", name, count)
+	return err
+}
+
+// NewCounter adds a counter to the registry.
+func (r *Registry) NewCounter(name string, help string) *Counter {
+	c := &Counter{}
+	r.add(name, help, c)
+	return c
+}
+
+// NewGauge adds a gauge to the registry.
+func (r *Registry) NewGauge(name string, help string) *Gauge {
+	g := &Gauge{}
+	r.add(name, help, g)
+	return g
+}
+
+// NewHistogram adds a histogram with the given ascending bucket bounds to
+// the registry.
+func (r *Registry) NewHistogram(name string, help string, bounds []float64) *Histogram {
+	h := &Histogram{
+		bounds: append([]float64(nil), bounds...),
+		counts: make([]uint64, len(bounds)),
+	}
+	sort.Float64s(h.bounds)
+	r.add(name, help, h)
+	return h
+}
+
+// add panics on a duplicate name, as metrics are registered at package
+// initialisation and a clash is a programming error.
+func (r *Registry) add(name string, help string, m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[name]; ok {
+		panic(fmt.Sprintf("metrics: %s registered twice", name))
+	}
+	r.entries[name] = entry{name: name, help: help, metric: m}
+}
+
+// Write writes every metric in the text format, sorted by name.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	entries := make([]entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		entries = append(entries, e)
+	}
+	r.mu.Unlock()
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+
+	for _, e := range entries {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", e.name, e.help, e.name, e.metric.kind()); err != nil {
+			return err
+		}
+		if err := e.metric.write(w, e.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler serves the registry at any path.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.Write(w)
+	})
+}
+
+// NewCounter adds a counter to the Default registry.
+func NewCounter(name string, help string) *Counter {
+	return Default.NewCounter(name, help)
+}
+
+// NewGauge adds a gauge to the Default registry.
+func NewGauge(name string, help string) *Gauge {
+	return Default.NewGauge(name, help)
+}
+
+// NewHistogram adds a histogram to the Default registry.
+func NewHistogram(name string, help string, bounds []float64) *Histogram {
+	return Default.NewHistogram(name, help, bounds)
+}
+
+// Handler serves the Default registry.
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
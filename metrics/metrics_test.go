@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistryWrite(t *testing.T) {
+	r := NewRegistry()
+	sessions := r.NewGauge("sessions_active", "Open sessions.")
+	total := r.NewCounter("sessions_total", "Sessions opened.")
+	duration := r.NewHistogram("session_duration_seconds", "Session length.", []float64{10, 1})
+
+	sessions.Inc()
+	sessions.Inc()
+	sessions.Dec()
+	total.Add(3)
+	duration.Observe(0.5)
+	duration.Observe(1)
+	duration.Observe(5)
+	duration.Observe(60)
+
+	var b strings.Builder
+	if err := r.Write(&b); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := `# HELP session_duration_seconds Session length.
+# TYPE session_duration_seconds histogram
+session_duration_seconds_bucket{le="1"} 2
+session_duration_seconds_bucket{le="10"} 3
+session_duration_seconds_bucket{le="+Inf"} 4
+session_duration_seconds_sum 66.5
+session_duration_seconds_count 4
+# HELP sessions_active Open sessions.
+# TYPE sessions_active gauge
+sessions_active 1
+# HELP sessions_total Sessions opened.
+# TYPE sessions_total counter
+sessions_total 3
+`
+	if got := b.String(); got != want {
+		t.Fatalf("Write() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("visits_total", "Visits.").Inc()
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Fatalf("Content-Type = %q", got)
+	}
+	if !strings.Contains(rec.Body.String(), "visits_total 1\n") {
+		t.Fatalf("body = %q, want visits_total 1", rec.Body.String())
+	}
+}
+
+func TestDuplicateNamePanics(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("visits_total", "Visits.")
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("second registration did not panic")
+		}
+	}()
+	r.NewGauge("visits_total", "Visits.")
+}
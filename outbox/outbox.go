@@ -0,0 +1,152 @@
+// Package outbox forwards contact form messages to the portfolio owner.
+// Messages are stored in the counter database first, then delivered through
+// each sink in the background with retries, so a visitor never waits on a
+// mail server and nothing is lost when one is down.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andatoshiki/termfolio/counter"
+)
+
+const (
+	pollInterval   = 5 * time.Second
+	deliverTimeout = 30 * time.Second
+	maxBackoff     = time.Hour
+	batchSize      = 20
+)
+
+// Sink delivers a message somewhere the owner will read it.
+type Sink interface {
+	// Name identifies the sink in the delivery queue. It must be stable
+	// across restarts so pending deliveries are picked up again.
+	Name() string
+	Deliver(ctx context.Context, d counter.Delivery) error
+}
+
+// Outbox queues messages and delivers them through its sinks.
+type Outbox struct {
+	store       *counter.Store
+	sinks       map[string]Sink
+	names       []string
+	wake        chan struct{}
+	maxAttempts int
+	retry       time.Duration
+}
+
+// New returns an outbox that gives up on a delivery after maxAttempts,
+// waiting retry after the first failure and doubling it each time.
+func New(store *counter.Store, sinks []Sink, maxAttempts int, retry time.Duration) *Outbox {
+	o := &Outbox{
+		store:       store,
+		sinks:       make(map[string]Sink),
+		wake:        make(chan struct{}, 1),
+		maxAttempts: max(maxAttempts, 1),
+		retry:       retry,
+	}
+	for _, sink := range sinks {
+		o.sinks[sink.Name()] = sink
+		o.names = append(o.names, sink.Name())
+	}
+	return o
+}
+
+// MaxAttempts is the number of attempts made per sink before giving up.
+func (o *Outbox) MaxAttempts() int {
+	return o.maxAttempts
+}
+
+// Send stores a message and wakes the dispatcher to deliver it.
+func (o *Outbox) Send(ip string, name string, replyTo string, body string) (counter.Message, error) {
+	msg, err := o.store.SaveMessage(ip, name, replyTo, body, o.names)
+	if err != nil {
+		return counter.Message{}, err
+	}
+	o.wakeUp()
+	return msg, nil
+}
+
+// Retry delivers a message that failed again, with a fresh set of attempts.
+func (o *Outbox) Retry(id int64) error {
+	if err := o.store.RetryMessage(id, time.Now()); err != nil {
+		return err
+	}
+	o.wakeUp()
+	return nil
+}
+
+func (o *Outbox) wakeUp() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Status reports how far delivery of a message has got.
+func (o *Outbox) Status(id int64) (counter.MessageStatus, error) {
+	return o.store.MessageStatus(id)
+}
+
+// Run delivers queued messages until ctx is cancelled. Delivery and store
+// errors are reported through onError.
+func (o *Outbox) Run(ctx context.Context, onError func(error)) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := o.dispatch(ctx, time.Now(), onError); err != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-o.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatch attempts every delivery due at now once.
+func (o *Outbox) dispatch(ctx context.Context, now time.Time, onError func(error)) error {
+	due, err := o.store.DueDeliveries(now, batchSize)
+	if err != nil {
+		return err
+	}
+	for _, d := range due {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		var deliveryErr error
+		final := d.Attempts+1 >= o.maxAttempts
+		if sink, ok := o.sinks[d.Sink]; ok {
+			deliverCtx, cancel := context.WithTimeout(ctx, deliverTimeout)
+			deliveryErr = sink.Deliver(deliverCtx, d)
+			cancel()
+		} else {
+			// The sink was removed from the config since the message was queued.
+			deliveryErr = fmt.Errorf("sink %s is not configured", d.Sink)
+			final = true
+		}
+		if deliveryErr != nil {
+			onError(fmt.Errorf("deliver message %d via %s (attempt %d): %w", d.ID, d.Sink, d.Attempts+1, deliveryErr))
+		}
+
+		if err := o.store.RecordDelivery(d.ID, d.Sink, deliveryErr, now.Add(o.backoff(d.Attempts)), final); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backoff is the wait after a failure, doubling per previous attempt.
+func (o *Outbox) backoff(attempts int) time.Duration {
+	wait := o.retry
+	for i := 0; i < attempts && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, maxBackoff)
+}
@@ -0,0 +1,307 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andatoshiki/termfolio/counter"
+)
+
+func openTestStore(t *testing.T) *counter.Store {
+	t.Helper()
+
+	store, err := counter.Open(filepath.Join(t.TempDir(), "visitors.db"), "", counter.Salts{Current: []byte("test salt")})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+// fakeSMTP is a minimal SMTP server that records the mails it accepts and
+// rejects the first failures recipients with a temporary error.
+type fakeSMTP struct {
+	addr     string
+	mu       sync.Mutex
+	mails    []string
+	failures int
+}
+
+func startFakeSMTP(t *testing.T, failures int) *fakeSMTP {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	s := &fakeSMTP{addr: ln.Addr().String(), failures: failures}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		verb := strings.ToUpper(strings.Fields(line + " x")[0])
+		switch verb {
+		case "EHLO", "HELO":
+			reply("250-fake")
+			reply("250 8BITMIME")
+		case "MAIL":
+			reply("250 ok")
+		case "RCPT":
+			s.mu.Lock()
+			fail := s.failures > 0
+			if fail {
+				s.failures--
+			}
+			s.mu.Unlock()
+			if fail {
+				reply("451 try again later")
+			} else {
+				reply("250 ok")
+			}
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			s.mu.Lock()
+			s.mails = append(s.mails, data.String())
+			s.mu.Unlock()
+			reply("250 queued")
+		case "RSET", "NOOP":
+			reply("250 ok")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func (s *fakeSMTP) sink(t *testing.T) *SMTPSink {
+	t.Helper()
+	host, port, err := net.SplitHostPort(s.addr)
+	if err != nil {
+		t.Fatalf("SplitHostPort() error = %v", err)
+	}
+	p, _ := strconv.Atoi(port)
+	return &SMTPSink{Host: host, Port: p, From: "termfolio@example.com", To: func() string { return "owner@example.com" }}
+}
+
+func (s *fakeSMTP) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.mails...)
+}
+
+func TestSMTPSinkDeliver(t *testing.T) {
+	server := startFakeSMTP(t, 0)
+	d := counter.Delivery{
+		Message: counter.Message{ID: 7, Name: "Zoë", ReplyTo: "zoe@example.org", Body: "Hi there,\nlove the site!", CreatedAt: time.Unix(1700000000, 0)},
+		Sink:    "smtp",
+	}
+
+	if err := server.sink(t).Deliver(context.Background(), d); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+
+	mails := server.received()
+	if len(mails) != 1 {
+		t.Fatalf("received %d mails, want 1", len(mails))
+	}
+	head, body, _ := strings.Cut(mails[0], "\r\n\r\n")
+	for _, want := range []string{
+		"To: owner@example.com",
+		`Reply-To: =?utf-8?q?Zo=C3=AB?= <zoe@example.org>`,
+		"Subject: =?utf-8?q?Message_from_Zo=C3=AB?=",
+		"Content-Transfer-Encoding: quoted-printable",
+	} {
+		if !strings.Contains(head, want) {
+			t.Fatalf("headers missing %q:\n%s", want, head)
+		}
+	}
+	decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(body)))
+	if err != nil {
+		t.Fatalf("decode body error = %v", err)
+	}
+	if !strings.HasPrefix(string(decoded), "Hi there,\r\nlove the site!") {
+		t.Fatalf("body = %q", decoded)
+	}
+
+	rejecting := startFakeSMTP(t, 1)
+	if err := rejecting.sink(t).Deliver(context.Background(), d); err == nil || !strings.Contains(err.Error(), "451") {
+		t.Fatalf("Deliver() error = %v, want 451 rejection", err)
+	}
+}
+
+func TestWebhookSinkDeliver(t *testing.T) {
+	var got webhookPayload
+	var auth string
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := &WebhookSink{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer secret"}}
+	d := counter.Delivery{Message: counter.Message{ID: 3, Name: "Ada", Body: "hello"}, Sink: "webhook"}
+
+	if err := sink.Deliver(context.Background(), d); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	if got.ID != 3 || got.Name != "Ada" || got.Body != "hello" || auth != "Bearer secret" {
+		t.Fatalf("payload = %+v, auth = %q", got, auth)
+	}
+
+	status = http.StatusBadGateway
+	if err := sink.Deliver(context.Background(), d); err == nil {
+		t.Fatalf("Deliver() error = nil, want error for %d", status)
+	}
+}
+
+func TestOutboxDeliversThroughEverySink(t *testing.T) {
+	store := openTestStore(t)
+	server := startFakeSMTP(t, 0)
+	hooks := 0
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hooks++
+	}))
+	defer webhook.Close()
+
+	o := New(store, []Sink{server.sink(t), &WebhookSink{URL: webhook.URL}}, 3, time.Minute)
+	msg, err := o.Send("203.0.113.1", "Ada", "ada@example.org", "hello")
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if status, _ := o.Status(msg.ID); status.State != counter.DeliveryPending {
+		t.Fatalf("status before dispatch = %q, want pending", status.State)
+	}
+
+	if err := o.dispatch(context.Background(), time.Now(), func(err error) { t.Errorf("delivery error: %v", err) }); err != nil {
+		t.Fatalf("dispatch() error = %v", err)
+	}
+
+	status, err := o.Status(msg.ID)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.State != counter.DeliveryDelivered || len(server.received()) != 1 || hooks != 1 {
+		t.Fatalf("status = %q, mails = %d, hooks = %d", status.State, len(server.received()), hooks)
+	}
+}
+
+type failingSink struct{ calls int }
+
+func (s *failingSink) Name() string { return "failing" }
+
+func (s *failingSink) Deliver(ctx context.Context, d counter.Delivery) error {
+	s.calls++
+	return errors.New("unreachable")
+}
+
+func TestOutboxRetriesWithBackoffThenGivesUp(t *testing.T) {
+	store := openTestStore(t)
+	sink := &failingSink{}
+	o := New(store, []Sink{sink}, 3, time.Minute)
+	msg, err := o.Send("203.0.113.1", "Ada", "", "hello")
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	var errs int
+	onError := func(error) { errs++ }
+	start := time.Now()
+	steps := []struct {
+		at       time.Time
+		calls    int
+		state    string
+		attempts int
+	}{
+		{at: start, calls: 1, state: counter.DeliveryPending, attempts: 1},
+		{at: start.Add(30 * time.Second), calls: 1, state: counter.DeliveryPending, attempts: 1},
+		{at: start.Add(time.Minute), calls: 2, state: counter.DeliveryPending, attempts: 2},
+		{at: start.Add(2 * time.Minute), calls: 2, state: counter.DeliveryPending, attempts: 2},
+		{at: start.Add(3 * time.Minute), calls: 3, state: counter.DeliveryFailed, attempts: 3},
+		{at: start.Add(time.Hour), calls: 3, state: counter.DeliveryFailed, attempts: 3},
+	}
+	for i, step := range steps {
+		if err := o.dispatch(context.Background(), step.at, onError); err != nil {
+			t.Fatalf("step %d: dispatch() error = %v", i, err)
+		}
+		status, err := o.Status(msg.ID)
+		if err != nil {
+			t.Fatalf("step %d: Status() error = %v", i, err)
+		}
+		if sink.calls != step.calls || status.State != step.state || status.Attempts != step.attempts {
+			t.Fatalf("step %d: calls = %d, state = %q, attempts = %d; want %d, %q, %d",
+				i, sink.calls, status.State, status.Attempts, step.calls, step.state, step.attempts)
+		}
+	}
+	if errs != 3 {
+		t.Fatalf("onError called %d times, want 3", errs)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	o := New(nil, nil, 5, 30*time.Second)
+	cases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, time.Minute},
+		{3, 4 * time.Minute},
+		{20, time.Hour},
+	}
+	for _, tc := range cases {
+		t.Run(strconv.Itoa(tc.attempts), func(t *testing.T) {
+			if got := o.backoff(tc.attempts); got != tc.want {
+				t.Fatalf("backoff(%d) = %v, want %v", tc.attempts, got, tc.want)
+			}
+		})
+	}
+}
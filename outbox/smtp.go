@@ -0,0 +1,145 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andatoshiki/termfolio/counter"
+)
+
+// SMTPSink mails messages to the owner. Port 465 uses implicit TLS; other
+// ports upgrade with STARTTLS when the server offers it.
+type SMTPSink struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	// To returns the recipient, read on every delivery so it follows
+	// content reloads.
+	To func() string
+}
+
+func (s *SMTPSink) Name() string { return "smtp" }
+
+func (s *SMTPSink) Deliver(ctx context.Context, d counter.Delivery) error {
+	to := s.To()
+	if to == "" {
+		return fmt.Errorf("no recipient address")
+	}
+	msg, err := composeMail(s.From, to, d)
+	if err != nil {
+		return err
+	}
+
+	port := s.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(port))
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if port == 465 {
+		conn = tls.Client(conn, &tls.Config{ServerName: s.Host})
+	}
+
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	if port != 465 {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: s.Host}); err != nil {
+				return fmt.Errorf("smtp starttls: %w", err)
+			}
+		}
+	}
+	if s.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err := client.Mail(s.From); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("smtp rcpt to: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	return client.Quit()
+}
+
+// composeMail renders a message as a plain text mail with the visitor as
+// Reply-To, so answering it reaches them directly.
+func composeMail(from string, to string, d counter.Delivery) ([]byte, error) {
+	var b bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&b, "%s: %s\r\n", key, value)
+	}
+	header("From", from)
+	header("To", to)
+	if d.ReplyTo != "" {
+		header("Reply-To", (&mail.Address{Name: d.Name, Address: d.ReplyTo}).String())
+	}
+	header("Subject", mime.QEncoding.Encode("utf-8", "Message from "+d.Name))
+	header("Date", d.CreatedAt.Format(time.RFC1123Z))
+	header("Message-ID", fmt.Sprintf("<termfolio-%d-%d@%s>", d.ID, d.CreatedAt.Unix(), mailDomain(from)))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	b.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&b)
+	body := fmt.Sprintf("%s\n\n-- \nSent by %s", d.Body, d.Name)
+	if d.ReplyTo != "" {
+		body += " <" + d.ReplyTo + ">"
+	}
+	body += " via the termfolio contact form\n"
+	if _, err := qp.Write(bytes.ReplaceAll([]byte(body), []byte("\n"), []byte("\r\n"))); err != nil {
+		return nil, fmt.Errorf("encode mail: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return nil, fmt.Errorf("encode mail: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+func mailDomain(addr string) string {
+	if parsed, err := mail.ParseAddress(addr); err == nil {
+		addr = parsed.Address
+	}
+	if i := strings.LastIndexByte(addr, '@'); i >= 0 {
+		return addr[i+1:]
+	}
+	return "localhost"
+}
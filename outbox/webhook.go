@@ -0,0 +1,71 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/andatoshiki/termfolio/counter"
+)
+
+// WebhookSink POSTs messages as JSON, e.g. to a chat integration. Any 2xx
+// response counts as delivered.
+type WebhookSink struct {
+	URL     string
+	Headers map[string]string
+	Client  *http.Client
+}
+
+type webhookPayload struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	ReplyTo   string    `json:"replyTo,omitempty"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (s *WebhookSink) Name() string { return "webhook" }
+
+func (s *WebhookSink) Deliver(ctx context.Context, d counter.Delivery) error {
+	payload, err := json.Marshal(webhookPayload{
+		ID:        d.ID,
+		Name:      d.Name,
+		ReplyTo:   d.ReplyTo,
+		Body:      d.Body,
+		CreatedAt: d.CreatedAt.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "termfolio")
+	for key, value := range s.Headers {
+		req.Header.Set(key, value)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -3,54 +3,93 @@ package pages
 import (
 	"math"
 	"strings"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 
+	"github.com/andatoshiki/termfolio/content"
 	"github.com/andatoshiki/termfolio/view"
 )
 
-const aboutCatLogo = `──────▄▀▄─────▄▀▄
-─────▄█░░▀▀▀▀▀░░█▄
-─▄▄──█░░░░░░░░░░░█──▄▄
-█▄▄█─█░░▀░░┬░░▀░░█─█▄▄█`
+const typewriterTick = 40 * time.Millisecond
 
-const aboutIntro = "Hey there, I'm Anda Toshiki -- call me kiki for short (like the protagonist from Kiki's Delivery Service by Hayao Miyazaki). I'm a Maho ShouJo (魔法少女) who loves anime, drinks monster, writes code, documents tutorials, takes photos, eats burgers, and stays up way too late. I'm into clean UI, useful tooling, and anything that makes dev life a little smoother."
+type aboutTickMsg struct {
+	gen int
+}
+
+type aboutPage struct {
+	reveal   int
+	scramble int
+	gen      int
+}
 
-var aboutContent = aboutCatLogo + "\n\n" + aboutIntro + "\n"
+func (p *aboutPage) Init(env *Env) tea.Cmd {
+	p.reveal = 0
+	p.scramble = 0
+	p.gen++
+	return aboutTickCmd(p.gen)
+}
+
+func (p *aboutPage) Update(env *Env, msg tea.Msg) (Page, tea.Cmd) {
+	tick, ok := msg.(aboutTickMsg)
+	if !ok || tick.gen != p.gen {
+		return p, nil
+	}
+	if p.reveal < AboutRuneCount(env.Content.About, env.BoxWidth) {
+		p.reveal++
+		p.scramble++
+		return p, aboutTickCmd(p.gen)
+	}
+	if p.scramble < p.reveal+AboutSettleTicks(env.Content.About, env.BoxWidth) {
+		p.scramble++
+		return p, aboutTickCmd(p.gen)
+	}
+	return p, nil
+}
+
+func (p *aboutPage) View(env *Env) string {
+	return RenderAbout(env.Styles, env.Content.About, p.reveal, p.scramble, env.Help(p.KeyHelp()), env.BoxWidth, env.ColorProfile)
+}
+
+func (p *aboutPage) Title() string       { return "About" }
+func (p *aboutPage) Description() string { return "Who I am" }
+func (p *aboutPage) KeyHelp() string     { return "esc: back to menu" }
+
+func aboutTickCmd(gen int) tea.Cmd {
+	return tea.Tick(typewriterTick, func(time.Time) tea.Msg {
+		return aboutTickMsg{gen: gen}
+	})
+}
 
-var aboutRunes = []rune(aboutContent)
 var scrambleRunes = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!@#$%^&*()-_=+[]{}|;:'\",.<>?/~")
 
 const settleDurationTicks = 8
 
-func aboutSettled(count int, scrambleTick int) bool {
+// aboutImageHeight caps the avatar so the intro stays on screen.
+const aboutImageHeight = 12
+
+// aboutText is the plain-text projection of the about page, wrapped the same
+// way as the styled render so the typewriter reveal lines up with it.
+func aboutText(about content.About, contentWidth int) string {
+	return about.Logo + "\n\n" + view.MarkdownPlain(about.Intro, contentWidth) + "\n"
+}
+
+func aboutSettled(aboutRunes []rune, count int, scrambleTick int) bool {
 	total := len(aboutRunes)
 	if count > total {
 		count = total
 	}
-	return count >= total && scrambleTick >= count+AboutSettleTicks()
+	return count >= total && scrambleTick >= count+settleDurationForWord(lastWordLength(aboutRunes))
 }
 
-func aboutStyled(styles view.ThemeStyles, contentWidth int) string {
+func aboutStyled(styles view.ThemeStyles, about content.About, contentWidth int) string {
 	var b strings.Builder
 
-	normal := styles.Content
-	bold := styles.Content.Copy().Bold(true)
-	italic := styles.Content.Copy().Italic(true)
-	link := styles.Accent.Copy().Underline(true)
-
-	b.WriteString(centerAboutLogo(styles.Accent.Copy().Bold(true).Render(aboutCatLogo), contentWidth))
+	b.WriteString(centerAboutLogo(styles.Accent.Copy().Bold(true).Render(about.Logo), contentWidth))
 	b.WriteString("\n\n")
-
-	b.WriteString(normal.Render("Hey there, I'm "))
-	b.WriteString(bold.Render("Anda Toshiki"))
-	b.WriteString(normal.Render(" -- call me "))
-	b.WriteString(italic.Render("kiki"))
-	b.WriteString(normal.Render(" for short (like the protagonist from "))
-	b.WriteString(view.ClickableLink(link.Render("Kiki's Delivery Service"), "https://en.wikipedia.org/wiki/Kiki%27s_Delivery_Service"))
-	b.WriteString(normal.Render(" by Hayao Miyazaki). I'm a "))
-	b.WriteString(italic.Render("Maho ShouJo"))
-	b.WriteString(normal.Render(" (魔法少女) who loves anime, drinks monster, writes code, documents tutorials, takes photos, eats burgers, and stays up way too late. I'm into clean UI, useful tooling, and anything that makes dev life a little smoother."))
+	b.WriteString(view.RenderMarkdown(styles, about.Intro, contentWidth))
 
 	return b.String()
 }
@@ -79,17 +118,7 @@ func centerAboutLogo(logo string, contentWidth int) string {
 	return lipgloss.NewStyle().Width(contentWidth).Align(lipgloss.Center).Render(logo)
 }
 
-func aboutContentWidth(boxWidth int) int {
-	if boxWidth <= 0 {
-		return 60
-	}
-	if boxWidth > 4 {
-		return boxWidth - 4
-	}
-	return boxWidth
-}
-
-func aboutVisible(count int, scrambleTick int) string {
+func aboutVisible(aboutRunes []rune, count int, scrambleTick int) string {
 	if count <= 0 {
 		return ""
 	}
@@ -98,8 +127,8 @@ func aboutVisible(count int, scrambleTick int) string {
 		count = total
 	}
 
-	if aboutSettled(count, scrambleTick) {
-		return aboutContent
+	if aboutSettled(aboutRunes, count, scrambleTick) {
+		return string(aboutRunes)
 	}
 
 	out := make([]rune, count)
@@ -149,27 +178,33 @@ func aboutVisible(count int, scrambleTick int) string {
 	return string(out)
 }
 
-func AboutRuneCount() int {
-	return len(aboutRunes)
+func AboutRuneCount(about content.About, boxWidth int) int {
+	return len([]rune(aboutText(about, boxContentWidth(boxWidth))))
 }
 
-func AboutSettleTicks() int {
-	return settleDurationForWord(lastWordLength())
+func AboutSettleTicks(about content.About, boxWidth int) int {
+	return settleDurationForWord(lastWordLength([]rune(aboutText(about, boxContentWidth(boxWidth)))))
 }
 
-func RenderAbout(styles view.ThemeStyles, revealCount int, scrambleTick int, themeLabel string, boxWidth int) string {
+func RenderAbout(styles view.ThemeStyles, about content.About, revealCount int, scrambleTick int, help string, boxWidth int, profile termenv.Profile) string {
 	var b strings.Builder
 
 	b.WriteString(styles.Title.Render("━━━ About Me ━━━"))
 	b.WriteString("\n")
-	contentWidth := aboutContentWidth(boxWidth)
-	if aboutSettled(revealCount, scrambleTick) {
-		b.WriteString(aboutStyled(styles, contentWidth))
+	contentWidth := boxContentWidth(boxWidth)
+	if about.Image != "" {
+		avatar := renderPicture(styles, content.Picture{Image: about.Image}, contentWidth, aboutImageHeight, profile)
+		b.WriteString(centerAboutLogo(avatar, contentWidth))
+		b.WriteString("\n\n")
+	}
+	aboutRunes := []rune(aboutText(about, contentWidth))
+	if aboutSettled(aboutRunes, revealCount, scrambleTick) {
+		b.WriteString(aboutStyled(styles, about, contentWidth))
 	} else {
-		b.WriteString(aboutVisibleStyled(styles, aboutVisible(revealCount, scrambleTick), contentWidth))
+		b.WriteString(aboutVisibleStyled(styles, aboutVisible(aboutRunes, revealCount, scrambleTick), contentWidth))
 	}
 	b.WriteString("\n")
-	b.WriteString(styles.Help.Render(themeLabel + " • esc: back to menu"))
+	b.WriteString(styles.Help.Render(help))
 
 	return b.String()
 }
@@ -226,7 +261,7 @@ func settleDurationForWord(wordLen int) int {
 	return settleDurationTicks
 }
 
-func lastWordLength() int {
+func lastWordLength(aboutRunes []rune) int {
 	if len(aboutRunes) == 0 {
 		return 0
 	}
@@ -0,0 +1,259 @@
+package pages
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/andatoshiki/termfolio/counter"
+	"github.com/andatoshiki/termfolio/session"
+	"github.com/andatoshiki/termfolio/view"
+)
+
+const (
+	adminNameWidth = 16
+	adminAddrWidth = 22
+	adminPageWidth = 12
+	adminDateFmt   = "2006-01-02 15:04"
+)
+
+// AdminAnalytics is the visit summary on the admin Analytics tab.
+type AdminAnalytics struct {
+	Visits            int
+	Online            int
+	Pages             map[string]int
+	GuestbookPending  int
+	GuestbookApproved int
+	Messages          int
+	OptOuts           int
+	// Countries is nil when country stats are off or unavailable, with
+	// CountriesError saying why in the latter case.
+	Countries      []counter.CountryCount
+	CountriesError string
+}
+
+// AdminSetting is a switch on the admin Settings tab.
+type AdminSetting struct {
+	Label       string
+	Description string
+	On          bool
+}
+
+// RenderAdmin frames the admin TUI: a tab bar above the active tab's body.
+func RenderAdmin(styles view.ThemeStyles, name string, tabs []string, active int, body string, notice string, errMsg string, help string) string {
+	var b strings.Builder
+
+	title := "━━━ Admin ━━━"
+	if name != "" {
+		title = "━━━ Admin · " + name + " ━━━"
+	}
+	b.WriteString(styles.Title.Render(title))
+	b.WriteString("\n")
+
+	labels := make([]string, len(tabs))
+	for i, tab := range tabs {
+		label := fmt.Sprintf(" %d %s ", i+1, tab)
+		if i == active {
+			labels[i] = styles.Selected.Render(label)
+		} else {
+			labels[i] = styles.Subtle.Render(label)
+		}
+	}
+	b.WriteString(strings.Join(labels, " "))
+	b.WriteString("\n\n")
+
+	b.WriteString(body)
+	b.WriteString("\n\n")
+	if errMsg != "" {
+		b.WriteString(styles.Error.Render(errMsg))
+		b.WriteString("\n")
+	} else if notice != "" {
+		b.WriteString(styles.Accent.Render(notice))
+		b.WriteString("\n")
+	}
+	b.WriteString(styles.Help.Render(help))
+	return b.String()
+}
+
+func RenderAdminSessions(styles view.ThemeStyles, sessions []session.Info, now time.Time, cursor int, rows int) string {
+	if len(sessions) == 0 {
+		return styles.Subtle.Render("No one is connected.")
+	}
+	lines := make([]string, len(sessions))
+	for i, s := range sessions {
+		user := s.User
+		if s.Admin {
+			user = "★" + user
+		}
+		lines[i] = fmt.Sprintf("#%-4d %-*s %-*s %-*s %s",
+			s.ID,
+			adminNameWidth, truncate(user, adminNameWidth),
+			adminAddrWidth, truncate(s.RemoteAddr, adminAddrWidth),
+			adminPageWidth, truncate(s.Page, adminPageWidth),
+			now.Sub(s.StartedAt).Round(time.Second))
+	}
+	return styles.Subtle.Render(fmt.Sprintf("%d connected, ★ marks your own sessions", len(sessions))) + "\n\n" +
+		renderAdminRows(styles, lines, cursor, rows)
+}
+
+func RenderAdminAnalytics(styles view.ThemeStyles, a AdminAnalytics) string {
+	var b strings.Builder
+	row := func(label string, value any) {
+		b.WriteString(styles.Content.Render(fmt.Sprintf("%-22s %v", label, value)))
+		b.WriteString("\n")
+	}
+	row("Unique visitors", a.Visits)
+	row("Online now", a.Online)
+	row("Opted out", a.OptOuts)
+	row("Guestbook pending", a.GuestbookPending)
+	row("Guestbook approved", a.GuestbookApproved)
+	row("Contact messages", a.Messages)
+
+	if len(a.Pages) > 0 {
+		b.WriteString("\n")
+		b.WriteString(styles.Accent.Render("Pages being viewed"))
+		b.WriteString("\n")
+		ids := make([]string, 0, len(a.Pages))
+		for id := range a.Pages {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool {
+			if a.Pages[ids[i]] == a.Pages[ids[j]] {
+				return ids[i] < ids[j]
+			}
+			return a.Pages[ids[i]] > a.Pages[ids[j]]
+		})
+		for _, id := range ids {
+			row("  "+id, a.Pages[id])
+		}
+	}
+
+	if a.CountriesError != "" {
+		b.WriteString("\n")
+		b.WriteString(styles.Subtle.Render("Countries unavailable: " + a.CountriesError))
+		b.WriteString("\n")
+	} else if a.Countries != nil {
+		b.WriteString("\n")
+		b.WriteString(styles.Accent.Render("Top countries"))
+		b.WriteString("\n")
+		if len(a.Countries) == 0 {
+			b.WriteString(styles.Subtle.Render("  N/A"))
+			b.WriteString("\n")
+		}
+		for _, country := range a.Countries {
+			row("  "+country.Name, country.Visitors)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// RenderAdminGuestbook lists pending entries with the selected one in full.
+func RenderAdminGuestbook(styles view.ThemeStyles, entries []counter.GuestbookEntry, total int, cursor int, rows int, width int) string {
+	if len(entries) == 0 {
+		return styles.Subtle.Render("No entries are waiting for approval.")
+	}
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = fmt.Sprintf("#%-4d %s  %-*s %s",
+			e.ID, e.CreatedAt.Format(adminDateFmt),
+			adminNameWidth, truncate(e.Name, adminNameWidth),
+			truncate(e.Message, max(width-45, 10)))
+	}
+	selected := entries[clampCursor(cursor, len(entries))]
+	return styles.Subtle.Render(adminShowing(len(entries), total, "pending entry", "pending entries")) + "\n\n" +
+		renderAdminRows(styles, lines, cursor, rows) + "\n\n" +
+		styles.Accent.Render(selected.Name) + "\n" +
+		lipgloss.NewStyle().Width(width).Render(styles.Content.Render(selected.Message))
+}
+
+// RenderAdminMessages lists contact messages with their delivery state and
+// the selected one in full.
+func RenderAdminMessages(styles view.ThemeStyles, messages []counter.InboxMessage, total int, cursor int, rows int, width int) string {
+	if len(messages) == 0 {
+		return styles.Subtle.Render("No messages yet.")
+	}
+	lines := make([]string, len(messages))
+	for i, m := range messages {
+		body, _, _ := strings.Cut(m.Body, "\n")
+		lines[i] = fmt.Sprintf("#%-4d %s  %-*s %-10s %s",
+			m.ID, m.CreatedAt.Format(adminDateFmt),
+			adminNameWidth, truncate(m.Name, adminNameWidth),
+			m.State,
+			truncate(body, max(width-56, 10)))
+	}
+
+	selected := messages[clampCursor(cursor, len(messages))]
+	from := selected.Name
+	if selected.ReplyTo != "" {
+		from += " <" + selected.ReplyTo + ">"
+	}
+	var b strings.Builder
+	b.WriteString(styles.Subtle.Render(adminShowing(len(messages), total, "message", "messages")))
+	b.WriteString("\n\n")
+	b.WriteString(renderAdminRows(styles, lines, cursor, rows))
+	b.WriteString("\n\n")
+	b.WriteString(styles.Accent.Render(from))
+	b.WriteString(styles.Period.Render("  " + selected.State))
+	b.WriteString("\n")
+	if selected.LastError != "" && selected.State != counter.DeliveryDelivered {
+		b.WriteString(styles.Error.Render(truncate("Last error: "+selected.LastError, width)))
+		b.WriteString("\n")
+	}
+	b.WriteString(lipgloss.NewStyle().Width(width).Render(styles.Content.Render(selected.Body)))
+	return b.String()
+}
+
+func RenderAdminOptOuts(styles view.ThemeStyles, records []counter.OptOut, total int, cursor int, rows int) string {
+	if len(records) == 0 {
+		return styles.Subtle.Render("No one has opted out.")
+	}
+	lines := make([]string, len(records))
+	for i, r := range records {
+		lines[i] = fmt.Sprintf("%-*s %s", adminAddrWidth+16, truncate(r.ID, adminAddrWidth+16), r.At.Format(adminDateFmt))
+	}
+	return styles.Subtle.Render(adminShowing(len(records), total, "opt-out", "opt-outs")) + "\n\n" +
+		renderAdminRows(styles, lines, cursor, rows)
+}
+
+func RenderAdminSettings(styles view.ThemeStyles, settings []AdminSetting, cursor int) string {
+	lines := make([]string, len(settings))
+	for i, s := range settings {
+		state := "off"
+		if s.On {
+			state = "on "
+		}
+		lines[i] = fmt.Sprintf("[%s] %-*s %s", state, adminNameWidth, s.Label, s.Description)
+	}
+	return renderAdminRows(styles, lines, cursor, len(lines)) + "\n\n" +
+		styles.Subtle.Render("Settings apply until the server restarts; the config file sets the defaults.")
+}
+
+// renderAdminRows shows the rows around the cursor, highlighting it.
+func renderAdminRows(styles view.ThemeStyles, lines []string, cursor int, rows int) string {
+	start, end := projectWindow(cursor, len(lines), rows)
+	out := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		if i == cursor {
+			out = append(out, styles.Selected.Render("→ "+lines[i]))
+		} else {
+			out = append(out, styles.Menu.Render("  "+lines[i]))
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// adminShowing counts the rows of a list, such as "Newest 50 of 120
+// messages". Nouns are given in singular and plural.
+func adminShowing(shown int, total int, one string, many string) string {
+	noun := many
+	if total == 1 {
+		noun = one
+	}
+	if shown < total {
+		return fmt.Sprintf("Newest %d of %d %s", shown, total, noun)
+	}
+	return fmt.Sprintf("%d %s", total, noun)
+}
@@ -0,0 +1,19 @@
+package pages
+
+import (
+	"strings"
+
+	"github.com/andatoshiki/termfolio/view"
+)
+
+// RenderBusy explains why a session was turned away before the TUI started.
+func RenderBusy(styles view.ThemeStyles, reason string) string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("━━━ Server busy ━━━"))
+	b.WriteString("\n\n")
+	b.WriteString(styles.Content.Render("Sorry, " + reason + "."))
+	b.WriteString("\n")
+	b.WriteString(styles.Subtle.Render("Please try again in a minute."))
+	return b.String()
+}
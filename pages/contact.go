@@ -1,51 +1,231 @@
 package pages
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/andatoshiki/termfolio/content"
+	"github.com/andatoshiki/termfolio/counter"
 	"github.com/andatoshiki/termfolio/view"
 )
 
-func RenderContact(styles view.ThemeStyles, themeLabel string) string {
+const (
+	contactColumnWidth = 32
+	contactLabelWidth  = 10
+
+	messageStatusInterval = time.Second
+)
+
+// messageStatusMsg asks the contact page to check on a sent message.
+type messageStatusMsg struct {
+	id int64
+}
+
+func (messageStatusMsg) PageID() string { return ContactID }
+
+func pollMessageStatus(id int64) tea.Cmd {
+	return tea.Tick(messageStatusInterval, func(time.Time) tea.Msg {
+		return messageStatusMsg{id: id}
+	})
+}
+
+type contactPage struct {
+	composing bool
+	form      form
+
+	// sent is the last message sent in this session, polled until its
+	// delivery settles.
+	sent   int64
+	status string
+}
+
+func (p *contactPage) Init(env *Env) tea.Cmd {
+	p.composing = false
+	return nil
+}
+
+func (p *contactPage) Update(env *Env, msg tea.Msg) (Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case messageStatusMsg:
+		if msg.id != p.sent || env.Outbox == nil {
+			return p, nil
+		}
+		return p, p.refreshStatus(env)
+	case tea.KeyMsg:
+		if p.composing {
+			return p, p.updateForm(env, msg)
+		}
+		if msg.String() == "m" && env.Outbox != nil {
+			if p.form.fields == nil {
+				p.form = newForm(
+					textField{label: "Name", limit: counter.MessageMaxName},
+					textField{label: "Reply-to email (optional)", limit: counter.MessageMaxReplyTo},
+					textField{label: "Message", limit: counter.MessageMaxBody, multiline: true},
+				)
+			}
+			p.composing = true
+			p.form.focus = 0
+			p.form.err = ""
+		}
+	}
+	return p, nil
+}
+
+func (p *contactPage) updateForm(env *Env, key tea.KeyMsg) tea.Cmd {
+	switch key.Type {
+	case tea.KeyCtrlC:
+		return env.Quit()
+	case tea.KeyEsc:
+		p.composing = false
+	default:
+		if p.form.update(key) {
+			return p.send(env)
+		}
+	}
+	return nil
+}
+
+func (p *contactPage) send(env *Env) tea.Cmd {
+	msg, err := env.Outbox.Send(env.Visitor.IP, p.form.value(0), p.form.value(1), p.form.value(2))
+	switch {
+	case errors.Is(err, counter.ErrMessageName), errors.Is(err, counter.ErrMessageReplyTo),
+		errors.Is(err, counter.ErrMessageBody), errors.Is(err, counter.ErrMessageRateLimited):
+		p.form.err = err.Error()
+		return nil
+	case err != nil:
+		env.Logger.Error("Failed to save message", "err", err)
+		p.form.err = "Could not send your message, please try again later."
+		return nil
+	}
+
+	p.composing = false
+	p.form.reset()
+	p.sent = msg.ID
+	p.status = "Sending…"
+	return pollMessageStatus(msg.ID)
+}
+
+// refreshStatus describes the delivery of the sent message and keeps
+// polling while it is pending. Delivery errors stay in the server log.
+func (p *contactPage) refreshStatus(env *Env) tea.Cmd {
+	status, err := env.Outbox.Status(p.sent)
+	if err != nil {
+		p.status = "Your message was saved."
+		return nil
+	}
+	switch status.State {
+	case counter.DeliveryDelivered:
+		p.status = "Delivered ✓"
+	case counter.DeliveryFailed, counter.DeliveryStored:
+		p.status = "Your message could not be delivered right now, but it is saved for the owner to read."
+	default:
+		p.status = "Sending…"
+		if status.Attempts > 0 {
+			p.status = fmt.Sprintf("Delivery is delayed, retrying (attempt %d of %d)…", status.Attempts+1, env.Outbox.MaxAttempts())
+		}
+		return pollMessageStatus(p.sent)
+	}
+	return nil
+}
+
+// CapturesKey sends every key to the form while composing.
+func (p *contactPage) CapturesKey(msg tea.KeyMsg) bool {
+	return p.composing
+}
+
+func (p *contactPage) View(env *Env) string {
+	if p.composing {
+		return RenderMessageForm(env.Styles, p.form.render(env.Styles, boxContentWidth(env.BoxWidth)), p.form.err, env.Help(p.KeyHelp()))
+	}
+	help := p.KeyHelp()
+	if env.Outbox != nil {
+		help = "m: send me a message • " + help
+	}
+	return RenderContact(env.Styles, env.Content.Contact, p.status, env.Help(help))
+}
+
+func (p *contactPage) Title() string       { return "Contact" }
+func (p *contactPage) Description() string { return "Get in touch" }
+
+func (p *contactPage) KeyHelp() string {
+	if p.composing {
+		return "tab: next field • ctrl+j: new line • enter: send • esc: cancel"
+	}
+	return "esc: back to menu"
+}
+
+func RenderContact(styles view.ThemeStyles, contact content.Contact, status string, help string) string {
 	var b strings.Builder
 
 	b.WriteString(styles.Title.Render("━━━ Contact ━━━"))
 	b.WriteString("\n")
 
-	b.WriteString(styles.Content.Render("Feel free to reach out!"))
-	b.WriteString("\n\n")
-
-	githubURL := "https://github.com/andatoshiki"
-	mailtoURL := "mailto:hi@tosh1ki.de"
-	telegramURL := "https://t.me/@andatoshiki"
-	twitterURL := "https://x.com/andatoshiki"
-	mastodonURL := "https://mastodon.social/@andatoshiki"
+	if contact.Greeting != "" {
+		b.WriteString(styles.Content.Render(contact.Greeting))
+		b.WriteString("\n\n")
+	}
 
-	leftLines := []string{
-		styles.Accent.Render("Contacts"),
-		styles.Content.Render(fmt.Sprintf("Email      %s", view.ClickableLink("hi@tosh1ki.de", mailtoURL))),
-		styles.Content.Render(fmt.Sprintf("Telegram   %s", view.ClickableLink("@andatoshiki", telegramURL))),
-		styles.Content.Render(fmt.Sprintf("GitHub     %s", view.ClickableLink("@andatoshiki", githubURL))),
+	columns := make([]string, 0, len(contact.Groups))
+	for _, group := range contact.Groups {
+		lines := []string{styles.Accent.Render(group.Title)}
+		for _, link := range group.Links {
+			text := link.Text
+			if text == "" {
+				text = link.URL
+			}
+			lines = append(lines, styles.Content.Render(fmt.Sprintf("%-*s %s", contactLabelWidth, link.Label, view.ClickableLink(text, link.URL))))
+		}
+		columns = append(columns, lipgloss.NewStyle().Width(contactColumnWidth).Render(strings.Join(lines, "\n")))
 	}
 
-	rightLines := []string{
-		styles.Accent.Render("Social"),
-		styles.Content.Render(fmt.Sprintf("Twitter(X) %s", view.ClickableLink("andatoshiki", twitterURL))),
-		styles.Content.Render(fmt.Sprintf("Mastodon   %s", view.ClickableLink("@andatoshiki", mastodonURL))),
-		"",
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, columns...))
+
+	b.WriteString("\n")
+	if status != "" {
+		b.WriteString("\n")
+		b.WriteString(styles.Accent.Render(status))
+		b.WriteString("\n")
 	}
+	b.WriteString(styles.Help.Render(help))
 
-	leftColumn := lipgloss.NewStyle().Width(32).Render(strings.Join(leftLines, "\n"))
-	rightColumn := lipgloss.NewStyle().Width(32).Render(strings.Join(rightLines, "\n"))
-	columns := lipgloss.JoinHorizontal(lipgloss.Top, leftColumn, rightColumn)
+	return b.String()
+}
 
-	b.WriteString(columns)
+func RenderMessageForm(styles view.ThemeStyles, fields string, errMsg string, help string) string {
+	var b strings.Builder
 
+	b.WriteString(styles.Title.Render("━━━ Send me a Message ━━━"))
 	b.WriteString("\n")
-	b.WriteString(styles.Help.Render(themeLabel + " • esc: back to menu"))
+	b.WriteString(fields)
 
+	b.WriteString(styles.Subtle.Render("Add an email address if you would like a reply."))
+	b.WriteString("\n")
+	if errMsg != "" {
+		b.WriteString(styles.Error.Render(errMsg))
+		b.WriteString("\n")
+	}
+	b.WriteString(styles.Help.Render(help))
 	return b.String()
 }
+
+func (p *contactPage) SearchItems(env *Env) []SearchItem {
+	var items []SearchItem
+	for _, group := range env.Content.Contact.Groups {
+		for _, link := range group.Links {
+			text := link.Text
+			if text == "" {
+				text = link.URL
+			}
+			items = append(items, SearchItem{Title: link.Label + " " + text, Detail: group.Title + " " + link.URL})
+		}
+	}
+	return items
+}
+
+// Focus is a no-op: the contact page shows every entry at once.
+func (p *contactPage) Focus(env *Env, index int) {}
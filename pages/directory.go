@@ -0,0 +1,60 @@
+package pages
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/andatoshiki/termfolio/view"
+)
+
+const (
+	directoryNameWidth    = 24
+	directoryCommandWidth = 40
+)
+
+// DirectoryEntry is a hosted portfolio listed on the landing directory shown
+// to usernames that do not match a tenant.
+type DirectoryEntry struct {
+	Name    string
+	Command string
+}
+
+func RenderDirectory(styles view.ThemeStyles, entries []DirectoryEntry, cursor int, user string, help string) string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("━━━ Directory ━━━"))
+	b.WriteString("\n")
+
+	if user != "" {
+		b.WriteString(styles.Content.Render("No portfolio is hosted for \"" + user + "\"."))
+		b.WriteString("\n")
+	}
+	b.WriteString(styles.Content.Render("This server hosts the portfolios below, connect with:"))
+	b.WriteString("\n\n")
+
+	if len(entries) == 0 {
+		b.WriteString(styles.Subtle.Render("No portfolios are configured."))
+		b.WriteString("\n")
+	}
+
+	for i, entry := range entries {
+		cursorMark := "  "
+		leftStyle := styles.Menu
+		rightStyle := styles.Subtle
+		if i == cursor {
+			cursorMark = "→ "
+			leftStyle = styles.Selected
+			rightStyle = styles.Accent.Copy().Bold(false)
+		}
+
+		leftCell := lipgloss.NewStyle().Width(directoryNameWidth).Render(cursorMark + truncate(entry.Name, directoryNameWidth-3))
+		rightCell := lipgloss.NewStyle().Width(directoryCommandWidth).Render(entry.Command)
+		b.WriteString(leftStyle.Render(leftCell) + rightStyle.Render(rightCell))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(styles.Help.Render(help))
+
+	return b.String()
+}
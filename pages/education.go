@@ -4,67 +4,48 @@ import (
 	"fmt"
 	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/andatoshiki/termfolio/content"
 	"github.com/andatoshiki/termfolio/view"
 )
 
-type Education struct {
-	Role    string
-	Company string
-	Period  string
-	Desc    string
-	URL     string
+type educationPage struct {
+	cursor int
+}
+
+func (p *educationPage) Init(env *Env) tea.Cmd {
+	return nil
 }
 
-var educations = []Education{
-	{
-		Role:    "Computer Science, B.S.",
-		Company: "Northwestern",
-		Period:  "2025–present",
-		Desc:    "McCormick School of Engineering",
-		URL:     "https://www.northwestern.edu/",
-	},
-	{
-		Role:    "Asian Languages, Japanese, Minor",
-		Company: "ASU",
-		Period:  "2023–present",
-		Desc:    "W. P. Carey School of Business",
-		URL:     "https://wpcarey.asu.edu/",
-	},
-	{
-		Role:    "Finance & Business, Minor",
-		Company: "ASU",
-		Period:  "2023–present",
-		Desc:    "The College of Liberal Arts and Sciences",
-		URL:     "https://thecollege.asu.edu/",
-	},
-	{
-		Role:    "Data Science, B.S.",
-		Company: "ASU",
-		Period:  "2023–2025",
-		Desc:    "Ira A. Fulton Schools of Engineering",
-		URL:     "https://engineering.asu.edu/",
-	},
-	{
-		Role:    "Computer Science, B.S.",
-		Company: "ASU",
-		Period:  "2023–2025",
-		Desc:    "Ira A. Fulton Schools of Engineering",
-		URL:     "https://engineering.asu.edu/",
-	},
-	{
-		Role:    "Global Launch Intl. Program",
-		Company: "ASU",
-		Period:  "2023–2024",
-		Desc:    "Arizona State University, Global Launch",
-		URL:     "https://www.asu.edu/",
-	},
+func (p *educationPage) Update(env *Env, msg tea.Msg) (Page, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+	p.cursor = clampCursor(p.cursor, len(env.Content.Education))
+	switch key.String() {
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(env.Content.Education)-1 {
+			p.cursor++
+		}
+	}
+	return p, nil
 }
 
-func Educations() []Education {
-	return educations
+func (p *educationPage) View(env *Env) string {
+	return RenderEducation(env.Styles, env.Content.Education, p.cursor, env.Help(p.KeyHelp()))
 }
 
-func RenderEducation(styles view.ThemeStyles, eduCursor int, themeLabel string) string {
+func (p *educationPage) Title() string       { return "Education" }
+func (p *educationPage) Description() string { return "Academic timeline" }
+func (p *educationPage) KeyHelp() string     { return "↑/↓: browse • esc: back to menu" }
+
+func RenderEducation(styles view.ThemeStyles, educations []content.Education, eduCursor int, help string) string {
 	var b strings.Builder
 
 	b.WriteString(styles.Title.Render("━━━ Education ━━━"))
@@ -103,7 +84,6 @@ func RenderEducation(styles view.ThemeStyles, eduCursor int, themeLabel string)
 		b.WriteString("\n")
 	}
 
-	help := themeLabel + " • ↑/↓: browse • esc: back to menu"
 	if end < len(educations) {
 		moreStyle := styles.Accent.Copy().Faint(true)
 		b.WriteString(moreStyle.Render("more below!"))
@@ -139,3 +119,15 @@ func educationWindow(cursor, total, pageSize int) (start, end int) {
 	}
 	return start, end
 }
+
+func (p *educationPage) SearchItems(env *Env) []SearchItem {
+	items := make([]SearchItem, 0, len(env.Content.Education))
+	for i, edu := range env.Content.Education {
+		items = append(items, SearchItem{Title: edu.Role + " @ " + edu.Company, Detail: edu.Period + " " + edu.Desc, Index: i})
+	}
+	return items
+}
+
+func (p *educationPage) Focus(env *Env, index int) {
+	p.cursor = clampCursor(index, len(env.Content.Education))
+}
@@ -4,42 +4,48 @@ import (
 	"fmt"
 	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/andatoshiki/termfolio/content"
 	"github.com/andatoshiki/termfolio/view"
 )
 
-type Experience struct {
-	Role    string
-	Company string
-	Period  string
-	Desc    string
+type experiencePage struct {
+	cursor int
+}
+
+func (p *experiencePage) Init(env *Env) tea.Cmd {
+	return nil
 }
 
-var experiences = []Experience{
-	{
-		Role:    "Software Engineer",
-		Company: "Microsoft",
-		Period:  "2025 - Present",
-		Desc:    "Azure SQL VM team",
-	},
-	{
-		Role:    "Software Engineer Intern",
-		Company: "Jenni AI",
-		Period:  "2024 - 2025",
-		Desc:    "Developed new product that reviews manuscripts for Jenni AI",
-	},
-	{
-		Role:    "Software Engineer Intern",
-		Company: "Blue Origin",
-		Period:  "Fall 2023",
-		Desc:    "New Glenn Rocket Software",
-	},
+func (p *experiencePage) Update(env *Env, msg tea.Msg) (Page, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+	p.cursor = clampCursor(p.cursor, len(env.Content.Experience))
+	switch key.String() {
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(env.Content.Experience)-1 {
+			p.cursor++
+		}
+	}
+	return p, nil
 }
 
-func Experiences() []Experience {
-	return experiences
+func (p *experiencePage) View(env *Env) string {
+	return RenderExperience(env.Styles, env.Content.Experience, p.cursor, env.Help(p.KeyHelp()))
 }
 
-func RenderExperience(styles view.ThemeStyles, expCursor int, themeLabel string) string {
+func (p *experiencePage) Title() string       { return "Experience" }
+func (p *experiencePage) Description() string { return "Work history" }
+func (p *experiencePage) KeyHelp() string     { return "↑/↓: browse • esc: back to menu" }
+
+func RenderExperience(styles view.ThemeStyles, experiences []content.Experience, expCursor int, help string) string {
 	var b strings.Builder
 
 	b.WriteString(styles.Title.Render("━━━ Experience ━━━"))
@@ -70,8 +76,19 @@ func RenderExperience(styles view.ThemeStyles, expCursor int, themeLabel string)
 		b.WriteString("\n")
 	}
 
-	help := themeLabel + " • ↑/↓: browse • esc: back to menu"
 	b.WriteString(styles.Help.Render(help))
 
 	return b.String()
 }
+
+func (p *experiencePage) SearchItems(env *Env) []SearchItem {
+	items := make([]SearchItem, 0, len(env.Content.Experience))
+	for i, exp := range env.Content.Experience {
+		items = append(items, SearchItem{Title: exp.Role + " @ " + exp.Company, Detail: exp.Period + " " + exp.Desc, Index: i})
+	}
+	return items
+}
+
+func (p *experiencePage) Focus(env *Env, index int) {
+	p.cursor = clampCursor(index, len(env.Content.Experience))
+}
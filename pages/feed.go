@@ -1,25 +1,162 @@
 package pages
 
 import (
+	"context"
+	"log/slog"
 	"strings"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/andatoshiki/termfolio/feed"
 	"github.com/andatoshiki/termfolio/view"
 )
 
 const (
 	feedLeftWidth  = 52
 	feedRightWidth = 12
+	feedPageSize   = 8
 )
 
-type FeedItem struct {
-	Title string
-	Link  string
-	Date  string
+type feedMsg struct {
+	items []feed.Item
+	err   error
 }
 
-func RenderFeed(styles view.ThemeStyles, items []FeedItem, cursor int, offset int, pageSize int, loading bool, errMsg string, themeLabel string) string {
+func (feedMsg) PageID() string { return FeedID }
+
+type refreshFeedMsg struct{}
+
+func (refreshFeedMsg) PageID() string { return FeedID }
+
+// RefreshFeed makes the feed page fetch the feed again, ignoring the cache.
+func RefreshFeed() tea.Cmd {
+	return func() tea.Msg {
+		return refreshFeedMsg{}
+	}
+}
+
+type feedPage struct {
+	items     []feed.Item
+	cursor    int
+	offset    int
+	loading   bool
+	err       string
+	fetchedAt time.Time
+}
+
+func (p *feedPage) Init(env *Env) tea.Cmd {
+	p.cursor = 0
+	p.offset = 0
+	if p.shouldFetch() {
+		p.loading = true
+		p.err = ""
+		return fetchFeedCmd(env.Logger)
+	}
+	return nil
+}
+
+func (p *feedPage) Update(env *Env, msg tea.Msg) (Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case refreshFeedMsg:
+		if p.loading {
+			return p, nil
+		}
+		p.loading = true
+		p.err = ""
+		return p, fetchFeedCmd(env.Logger)
+
+	case feedMsg:
+		p.loading = false
+		if msg.err != nil {
+			p.err = msg.err.Error()
+			return p, nil
+		}
+		p.err = ""
+		p.items = msg.items
+		p.fetchedAt = time.Now()
+		if len(p.items) == 0 {
+			p.cursor = 0
+			p.offset = 0
+			return p, nil
+		}
+		if p.cursor >= len(p.items) {
+			p.cursor = 0
+		}
+		p.adjustWindow()
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if p.cursor > 0 {
+				p.cursor--
+			}
+			p.adjustWindow()
+		case "down", "j":
+			if p.cursor < len(p.items)-1 {
+				p.cursor++
+			}
+			p.adjustWindow()
+		}
+	}
+	return p, nil
+}
+
+func (p *feedPage) View(env *Env) string {
+	return RenderFeed(env.Styles, p.items, p.cursor, p.offset, feedPageSize, p.loading, p.err, env.Help(p.KeyHelp()))
+}
+
+func (p *feedPage) Title() string       { return "Feed" }
+func (p *feedPage) Description() string { return "Latest posts" }
+func (p *feedPage) KeyHelp() string     { return "↑/↓: browse • esc: back to menu" }
+
+func (p *feedPage) shouldFetch() bool {
+	if p.loading {
+		return false
+	}
+	if len(p.items) == 0 {
+		return true
+	}
+	if p.fetchedAt.IsZero() {
+		return true
+	}
+	return time.Since(p.fetchedAt) > feed.CacheTTL
+}
+
+func (p *feedPage) adjustWindow() {
+	if feedPageSize <= 0 || len(p.items) == 0 {
+		p.offset = 0
+		return
+	}
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	} else if p.cursor >= p.offset+feedPageSize {
+		p.offset = p.cursor - feedPageSize + 1
+	}
+	if p.offset < 0 {
+		p.offset = 0
+	}
+	maxOffset := max(0, len(p.items)-feedPageSize)
+	if p.offset > maxOffset {
+		p.offset = maxOffset
+	}
+}
+
+func fetchFeedCmd(logger *slog.Logger) tea.Cmd {
+	return func() tea.Msg {
+		start := time.Now()
+		items, err := feed.Fetch(context.Background())
+		if err != nil {
+			logger.Warn("Failed to fetch feed", "err", err)
+		} else {
+			logger.Debug("Fetched feed", "items", len(items), "duration_ms", time.Since(start).Milliseconds())
+		}
+		return feedMsg{items: items, err: err}
+	}
+}
+
+func RenderFeed(styles view.ThemeStyles, items []feed.Item, cursor int, offset int, pageSize int, loading bool, errMsg string, help string) string {
 	var b strings.Builder
 
 	b.WriteString(styles.Title.Render("━━━ Feed ━━━"))
@@ -28,7 +165,7 @@ func RenderFeed(styles view.ThemeStyles, items []FeedItem, cursor int, offset in
 	if loading {
 		b.WriteString(styles.Subtle.Render("Loading feed..."))
 		b.WriteString("\n")
-		b.WriteString(styles.Help.Render(themeLabel + " • esc: back to menu"))
+		b.WriteString(styles.Help.Render(help))
 		return b.String()
 	}
 
@@ -37,14 +174,14 @@ func RenderFeed(styles view.ThemeStyles, items []FeedItem, cursor int, offset in
 		b.WriteString("\n")
 		b.WriteString(styles.Subtle.Render(errMsg))
 		b.WriteString("\n")
-		b.WriteString(styles.Help.Render(themeLabel + " • esc: back to menu"))
+		b.WriteString(styles.Help.Render(help))
 		return b.String()
 	}
 
 	if len(items) == 0 {
 		b.WriteString(styles.Subtle.Render("No posts found."))
 		b.WriteString("\n")
-		b.WriteString(styles.Help.Render(themeLabel + " • esc: back to menu"))
+		b.WriteString(styles.Help.Render(help))
 		return b.String()
 	}
 
@@ -94,7 +231,6 @@ func RenderFeed(styles view.ThemeStyles, items []FeedItem, cursor int, offset in
 		b.WriteString("\n")
 	}
 
-	help := themeLabel + " • ↑/↓: browse • esc: back to menu"
 	b.WriteString(styles.Help.Render("\n" + help))
 
 	return b.String()
@@ -113,3 +249,17 @@ func truncate(value string, max int) string {
 	}
 	return string(runes[:max-3]) + "..."
 }
+
+// SearchItems lists the posts fetched so far in this session.
+func (p *feedPage) SearchItems(env *Env) []SearchItem {
+	items := make([]SearchItem, 0, len(p.items))
+	for i, item := range p.items {
+		items = append(items, SearchItem{Title: item.Title, Detail: item.Date, Index: i})
+	}
+	return items
+}
+
+func (p *feedPage) Focus(env *Env, index int) {
+	p.cursor = clampCursor(index, len(p.items))
+	p.adjustWindow()
+}
@@ -0,0 +1,108 @@
+package pages
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/andatoshiki/termfolio/view"
+)
+
+// textField is a hand-rolled text input. Multiline fields take ctrl+j or
+// alt+enter as a line break, since enter moves on.
+type textField struct {
+	label     string
+	value     []rune
+	limit     int
+	multiline bool
+}
+
+// form is a list of text fields edited one at a time. Enter moves to the
+// next field and submits on the last one.
+type form struct {
+	fields []textField
+	focus  int
+	err    string
+}
+
+func newForm(fields ...textField) form {
+	return form{fields: fields}
+}
+
+// update handles a key and reports whether the form was submitted.
+func (f *form) update(key tea.KeyMsg) bool {
+	field := &f.fields[f.focus]
+	switch key.Type {
+	case tea.KeyTab, tea.KeyDown:
+		f.focus = (f.focus + 1) % len(f.fields)
+	case tea.KeyShiftTab, tea.KeyUp:
+		f.focus = (f.focus + len(f.fields) - 1) % len(f.fields)
+	case tea.KeyEnter:
+		if key.Alt && field.multiline {
+			field.insert([]rune{'\n'})
+			return false
+		}
+		if f.focus < len(f.fields)-1 {
+			f.focus++
+			return false
+		}
+		return true
+	case tea.KeyCtrlJ:
+		if field.multiline {
+			field.insert([]rune{'\n'})
+		}
+	case tea.KeyBackspace:
+		if len(field.value) > 0 {
+			field.value = field.value[:len(field.value)-1]
+		}
+	case tea.KeyCtrlU:
+		field.value = nil
+	case tea.KeyRunes, tea.KeySpace:
+		field.insert(key.Runes)
+	}
+	return false
+}
+
+func (t *textField) insert(runes []rune) {
+	if len(t.value)+len(runes) <= t.limit {
+		t.value = append(t.value, runes...)
+	}
+}
+
+func (f *form) value(i int) string {
+	return string(f.fields[i].value)
+}
+
+// reset clears every field and moves back to the first.
+func (f *form) reset() {
+	for i := range f.fields {
+		f.fields[i].value = nil
+	}
+	f.focus = 0
+	f.err = ""
+}
+
+// render draws the fields with a character count and a cursor on the
+// focused one.
+func (f *form) render(styles view.ThemeStyles, width int) string {
+	var b strings.Builder
+	for i, field := range f.fields {
+		active := i == f.focus
+		labelStyle := styles.Subtle
+		if active {
+			labelStyle = styles.Accent
+		}
+		b.WriteString(labelStyle.Render(field.label))
+		b.WriteString(styles.Period.Render(fmt.Sprintf(" %d/%d", len(field.value), field.limit)))
+		b.WriteString("\n")
+		text := styles.Content.Render(string(field.value))
+		if active {
+			text += styles.Accent.Render("▏")
+		}
+		b.WriteString(lipgloss.NewStyle().PaddingLeft(2).Width(width).Render(text))
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
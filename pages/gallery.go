@@ -0,0 +1,96 @@
+package pages
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/andatoshiki/termfolio/content"
+	"github.com/andatoshiki/termfolio/view"
+)
+
+type galleryPage struct {
+	cursor int
+}
+
+func (p *galleryPage) Init(env *Env) tea.Cmd {
+	return nil
+}
+
+func (p *galleryPage) Update(env *Env, msg tea.Msg) (Page, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+	p.cursor = clampCursor(p.cursor, len(env.Content.Gallery))
+	switch key.String() {
+	case "left", "h", "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "right", "l", "down", "j":
+		if p.cursor < len(env.Content.Gallery)-1 {
+			p.cursor++
+		}
+	}
+	return p, nil
+}
+
+func (p *galleryPage) View(env *Env) string {
+	return RenderGallery(env.Styles, env.Content.Gallery, p.cursor, env.Help(p.KeyHelp()), env.BoxWidth, galleryImageHeight(env.Height), env.ColorProfile)
+}
+
+// Available hides the gallery from the default menu until it has pictures.
+func (p *galleryPage) Available(env *Env) bool {
+	return len(env.Content.Gallery) > 0
+}
+
+func (p *galleryPage) Title() string       { return "Gallery" }
+func (p *galleryPage) Description() string { return "Pictures and artwork" }
+func (p *galleryPage) KeyHelp() string     { return "←/→: browse • esc: back to menu" }
+
+// galleryImageHeight leaves room for the title, caption and help line.
+func galleryImageHeight(termHeight int) int {
+	if termHeight <= 0 {
+		return 0
+	}
+	height := termHeight - 12
+	if height < 4 {
+		height = 4
+	}
+	return height
+}
+
+func RenderGallery(styles view.ThemeStyles, gallery []content.Picture, cursor int, help string, boxWidth int, height int, profile termenv.Profile) string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("━━━ Gallery ━━━"))
+	b.WriteString("\n")
+
+	if len(gallery) == 0 {
+		b.WriteString(styles.Subtle.Render("Nothing here yet."))
+		b.WriteString("\n")
+		b.WriteString(styles.Help.Render(help))
+		return b.String()
+	}
+
+	cursor = clampCursor(cursor, len(gallery))
+	pic := gallery[cursor]
+	width := boxContentWidth(boxWidth)
+	center := lipgloss.NewStyle().Width(width).Align(lipgloss.Center)
+
+	b.WriteString(center.Render(renderPicture(styles, pic, width, height, profile)))
+	b.WriteString("\n")
+	if pic.Caption != "" {
+		b.WriteString(center.Render(styles.Period.Render(pic.Caption)))
+		b.WriteString("\n")
+	}
+	b.WriteString(center.Render(styles.Subtle.Render(fmt.Sprintf("%d/%d", cursor+1, len(gallery)))))
+	b.WriteString("\n")
+	b.WriteString(styles.Help.Render(help))
+
+	return b.String()
+}
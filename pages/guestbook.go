@@ -0,0 +1,210 @@
+package pages
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/andatoshiki/termfolio/counter"
+	"github.com/andatoshiki/termfolio/view"
+)
+
+const guestbookPageSize = 4
+
+type guestbookPage struct {
+	entries []counter.GuestbookEntry
+	total   int
+	page    int
+	err     string
+	notice  string
+
+	composing bool
+	form      form
+}
+
+func (p *guestbookPage) Init(env *Env) tea.Cmd {
+	p.page = 0
+	p.notice = ""
+	p.composing = false
+	p.load(env)
+	return nil
+}
+
+func (p *guestbookPage) Update(env *Env, msg tea.Msg) (Page, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+	if p.composing {
+		return p, p.updateForm(env, key)
+	}
+	switch key.String() {
+	case "left", "h", "up", "k":
+		if p.page > 0 {
+			p.page--
+			p.load(env)
+		}
+	case "right", "l", "down", "j":
+		if (p.page+1)*guestbookPageSize < p.total {
+			p.page++
+			p.load(env)
+		}
+	case "s", "enter":
+		if env.TrackingAvailable() {
+			if p.form.fields == nil {
+				p.form = newForm(
+					textField{label: "Name", limit: counter.GuestbookMaxName},
+					textField{label: "Message", limit: counter.GuestbookMaxMessage},
+				)
+			}
+			p.composing = true
+			p.form.focus = 0
+			p.form.err = ""
+			p.notice = ""
+		}
+	}
+	return p, nil
+}
+
+func (p *guestbookPage) updateForm(env *Env, key tea.KeyMsg) tea.Cmd {
+	switch key.Type {
+	case tea.KeyCtrlC:
+		return env.Quit()
+	case tea.KeyEsc:
+		p.composing = false
+	default:
+		if p.form.update(key) {
+			p.submit(env)
+		}
+	}
+	return nil
+}
+
+func (p *guestbookPage) submit(env *Env) {
+	entry, err := env.Counter.SignGuestbook(env.Visitor.IP, p.form.value(0), p.form.value(1))
+	switch {
+	case errors.Is(err, counter.ErrGuestbookName), errors.Is(err, counter.ErrGuestbookMessage),
+		errors.Is(err, counter.ErrGuestbookProfanity), errors.Is(err, counter.ErrGuestbookRateLimited):
+		p.form.err = err.Error()
+		return
+	case err != nil:
+		env.Logger.Error("Failed to sign guestbook", "err", err)
+		p.form.err = "Could not save your message, please try again later."
+		return
+	}
+
+	p.composing = false
+	p.form.reset()
+	p.notice = fmt.Sprintf("Thanks, %s! Your message will appear once it is approved.", entry.Name)
+}
+
+func (p *guestbookPage) load(env *Env) {
+	p.err = ""
+	if env.Counter == nil {
+		p.entries, p.total = nil, 0
+		return
+	}
+	entries, total, err := env.Counter.GuestbookEntries(counter.GuestbookApproved, p.page*guestbookPageSize, guestbookPageSize)
+	if err != nil {
+		env.Logger.Error("Failed to load guestbook", "err", err)
+		p.err = "Could not load the guestbook."
+		return
+	}
+	p.entries, p.total = entries, total
+}
+
+// CapturesKey sends every key to the form while composing, so typing q, t,
+// / or : does not trigger the router.
+func (p *guestbookPage) CapturesKey(msg tea.KeyMsg) bool {
+	return p.composing
+}
+
+func (p *guestbookPage) View(env *Env) string {
+	if p.composing {
+		return RenderGuestbookForm(env.Styles, p.form.render(env.Styles, boxContentWidth(env.BoxWidth)), p.form.err, env.Help(p.KeyHelp()))
+	}
+	return RenderGuestbook(env.Styles, p.entries, p.page, p.total, p.notice, p.err, env.TrackingAvailable(), env.Help(p.KeyHelp()), env.BoxWidth)
+}
+
+// Available hides the guestbook when there is no store to keep entries in.
+func (p *guestbookPage) Available(env *Env) bool {
+	return env.Counter != nil
+}
+
+func (p *guestbookPage) Title() string       { return "Guestbook" }
+func (p *guestbookPage) Description() string { return "Leave a message" }
+
+func (p *guestbookPage) KeyHelp() string {
+	if p.composing {
+		return "tab: next field • enter: send • esc: cancel"
+	}
+	return "←/→: page • s: sign • esc: back to menu"
+}
+
+func RenderGuestbook(styles view.ThemeStyles, entries []counter.GuestbookEntry, page int, total int, notice string, errMsg string, canSign bool, help string, boxWidth int) string {
+	var b strings.Builder
+	width := boxContentWidth(boxWidth)
+
+	b.WriteString(styles.Title.Render("━━━ Guestbook ━━━"))
+	b.WriteString("\n")
+
+	if notice != "" {
+		b.WriteString(styles.Accent.Render(notice))
+		b.WriteString("\n\n")
+	}
+
+	switch {
+	case errMsg != "":
+		b.WriteString(styles.Subtle.Render(errMsg))
+		b.WriteString("\n")
+	case len(entries) == 0:
+		b.WriteString(styles.Subtle.Render("No messages yet."))
+		if canSign {
+			b.WriteString(styles.Subtle.Render(" Press s to be the first!"))
+		}
+		b.WriteString("\n")
+	default:
+		for i, entry := range entries {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(styles.Role.Render(entry.Name))
+			b.WriteString(" ")
+			b.WriteString(styles.Period.Render(entry.CreatedAt.Format("Jan 2, 2006")))
+			b.WriteString("\n")
+			b.WriteString(lipgloss.NewStyle().PaddingLeft(2).Width(width).Render(styles.Content.Render(entry.Message)))
+			b.WriteString("\n")
+		}
+		if pages := (total + guestbookPageSize - 1) / guestbookPageSize; pages > 1 {
+			b.WriteString("\n")
+			b.WriteString(styles.Subtle.Render(fmt.Sprintf("page %d/%d", page+1, pages)))
+			b.WriteString("\n")
+		}
+	}
+
+	if !canSign {
+		help = strings.Replace(help, " • s: sign", "", 1)
+	}
+	b.WriteString(styles.Help.Render(help))
+	return b.String()
+}
+
+func RenderGuestbookForm(styles view.ThemeStyles, fields string, errMsg string, help string) string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("━━━ Sign the Guestbook ━━━"))
+	b.WriteString("\n")
+	b.WriteString(fields)
+
+	b.WriteString(styles.Subtle.Render("Messages are shown after the owner approves them."))
+	b.WriteString("\n")
+	if errMsg != "" {
+		b.WriteString(styles.Error.Render(errMsg))
+		b.WriteString("\n")
+	}
+	b.WriteString(styles.Help.Render(help))
+	return b.String()
+}
@@ -0,0 +1,309 @@
+package pages
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/andatoshiki/termfolio/chat"
+	"github.com/andatoshiki/termfolio/view"
+)
+
+const (
+	lobbyPageScroll     = 10
+	defaultMuteDuration = 10 * time.Minute
+)
+
+// LobbyMsg tells the lobby page that the room changed. The page re-reads
+// the scrollback, so messages may arrive in any order or be coalesced.
+type LobbyMsg struct{}
+
+func (LobbyMsg) PageID() string { return LobbyID }
+
+type lobbyPage struct {
+	joined bool
+	nick   []rune
+	input  []rune
+	err    string
+	notice string
+	// scroll is how many lines the view is scrolled up from the newest.
+	scroll int
+}
+
+func (p *lobbyPage) Init(env *Env) tea.Cmd {
+	p.scroll = 0
+	p.err = ""
+	p.notice = ""
+	return nil
+}
+
+func (p *lobbyPage) Update(env *Env, msg tea.Msg) (Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case LobbyMsg:
+		if p.joined {
+			if _, ok := env.Lobby.Member(); !ok {
+				p.joined = false
+				p.input = nil
+				p.err = ""
+				p.notice = "You were removed from the lobby by the owner."
+			}
+		}
+	case tea.KeyMsg:
+		return p, p.updateKey(env, msg)
+	}
+	return p, nil
+}
+
+func (p *lobbyPage) updateKey(env *Env, key tea.KeyMsg) tea.Cmd {
+	field := &p.input
+	limit := chat.MaxText
+	if !p.joined {
+		field = &p.nick
+		limit = chat.MaxNick
+	}
+
+	switch key.Type {
+	case tea.KeyCtrlC:
+		return env.Quit()
+	case tea.KeyEsc:
+		return Navigate(MenuID)
+	case tea.KeyEnter:
+		if p.joined {
+			p.send(env)
+		} else {
+			p.join(env)
+		}
+	case tea.KeyUp:
+		p.scroll++
+	case tea.KeyPgUp:
+		p.scroll += lobbyPageScroll
+	case tea.KeyDown:
+		p.scroll = max(p.scroll-1, 0)
+	case tea.KeyPgDown:
+		p.scroll = max(p.scroll-lobbyPageScroll, 0)
+	case tea.KeyBackspace:
+		if len(*field) > 0 {
+			*field = (*field)[:len(*field)-1]
+		}
+	case tea.KeyCtrlU:
+		*field = nil
+	case tea.KeyRunes, tea.KeySpace:
+		p.err = ""
+		if len(*field)+len(key.Runes) <= limit {
+			*field = append(*field, key.Runes...)
+		}
+	}
+	return nil
+}
+
+func (p *lobbyPage) join(env *Env) {
+	if err := env.Lobby.Join(string(p.nick)); err != nil {
+		p.err = err.Error()
+		return
+	}
+	p.joined = true
+	p.err = ""
+	p.notice = ""
+	p.scroll = 0
+}
+
+func (p *lobbyPage) send(env *Env) {
+	text := strings.TrimSpace(string(p.input))
+	if text == "" {
+		return
+	}
+	var err error
+	if strings.HasPrefix(text, "/") {
+		err = p.command(env, strings.Fields(text))
+	} else {
+		err = env.Lobby.Say(text)
+	}
+	if err != nil {
+		p.err = err.Error()
+		return
+	}
+	p.input = nil
+	p.err = ""
+	p.scroll = 0
+}
+
+// command runs the owner's moderation commands. The hub only returns
+// errors meant for the visitor, so they are shown as they are.
+func (p *lobbyPage) command(env *Env, fields []string) error {
+	switch fields[0] {
+	case "/kick":
+		if len(fields) != 2 {
+			return errors.New("usage: /kick <nick>")
+		}
+		return env.Lobby.Kick(fields[1])
+	case "/mute":
+		if len(fields) < 2 || len(fields) > 3 {
+			return errors.New("usage: /mute <nick> [duration]")
+		}
+		d := defaultMuteDuration
+		if len(fields) == 3 {
+			parsed, err := time.ParseDuration(fields[2])
+			if err != nil || parsed <= 0 {
+				return fmt.Errorf("invalid duration %q", fields[2])
+			}
+			d = parsed
+		}
+		return env.Lobby.Mute(fields[1], d)
+	case "/unmute":
+		if len(fields) != 2 {
+			return errors.New("usage: /unmute <nick>")
+		}
+		return env.Lobby.Mute(fields[1], 0)
+	}
+	return fmt.Errorf("unknown command %s", fields[0])
+}
+
+// Leave gives up the nickname when the visitor leaves the page.
+func (p *lobbyPage) Leave(env *Env) {
+	if p.joined {
+		env.Lobby.Leave()
+		p.joined = false
+	}
+}
+
+// CapturesKey takes every key for the input line, including q, t, / and
+// :. Esc still leaves the page.
+func (p *lobbyPage) CapturesKey(msg tea.KeyMsg) bool {
+	return true
+}
+
+// Available hides the lobby unless it is enabled in the config.
+func (p *lobbyPage) Available(env *Env) bool {
+	return env.Lobby != nil
+}
+
+// View leaves out the theme hint from the help line, since t is typed
+// into the input here.
+func (p *lobbyPage) View(env *Env) string {
+	height := max(env.Height-14, 5)
+	if !p.joined {
+		return RenderLobbyJoin(env.Styles, env.Lobby.Members(), string(p.nick), p.notice, p.err, p.KeyHelp(), env.BoxWidth)
+	}
+	member, _ := env.Lobby.Member()
+	return RenderLobby(env.Styles, env.Lobby.History(), env.Lobby.Members(), member, string(p.input), p.scroll, height, p.err, p.KeyHelp(), env.BoxWidth)
+}
+
+func (p *lobbyPage) Title() string       { return "Lobby" }
+func (p *lobbyPage) Description() string { return "Chat with visitors" }
+
+func (p *lobbyPage) KeyHelp() string {
+	if !p.joined {
+		return "enter: join • esc: back to menu"
+	}
+	return "enter: send • ↑/↓ pgup/pgdn: scroll • esc: leave"
+}
+
+func RenderLobbyJoin(styles view.ThemeStyles, members []chat.Member, nick string, notice string, errMsg string, help string, boxWidth int) string {
+	var b strings.Builder
+	width := boxContentWidth(boxWidth)
+
+	b.WriteString(styles.Title.Render("━━━ Lobby ━━━"))
+	b.WriteString("\n")
+	b.WriteString(lipgloss.NewStyle().Width(width).Render(styles.Content.Render(
+		"Chat with whoever else is browsing right now. Nothing here is saved.")))
+	b.WriteString("\n\n")
+	b.WriteString(styles.Subtle.Render(lobbyMembersLine(members)))
+	b.WriteString("\n\n")
+
+	if notice != "" {
+		b.WriteString(styles.Error.Render(notice))
+		b.WriteString("\n\n")
+	}
+	b.WriteString(styles.Accent.Render("Nickname"))
+	b.WriteString(styles.Period.Render(fmt.Sprintf(" %d/%d", len([]rune(nick)), chat.MaxNick)))
+	b.WriteString("\n  ")
+	b.WriteString(styles.Content.Render(nick))
+	b.WriteString(styles.Accent.Render("▏"))
+	b.WriteString("\n\n")
+	if errMsg != "" {
+		b.WriteString(styles.Error.Render(errMsg))
+		b.WriteString("\n")
+	}
+	b.WriteString(styles.Help.Render(help))
+	return b.String()
+}
+
+func RenderLobby(styles view.ThemeStyles, history []chat.Message, members []chat.Member, me chat.Member, input string, scroll int, height int, errMsg string, help string, boxWidth int) string {
+	var b strings.Builder
+	width := boxContentWidth(boxWidth)
+
+	b.WriteString(styles.Title.Render("━━━ Lobby ━━━"))
+	b.WriteString("\n")
+	b.WriteString(styles.Subtle.Render(lobbyMembersLine(members)))
+	b.WriteString("\n\n")
+
+	var lines []string
+	for _, msg := range history {
+		stamp := styles.Period.Render(msg.At.Format("15:04") + " ")
+		var line string
+		if msg.Nick == "" {
+			line = stamp + styles.Subtle.Render("· "+msg.Text)
+		} else {
+			line = stamp + lobbyNick(styles, chat.Member{Nick: msg.Nick, Owner: msg.Owner}) + " " + styles.Content.Render(msg.Text)
+		}
+		wrapped := lipgloss.NewStyle().Width(width).Render(line)
+		lines = append(lines, strings.Split(wrapped, "\n")...)
+	}
+
+	scroll = min(scroll, max(len(lines)-height, 0))
+	end := len(lines) - scroll
+	start := max(end-height, 0)
+	window := lines[start:end]
+	for i := len(window); i < height; i++ {
+		b.WriteString("\n")
+	}
+	for _, line := range window {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	if scroll > 0 {
+		b.WriteString(styles.Subtle.Render(fmt.Sprintf("↓ %d more lines", scroll)))
+	}
+	b.WriteString("\n")
+
+	b.WriteString(lobbyNick(styles, me))
+	b.WriteString(styles.Accent.Render(" › "))
+	b.WriteString(styles.Content.Render(input))
+	b.WriteString(styles.Accent.Render("▏"))
+	b.WriteString("\n")
+	if errMsg != "" {
+		b.WriteString(styles.Error.Render(errMsg))
+		b.WriteString("\n")
+	}
+	if me.Owner {
+		help += " • /kick, /mute, /unmute <nick>"
+	}
+	b.WriteString(styles.Help.Render(help))
+	return b.String()
+}
+
+func lobbyMembersLine(members []chat.Member) string {
+	if len(members) == 0 {
+		return "No one is here yet."
+	}
+	nicks := make([]string, len(members))
+	for i, m := range members {
+		nicks[i] = m.Nick
+		if m.Owner {
+			nicks[i] = "★" + m.Nick
+		}
+	}
+	return fmt.Sprintf("%d here: %s", len(members), strings.Join(nicks, ", "))
+}
+
+// lobbyNick marks the owner with a star so visitors cannot impersonate them.
+func lobbyNick(styles view.ThemeStyles, m chat.Member) string {
+	if m.Owner {
+		return styles.Accent.Render("★" + m.Nick)
+	}
+	return styles.Role.Render(m.Nick)
+}
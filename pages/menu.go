@@ -3,41 +3,107 @@ package pages
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/andatoshiki/termfolio/view"
 )
 
-var menuItems = []string{"About", "Projects", "Education", "Contact", "Feed", "Privacy"}
-var menuDescriptions = []string{
-	"Who I am",
-	"Selected work",
-	"Academic timeline",
-	"Get in touch",
-	"Latest posts",
-	"Tracking control",
-}
-
 const (
 	menuLeftWidth  = 16
 	menuRightWidth = 40
+	menuTick       = 50 * time.Millisecond
 )
 
-func MenuItems() []string {
-	return menuItems
+// MenuEntry is one menu row, generated from a registered page.
+type MenuEntry struct {
+	ID          string
+	Title       string
+	Description string
+}
+
+type menuTickMsg struct {
+	gen int
+}
+
+type menuPage struct {
+	cursor int
+	sweep  int
+	gen    int
+}
+
+// NewMenu returns the menu page. It lists env.Menu, which the router builds
+// from the registry and the content file's menu block.
+func NewMenu() Page {
+	return &menuPage{}
+}
+
+func (p *menuPage) Init(env *Env) tea.Cmd {
+	p.gen++
+	return menuTickCmd(p.gen)
+}
+
+func (p *menuPage) Update(env *Env, msg tea.Msg) (Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case menuTickMsg:
+		if msg.gen != p.gen {
+			return p, nil
+		}
+		p.sweep++
+		return p, menuTickCmd(p.gen)
+
+	case tea.KeyMsg:
+		p.cursor = clampCursor(p.cursor, len(env.Menu))
+		switch msg.String() {
+		case "up", "k":
+			if p.cursor > 0 {
+				p.cursor--
+			}
+		case "down", "j":
+			if p.cursor < len(env.Menu)-1 {
+				p.cursor++
+			}
+		case "enter", " ":
+			if p.cursor < len(env.Menu) {
+				return p, Navigate(env.Menu[p.cursor].ID)
+			}
+		}
+	}
+	return p, nil
+}
+
+func (p *menuPage) View(env *Env) string {
+	return RenderMenu(env.Styles, env.Menu, p.cursor, p.sweep, env.ThemeLabel, p.KeyHelp(), env.VisitorCount, env.Online, env.BoxWidth)
 }
 
-func RenderMenu(styles view.ThemeStyles, menuCursor int, logoSweepIndex int, themeLabel string, visitorCount int, boxWidth int) string {
+func (p *menuPage) Title() string       { return "Menu" }
+func (p *menuPage) Description() string { return "" }
+func (p *menuPage) KeyHelp() string {
+	return "↑/↓: navigate • enter: select • /: search • esc/backspace: menu • q: quit"
+}
+
+func menuTickCmd(gen int) tea.Cmd {
+	return tea.Tick(menuTick, func(time.Time) tea.Msg {
+		return menuTickMsg{gen: gen}
+	})
+}
+
+func RenderMenu(styles view.ThemeStyles, entries []MenuEntry, menuCursor int, logoSweepIndex int, themeLabel string, keyHelp string, visitorCount int, online int, boxWidth int) string {
 	var b strings.Builder
 
 	logoWidth := 60
 	b.WriteString(view.RenderGradientLogo(logoWidth, logoSweepIndex, styles.LogoBase, styles.LogoSnake))
 
-	infoLine := ""
+	var info []string
+	if online > 0 {
+		info = append(info, fmt.Sprintf("%d online now", online))
+	}
 	if visitorCount > 0 {
-		infoLine = fmt.Sprintf("Visits: %d", visitorCount)
+		info = append(info, fmt.Sprintf("Visits: %d", visitorCount))
 	}
+	infoLine := strings.Join(info, " • ")
 
 	b.WriteString("\n")
 	if boxWidth <= 0 {
@@ -46,13 +112,13 @@ func RenderMenu(styles view.ThemeStyles, menuCursor int, logoSweepIndex int, the
 	b.WriteString(styles.Subtle.Copy().Width(boxWidth).Align(lipgloss.Left).Render(infoLine))
 	b.WriteString("\n\n")
 
-	for i, item := range menuItems {
+	for i, entry := range entries {
 		cursor := "  "
 		if menuCursor == i {
 			cursor = "→ "
 		}
 
-		leftText := cursor + item
+		leftText := cursor + entry.Title
 		leftCell := lipgloss.NewStyle().Width(menuLeftWidth).Render(leftText)
 		if menuCursor == i {
 			leftCell = styles.Selected.Render(leftCell)
@@ -60,11 +126,10 @@ func RenderMenu(styles view.ThemeStyles, menuCursor int, logoSweepIndex int, the
 			leftCell = styles.Menu.Render(leftCell)
 		}
 
-		desc := menuDescriptions[i]
 		rightCell := lipgloss.NewStyle().
 			Width(menuRightWidth).
 			Align(lipgloss.Right).
-			Render(desc)
+			Render(entry.Description)
 		if menuCursor == i {
 			rightCell = styles.Selected.Copy().Bold(false).Faint(true).Render(rightCell)
 		} else {
@@ -75,9 +140,7 @@ func RenderMenu(styles view.ThemeStyles, menuCursor int, logoSweepIndex int, the
 		b.WriteString("\n")
 	}
 
-	helpMain := "↑/↓: navigate • enter: select • esc/backspace: menu • q: quit"
-	helpTheme := "t: " + themeLabel[len("t: "):]
-	b.WriteString(styles.Help.Render("\n" + helpMain + "\n" + helpTheme))
+	b.WriteString(styles.Help.Render("\n" + keyHelp + "\n" + themeLabel))
 
 	return b.String()
 }
@@ -0,0 +1,235 @@
+package pages
+
+import (
+	"fmt"
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+
+	"github.com/andatoshiki/termfolio/chat"
+	"github.com/andatoshiki/termfolio/content"
+	"github.com/andatoshiki/termfolio/counter"
+	"github.com/andatoshiki/termfolio/outbox"
+	"github.com/andatoshiki/termfolio/view"
+)
+
+const (
+	SplashID     = "splash"
+	MenuID       = "menu"
+	AboutID      = "about"
+	ProjectsID   = "projects"
+	GalleryID    = "gallery"
+	ExperienceID = "experience"
+	EducationID  = "education"
+	ContactID    = "contact"
+	GuestbookID  = "guestbook"
+	LobbyID      = "lobby"
+	FeedID       = "feed"
+	StatsID      = "stats"
+	PrivacyID    = "privacy"
+)
+
+// Page is a screen of the app. Pages own their own state; everything shared
+// across the session lives in Env, which the router passes to every call.
+type Page interface {
+	// Init runs each time the page is entered.
+	Init(env *Env) tea.Cmd
+	Update(env *Env, msg tea.Msg) (Page, tea.Cmd)
+	View(env *Env) string
+	Title() string
+	Description() string
+	// KeyHelp is the page-specific part of the help line.
+	KeyHelp() string
+}
+
+// KeyCapturer is implemented by pages that want keys the router would
+// otherwise handle itself (q, t, esc, backspace), e.g. while editing text.
+type KeyCapturer interface {
+	CapturesKey(msg tea.KeyMsg) bool
+}
+
+// Framer is implemented by pages that draw outside the centered content box.
+type Framer interface {
+	Frame(env *Env, boxed string) string
+}
+
+// Addressed is implemented by messages that belong to a specific page, such
+// as async results, so the router delivers them even when it is not current.
+type Addressed interface {
+	PageID() string
+}
+
+// Optional is implemented by pages that only make sense with some content,
+// such as the gallery. Without a menu block, the menu skips them when
+// Available reports false.
+type Optional interface {
+	Available(env *Env) bool
+}
+
+// Leaver is implemented by pages that hold a shared resource while shown,
+// such as a seat in the lobby. The router calls Leave when switching away.
+type Leaver interface {
+	Leave(env *Env)
+}
+
+// NavigateMsg asks the router to switch to the page with the given ID.
+type NavigateMsg struct {
+	ID string
+}
+
+func Navigate(id string) tea.Cmd {
+	return func() tea.Msg {
+		return NavigateMsg{ID: id}
+	}
+}
+
+// Env is the session-wide state shared by all pages.
+type Env struct {
+	Styles     view.ThemeStyles
+	ThemeLabel string
+	Width      int
+	Height     int
+	BoxWidth   int
+	Content    *content.Content
+	Menu       []MenuEntry
+	// ColorProfile is the visitor's terminal color support, used for images.
+	ColorProfile termenv.Profile
+	// Logger reports the failures pages hide from the visitor, tagged with
+	// the session.
+	Logger *slog.Logger
+
+	Counter *counter.Store
+	// Visitor identifies the visitor to the counter; its IP also rate
+	// limits the guestbook and contact form.
+	Visitor counter.Visitor
+	// Visit records the pages this session opens; nil when it is not
+	// recorded.
+	Visit           *counter.Visit
+	TrackingEnabled bool
+	VisitorCount    int
+	// Online is the number of sessions browsing this portfolio, including
+	// this one; zero when unknown.
+	Online       int
+	StatsEnabled bool
+	// Outbox delivers contact form messages; nil hides the form.
+	Outbox *outbox.Outbox
+	// Lobby is this session's seat in the chat lobby; nil hides the page.
+	Lobby *chat.Client
+}
+
+// TrackingAvailable reports whether the visitor can toggle tracking.
+func (e *Env) TrackingAvailable() bool {
+	return e.Counter != nil && e.Visitor.ID() != ""
+}
+
+// SetTracking records the visitor's opt-in or opt-out choice.
+func (e *Env) SetTracking(enabled bool) {
+	if !e.TrackingAvailable() {
+		return
+	}
+	if enabled == e.TrackingEnabled {
+		return
+	}
+	count, err := e.Counter.SetOptOut(e.Visitor, !enabled)
+	if err != nil {
+		e.Logger.Error("Failed to update privacy setting", "err", err)
+		return
+	}
+	e.TrackingEnabled = enabled
+	e.VisitorCount = count
+	e.Visit.SetTracking(enabled)
+}
+
+// Quit ends the session, recording that the visitor chose to leave.
+func (e *Env) Quit() tea.Cmd {
+	e.Visit.End(counter.EndQuit)
+	return tea.Quit
+}
+
+// Help joins the theme label with a page's key help.
+func (e *Env) Help(keyHelp string) string {
+	if keyHelp == "" {
+		return e.ThemeLabel
+	}
+	return e.ThemeLabel + " • " + keyHelp
+}
+
+// boxContentWidth is the width available inside the padded content box.
+func boxContentWidth(boxWidth int) int {
+	if boxWidth <= 0 {
+		return 60
+	}
+	if boxWidth > 4 {
+		return boxWidth - 4
+	}
+	return boxWidth
+}
+
+// Registry holds the pages reachable from the menu, in menu order.
+type Registry struct {
+	ids       []string
+	factories map[string]func() Page
+}
+
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]func() Page)}
+}
+
+// Register adds a page factory under id. It panics on duplicate IDs.
+func (r *Registry) Register(id string, factory func() Page) {
+	if _, exists := r.factories[id]; exists {
+		panic(fmt.Sprintf("pages: duplicate page id %q", id))
+	}
+	r.ids = append(r.ids, id)
+	r.factories[id] = factory
+}
+
+func (r *Registry) IDs() []string {
+	return append([]string(nil), r.ids...)
+}
+
+// ValidateMenu reports menu items that do not name a registered page.
+func (r *Registry) ValidateMenu(items []content.MenuItem) error {
+	for i, item := range items {
+		if _, ok := r.factories[item.ID]; !ok {
+			return fmt.Errorf("menu[%d]: unknown section %q", i, item.ID)
+		}
+	}
+	return nil
+}
+
+func (r *Registry) New(id string) (Page, bool) {
+	factory, ok := r.factories[id]
+	if !ok {
+		return nil, false
+	}
+	return factory(), true
+}
+
+// DefaultRegistry returns the built-in menu sections.
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(AboutID, func() Page { return &aboutPage{} })
+	r.Register(ProjectsID, func() Page { return &projectsPage{} })
+	r.Register(GalleryID, func() Page { return &galleryPage{} })
+	r.Register(ExperienceID, func() Page { return &experiencePage{} })
+	r.Register(EducationID, func() Page { return &educationPage{} })
+	r.Register(ContactID, func() Page { return &contactPage{} })
+	r.Register(GuestbookID, func() Page { return &guestbookPage{} })
+	r.Register(LobbyID, func() Page { return &lobbyPage{} })
+	r.Register(FeedID, func() Page { return &feedPage{} })
+	r.Register(StatsID, func() Page { return &statsPage{} })
+	r.Register(PrivacyID, func() Page { return &privacyPage{} })
+	return r
+}
+
+func clampCursor(cursor int, total int) int {
+	if cursor >= total {
+		cursor = total - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	return cursor
+}
@@ -0,0 +1,35 @@
+package pages
+
+import (
+	"strings"
+
+	xansi "github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+
+	"github.com/andatoshiki/termfolio/content"
+	"github.com/andatoshiki/termfolio/view"
+)
+
+// renderPicture renders a picture's image as half-block art within width
+// cells and height lines, falling back to its ASCII art when it has no image
+// or the image can no longer be read.
+func renderPicture(styles view.ThemeStyles, pic content.Picture, width, height int, profile termenv.Profile) string {
+	if pic.Image != "" {
+		art, err := view.RenderImage(pic.Image, width, height, profile)
+		if err == nil {
+			return art
+		}
+		if strings.TrimSpace(pic.Art) == "" {
+			return styles.Subtle.Render("[image unavailable]")
+		}
+	}
+
+	lines := strings.Split(strings.TrimRight(pic.Art, "\n"), "\n")
+	for i, line := range lines {
+		if width > 0 {
+			line = xansi.Truncate(line, width, "")
+		}
+		lines[i] = styles.Content.Render(line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/andatoshiki/termfolio/counter"
@@ -17,12 +18,90 @@ const (
 	privacyCountryNameWidth = 18
 )
 
+type privacyPage struct {
+	cursor     int
+	statsTotal int
+	statsTop   []counter.CountryCount
+	statsError string
+}
+
+func (p *privacyPage) Init(env *Env) tea.Cmd {
+	p.cursor = 0
+	p.refreshStats(env)
+	return nil
+}
+
+func (p *privacyPage) Update(env *Env, msg tea.Msg) (Page, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+	switch key.String() {
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < 1 {
+			p.cursor++
+		}
+	case "enter", " ":
+		env.SetTracking(p.cursor == 0)
+		p.refreshStats(env)
+		return p, Navigate(MenuID)
+	}
+	return p, nil
+}
+
+func (p *privacyPage) View(env *Env) string {
+	return RenderPrivacy(
+		env.Styles,
+		p.cursor,
+		env.TrackingEnabled,
+		env.TrackingAvailable(),
+		env.Help(p.KeyHelp()),
+		env.StatsEnabled,
+		p.statsTotal,
+		p.statsTop,
+		p.statsError,
+	)
+}
+
+func (p *privacyPage) Title() string       { return "Privacy" }
+func (p *privacyPage) Description() string { return "Tracking control" }
+func (p *privacyPage) KeyHelp() string {
+	return "↑/↓: select • enter: confirm • esc/backspace: menu • q: quit"
+}
+
+func (p *privacyPage) refreshStats(env *Env) {
+	p.statsTotal = 0
+	p.statsTop = nil
+	p.statsError = ""
+
+	if !env.StatsEnabled {
+		return
+	}
+	if env.Counter == nil {
+		p.statsError = "counter is disabled"
+		return
+	}
+
+	stats, err := env.Counter.CountryStats()
+	if err != nil {
+		p.statsError = err.Error()
+		return
+	}
+
+	p.statsTotal = stats.TotalVisitors
+	p.statsTop = append([]counter.CountryCount(nil), stats.TopCountries...)
+}
+
 func RenderPrivacy(
 	styles view.ThemeStyles,
 	cursor int,
 	trackingEnabled bool,
 	trackingAvailable bool,
-	themeLabel string,
+	help string,
 	statsEnabled bool,
 	statsTotal int,
 	statsTopCountries []counter.CountryCount,
@@ -91,7 +170,6 @@ func RenderPrivacy(
 		}
 	}
 
-	help := "↑/↓: select • enter: confirm • esc/backspace: menu • q: quit • " + themeLabel
 	b.WriteString("\n")
 	b.WriteString(styles.Help.Render(help))
 
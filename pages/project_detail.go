@@ -0,0 +1,174 @@
+package pages
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/andatoshiki/termfolio/content"
+	"github.com/andatoshiki/termfolio/view"
+)
+
+const projectLinkLabelWidth = 10
+
+// projectDetailHeight is how many body lines fit on screen below the title
+// and above the help line. Zero means unlimited.
+func projectDetailHeight(termHeight int) int {
+	if termHeight <= 0 {
+		return 0
+	}
+	height := termHeight - 10
+	if height < 5 {
+		height = 5
+	}
+	return height
+}
+
+func clampScroll(scroll, total, height int) int {
+	if height <= 0 {
+		return 0
+	}
+	maxScroll := total - height
+	if scroll > maxScroll {
+		scroll = maxScroll
+	}
+	if scroll < 0 {
+		scroll = 0
+	}
+	return scroll
+}
+
+func RenderProjectDetail(styles view.ThemeStyles, project content.Project, scroll int, height int, help string, boxWidth int, profile termenv.Profile) string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("━━━ " + project.Name + " ━━━"))
+	b.WriteString("\n")
+
+	lines := projectDetailLines(styles, project, boxContentWidth(boxWidth), height, profile)
+	start, end := 0, len(lines)
+	if height > 0 {
+		start = clampScroll(scroll, len(lines), height)
+		if start+height < end {
+			end = start + height
+		}
+	}
+
+	moreStyle := styles.Accent.Copy().Faint(true)
+	if start > 0 {
+		b.WriteString(moreStyle.Render("more above!"))
+	}
+	b.WriteString("\n")
+	b.WriteString(strings.Join(lines[start:end], "\n"))
+	b.WriteString("\n")
+
+	if end < len(lines) {
+		b.WriteString(moreStyle.Render("more below!"))
+		b.WriteString(styles.Help.Render(" • " + help))
+	} else {
+		b.WriteString(styles.Help.Render(help))
+	}
+
+	return b.String()
+}
+
+// projectDetailLines lays out everything below the title as lines, so the
+// view can scroll through them. Screenshot images are sized to fit within
+// height lines.
+func projectDetailLines(styles view.ThemeStyles, project content.Project, width int, height int, profile termenv.Profile) []string {
+	var sections []string
+
+	var meta []string
+	if project.Status != "" {
+		meta = append(meta, project.Status)
+	}
+	if project.Year != "" {
+		meta = append(meta, project.Year)
+	}
+	var header []string
+	if len(meta) > 0 {
+		header = append(header, styles.Period.Render(strings.Join(meta, " • ")))
+	}
+	if len(project.Tags) > 0 {
+		header = append(header, renderTagChips(styles, project.Tags, width))
+	}
+	if project.Tech != "" {
+		header = append(header, styles.Tech.Render(project.Tech))
+	}
+	if len(header) > 0 {
+		sections = append(sections, strings.Join(header, "\n"))
+	}
+
+	body := project.Details
+	if strings.TrimSpace(body) == "" {
+		body = project.Desc
+	}
+	if strings.TrimSpace(body) != "" {
+		sections = append(sections, view.RenderMarkdown(styles, body, width))
+	}
+
+	links := projectLinks(project)
+	if len(links) > 0 {
+		linkLines := []string{styles.Accent.Render("Links")}
+		for _, link := range links {
+			text := link.Text
+			if text == "" {
+				text = link.URL
+			}
+			label := styles.Content.Render(fmt.Sprintf("%-*s", projectLinkLabelWidth, link.Label))
+			linkLines = append(linkLines, label+" "+styles.Content.Render(view.ClickableLink(text, projectURL(link.URL))))
+		}
+		sections = append(sections, strings.Join(linkLines, "\n"))
+	}
+
+	if len(project.Screenshots) > 0 {
+		shotLines := []string{styles.Accent.Render("Screenshots")}
+		for i, shot := range project.Screenshots {
+			if i > 0 {
+				shotLines = append(shotLines, "")
+			}
+			shotLines = append(shotLines, renderPicture(styles, shot, width, height, profile))
+			if shot.Caption != "" {
+				shotLines = append(shotLines, styles.Period.Render(shot.Caption))
+			}
+		}
+		sections = append(sections, strings.Join(shotLines, "\n"))
+	}
+
+	return strings.Split(strings.Join(sections, "\n\n"), "\n")
+}
+
+// projectLinks lists the project's primary link first, followed by its
+// labelled links.
+func projectLinks(project content.Project) []content.Link {
+	var links []content.Link
+	if project.Link != "" {
+		links = append(links, content.Link{Label: "Link", URL: project.Link})
+	}
+	return append(links, project.Links...)
+}
+
+// renderTagChips renders tags as reversed chips, wrapped to width.
+func renderTagChips(styles view.ThemeStyles, tags []string, width int) string {
+	chip := styles.Tech.Copy().Reverse(true).Padding(0, 1)
+
+	var lines []string
+	line := ""
+	for _, tag := range tags {
+		rendered := chip.Render(tag)
+		switch {
+		case line == "":
+			line = rendered
+		case width > 0 && lipgloss.Width(line)+1+lipgloss.Width(rendered) > width:
+			lines = append(lines, line)
+			line = rendered
+		default:
+			line += " " + rendered
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -3,66 +3,113 @@ package pages
 import (
 	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/andatoshiki/termfolio/content"
 	"github.com/andatoshiki/termfolio/view"
 )
 
-type Project struct {
-	Name string
-	Desc string
-	Tech string
-	Link string
+type projectsPage struct {
+	cursor int
+	detail bool
+	scroll int
+}
+
+func (p *projectsPage) Init(env *Env) tea.Cmd {
+	p.detail = false
+	return nil
+}
+
+func (p *projectsPage) Update(env *Env, msg tea.Msg) (Page, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+	p.cursor = clampCursor(p.cursor, len(env.Content.Projects))
+	if p.detail {
+		p.updateDetail(env, key)
+		return p, nil
+	}
+	switch key.String() {
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(env.Content.Projects)-1 {
+			p.cursor++
+		}
+	case "enter", " ":
+		if len(env.Content.Projects) > 0 {
+			p.detail = true
+			p.scroll = 0
+		}
+	}
+	return p, nil
+}
+
+func (p *projectsPage) updateDetail(env *Env, key tea.KeyMsg) {
+	if len(env.Content.Projects) == 0 {
+		p.detail = false
+		return
+	}
+	height := projectDetailHeight(env.Height)
+	switch key.String() {
+	case "esc", "backspace":
+		p.detail = false
+		return
+	case "up", "k":
+		p.scroll--
+	case "down", "j":
+		p.scroll++
+	case "pgup", "b":
+		p.scroll -= height
+	case "pgdown", "f", " ":
+		p.scroll += height
+	case "home", "g":
+		p.scroll = 0
+	case "end", "G":
+		p.scroll = len(p.detailLines(env))
+	}
+	p.scroll = clampScroll(p.scroll, len(p.detailLines(env)), height)
+}
+
+func (p *projectsPage) detailLines(env *Env) []string {
+	return projectDetailLines(env.Styles, env.Content.Projects[p.cursor], boxContentWidth(env.BoxWidth), projectDetailHeight(env.Height), env.ColorProfile)
 }
 
-var projects = []Project{
-	{
-		Name: "Toshiki's Homepage",
-		Desc: "All-in-one home landing page/blog/portfolio with a Nuxt 3 rebuild.",
-		Tech: "Nuxt 3, Vue, Vercel, Cloudflare Workers, Netlify",
-		Link: "https://github.com/andatoshiki/toshiki-home-nuxt3",
-	},
-	{
-		Name: "Toshiki's Notebook",
-		Desc: "VitePress-powered web notebook and knowledge base.",
-		Tech: "VitePress, Vercel",
-		Link: "https://note.toshiki.dev",
-	},
-	{
-		Name: "Toshiki's HTTP",
-		Desc: "Playful HTTP status code illustrations with the Ukuku character.",
-		Tech: "Web, API",
-		Link: "https://http.toshiki.dev",
-	},
-	{
-		Name: "Toshiki's Live2D Viewer",
-		Desc: "Simple web Live2D viewer built on Pixi.",
-		Tech: "PixiJS, Live2D",
-		Link: "https://live2d.toshiki.dev",
-	},
-	{
-		Name: "Toshiki's Temple Block",
-		Desc: "Virtual temple block to collect merits with a tap.",
-		Tech: "Web",
-		Link: "https://merit.toshiki.dev",
-	},
-	{
-		Name: "Toshiki's Gallery",
-		Desc: "Self-hosted photo gallery built with Hugo.",
-		Tech: "Hugo",
-		Link: "https://github.com/andatoshiki/toshiki-gallery",
-	},
-	{
-		Name: "Toshiki's Mahjong Calculator",
-		Desc: "Mahjong score calculator for quick scorekeeping.",
-		Tech: "Web",
-		Link: "https://github.com/andatoshiki/toshiki-mahjong-calc",
-	},
+// CapturesKey keeps esc and backspace on the page while a project is open,
+// so they return to the list instead of the menu.
+func (p *projectsPage) CapturesKey(msg tea.KeyMsg) bool {
+	if !p.detail {
+		return false
+	}
+	switch msg.String() {
+	case "esc", "backspace":
+		return true
+	}
+	return false
 }
 
-func Projects() []Project {
-	return projects
+func (p *projectsPage) View(env *Env) string {
+	if p.detail && len(env.Content.Projects) > 0 {
+		project := env.Content.Projects[clampCursor(p.cursor, len(env.Content.Projects))]
+		return RenderProjectDetail(env.Styles, project, p.scroll, projectDetailHeight(env.Height), env.Help(p.KeyHelp()), env.BoxWidth, env.ColorProfile)
+	}
+	return RenderProjects(env.Styles, env.Content.Projects, p.cursor, env.Help(p.KeyHelp()), env.BoxWidth)
 }
 
-func RenderProjects(styles view.ThemeStyles, projectCursor int, themeLabel string) string {
+func (p *projectsPage) Title() string       { return "Projects" }
+func (p *projectsPage) Description() string { return "Selected work" }
+
+func (p *projectsPage) KeyHelp() string {
+	if p.detail {
+		return "↑/↓: scroll • esc: back to list"
+	}
+	return "↑/↓: browse • enter: details • esc: back to menu"
+}
+
+func RenderProjects(styles view.ThemeStyles, projects []content.Project, projectCursor int, help string, boxWidth int) string {
 	var b strings.Builder
 
 	b.WriteString(styles.Title.Render("━━━ Projects ━━━"))
@@ -88,23 +135,25 @@ func RenderProjects(styles view.ThemeStyles, projectCursor int, themeLabel strin
 
 		// Expands project section
 		if projectCursor == i {
-			b.WriteString(styles.Subtle.Render("    " + p.Desc))
-			b.WriteString("\n")
-			b.WriteString("    ")
-			b.WriteString(styles.Tech.Render(p.Tech))
-			b.WriteString("\n")
-			b.WriteString("    ")
-			projectURL := p.Link
-			if !strings.HasPrefix(projectURL, "http://") && !strings.HasPrefix(projectURL, "https://") {
-				projectURL = "https://" + projectURL
+			if p.Desc != "" {
+				b.WriteString(renderProjectDesc(styles, p.Desc, boxContentWidth(boxWidth)-4))
+				b.WriteString("\n")
+			}
+			if p.Tech != "" {
+				b.WriteString("    ")
+				b.WriteString(styles.Tech.Render(p.Tech))
+				b.WriteString("\n")
+			}
+			if p.Link != "" {
+				b.WriteString("    ")
+				link := projectURL(p.Link)
+				b.WriteString(styles.Accent.Render(view.ClickableLink(link, link)))
+				b.WriteString("\n")
 			}
-			b.WriteString(styles.Accent.Render(view.ClickableLink(projectURL, projectURL)))
-			b.WriteString("\n")
 		}
 		b.WriteString("\n")
 	}
 
-	help := themeLabel + " • ↑/↓: browse • esc: back to menu"
 	if end < len(projects) {
 		moreStyle := styles.Accent.Copy().Faint(true)
 		b.WriteString(moreStyle.Render("more below!"))
@@ -116,6 +165,27 @@ func RenderProjects(styles view.ThemeStyles, projectCursor int, themeLabel strin
 	return b.String()
 }
 
+func projectURL(link string) string {
+	if !strings.HasPrefix(link, "http://") && !strings.HasPrefix(link, "https://") {
+		return "https://" + link
+	}
+	return link
+}
+
+// renderProjectDesc renders the Markdown description muted and indented under
+// the project name.
+func renderProjectDesc(styles view.ThemeStyles, desc string, width int) string {
+	muted := styles
+	muted.Content = styles.Subtle
+	lines := strings.Split(view.RenderMarkdown(muted, desc, width), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = "    " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 func projectWindow(cursor, total, pageSize int) (start, end int) {
 	if total <= 0 {
 		return 0, 0
@@ -140,3 +210,19 @@ func projectWindow(cursor, total, pageSize int) (start, end int) {
 	}
 	return start, end
 }
+
+func (p *projectsPage) SearchItems(env *Env) []SearchItem {
+	items := make([]SearchItem, 0, len(env.Content.Projects))
+	for i, project := range env.Content.Projects {
+		detail := project.Tech
+		if len(project.Tags) > 0 {
+			detail += " " + strings.Join(project.Tags, " ")
+		}
+		items = append(items, SearchItem{Title: project.Name, Detail: detail + " " + view.MarkdownPlain(project.Desc, 0), Index: i})
+	}
+	return items
+}
+
+func (p *projectsPage) Focus(env *Env, index int) {
+	p.cursor = clampCursor(index, len(env.Content.Projects))
+}
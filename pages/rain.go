@@ -0,0 +1,232 @@
+package pages
+
+import (
+	"strings"
+
+	xansi "github.com/charmbracelet/x/ansi"
+
+	"github.com/andatoshiki/termfolio/view"
+)
+
+const splashRainFrameDivisor = 1
+
+const splashRainLeadPadding = 12
+
+func renderSplashWithRain(styles view.ThemeStyles, frame int, width int, height int, overlay string, left int, top int) string {
+	if width <= 0 || height <= 0 {
+		return overlay
+	}
+
+	rainFrame := frame
+	if rainFrame < 0 {
+		rainFrame = 0
+	}
+	rainFrame /= splashRainFrameDivisor
+
+	tokens := buildSplashRainTokens(styles)
+	seeds, speeds, trails := buildSplashRainColumns(width)
+	overlayLines := strings.Split(overlay, "\n")
+	contentLeft := max(0, left)
+	if contentLeft > width {
+		contentLeft = width
+	}
+
+	var out strings.Builder
+	for y := 0; y < height; y++ {
+		if y > 0 {
+			out.WriteByte('\n')
+		}
+
+		overlayRow := y - top
+		if overlayRow < 0 || overlayRow >= len(overlayLines) {
+			writeSplashRainRange(&out, tokens, rainFrame, width, height, y, 0, width, false, seeds, speeds, trails)
+			continue
+		}
+
+		overlayLine := overlayLines[overlayRow]
+		plainOverlayLine := xansi.Strip(overlayLine)
+		spanStart, spanEnd, hasVisible := nonSpaceSpan(plainOverlayLine)
+		if !hasVisible {
+			writeSplashRainRange(&out, tokens, rainFrame, width, height, y, 0, width, true, seeds, speeds, trails)
+			continue
+		}
+
+		lineWidth := xansi.StringWidth(plainOverlayLine)
+		if spanStart < 0 {
+			spanStart = 0
+		}
+		if spanEnd > lineWidth {
+			spanEnd = lineWidth
+		}
+
+		overlayStartCol := contentLeft + spanStart
+		overlayEndCol := contentLeft + spanEnd
+		if overlayStartCol < 0 {
+			overlayStartCol = 0
+		}
+		if overlayEndCol > width {
+			overlayEndCol = width
+		}
+
+		if overlayStartCol > 0 {
+			writeSplashRainRange(&out, tokens, rainFrame, width, height, y, 0, overlayStartCol, true, seeds, speeds, trails)
+		}
+
+		if overlayEndCol > overlayStartCol {
+			out.WriteString(xansi.Cut(overlayLine, spanStart, spanEnd))
+		}
+
+		if overlayEndCol < width {
+			writeSplashRainRange(&out, tokens, rainFrame, width, height, y, overlayEndCol, width, true, seeds, speeds, trails)
+		}
+	}
+
+	return out.String()
+}
+
+type splashRainTokens struct {
+	headZero  string
+	headOne   string
+	trailZero string
+	trailOne  string
+	dimZero   string
+	dimOne    string
+}
+
+func buildSplashRainTokens(styles view.ThemeStyles) splashRainTokens {
+	headStyle := styles.Accent.Copy().Bold(false)
+	trailStyle := styles.Accent.Copy().Bold(false).Faint(true)
+	dimStyle := styles.Subtle.Copy().Bold(false).Faint(true)
+
+	return splashRainTokens{
+		headZero:  headStyle.Render("0"),
+		headOne:   headStyle.Render("1"),
+		trailZero: trailStyle.Render("0"),
+		trailOne:  trailStyle.Render("1"),
+		dimZero:   dimStyle.Render("0"),
+		dimOne:    dimStyle.Render("1"),
+	}
+}
+
+func buildSplashRainColumns(width int) ([]int, []int, []int) {
+	seeds := make([]int, width)
+	speeds := make([]int, width)
+	trails := make([]int, width)
+
+	for x := 0; x < width; x++ {
+		seed := splashRainHash((x + 1) * 7919)
+		seeds[x] = seed
+		speeds[x] = 1 + seed%3
+		trails[x] = 4 + seed%6
+	}
+
+	return seeds, speeds, trails
+}
+
+func writeSplashRainRange(
+	out *strings.Builder,
+	tokens splashRainTokens,
+	rainFrame int,
+	width int,
+	height int,
+	y int,
+	start int,
+	end int,
+	soft bool,
+	seeds []int,
+	speeds []int,
+	trails []int,
+) {
+	if start < 0 {
+		start = 0
+	}
+	if end > width {
+		end = width
+	}
+	if start >= end {
+		return
+	}
+
+	for x := start; x < end; x++ {
+		seed := seeds[x]
+		speed := speeds[x]
+		trail := trails[x]
+		headRange := height + trail + splashRainLeadPadding
+		head := (rainFrame/speed + seed) % headRange
+
+		switch {
+		case head < height && y <= head && y > head-trail:
+			one := splashBinaryOne(rainFrame, x, y, seed)
+			if soft {
+				if one {
+					out.WriteString(tokens.dimOne)
+				} else {
+					out.WriteString(tokens.dimZero)
+				}
+				continue
+			}
+
+			dist := head - y
+			switch {
+			case dist == 0:
+				if one {
+					out.WriteString(tokens.headOne)
+				} else {
+					out.WriteString(tokens.headZero)
+				}
+			case dist <= trail/2:
+				if one {
+					out.WriteString(tokens.trailOne)
+				} else {
+					out.WriteString(tokens.trailZero)
+				}
+			default:
+				if one {
+					out.WriteString(tokens.dimOne)
+				} else {
+					out.WriteString(tokens.dimZero)
+				}
+			}
+		case splashRainHash(rainFrame*29+x*97+y*53+seed)%41 == 0:
+			if splashBinaryOne(rainFrame/2, x, y, seed+17) {
+				out.WriteString(tokens.dimOne)
+			} else {
+				out.WriteString(tokens.dimZero)
+			}
+		default:
+			out.WriteByte(' ')
+		}
+	}
+}
+
+func nonSpaceSpan(s string) (start int, end int, ok bool) {
+	runes := []rune(s)
+	start = 0
+	for start < len(runes) && runes[start] == ' ' {
+		start++
+	}
+	if start >= len(runes) {
+		return 0, 0, false
+	}
+
+	last := len(runes) - 1
+	for last >= 0 && runes[last] == ' ' {
+		last--
+	}
+
+	return start, last + 1, true
+}
+
+func splashRainHash(n int) int {
+	n ^= n << 13
+	n ^= n >> 17
+	n ^= n << 5
+	if n < 0 {
+		n = -n
+	}
+	return n
+}
+
+func splashBinaryOne(frame int, x int, y int, seed int) bool {
+	return splashRainHash(frame*131+x*17+y*23+seed)%2 == 0
+}
@@ -0,0 +1,221 @@
+package pages
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	xansi "github.com/charmbracelet/x/ansi"
+
+	"github.com/andatoshiki/termfolio/view"
+)
+
+// Searchable is implemented by pages whose entries the / search overlay can
+// find. Focus moves the page's cursor to an item after the router has
+// entered the page.
+type Searchable interface {
+	SearchItems(env *Env) []SearchItem
+	Focus(env *Env, index int)
+}
+
+// SearchItem is one entry of a page. Title is shown and matched first;
+// Detail is matched with a lower score. Index is passed back to Focus.
+type SearchItem struct {
+	Title  string
+	Detail string
+	Index  int
+}
+
+// SearchEntry is a SearchItem together with the page it belongs to.
+type SearchEntry struct {
+	PageID  string
+	Section string
+	SearchItem
+}
+
+// SearchResult is a matched entry. Matched holds the rune positions of the
+// query in Title, for highlighting.
+type SearchResult struct {
+	SearchEntry
+	Score   int
+	Matched []int
+}
+
+// RankSearch fuzzy-matches query against entries and returns the matches,
+// best first. Each word of the query must match the title or detail, in any
+// order. An empty query returns every entry in order.
+func RankSearch(query string, entries []SearchEntry) []SearchResult {
+	terms := strings.Fields(query)
+	results := make([]SearchResult, 0, len(entries))
+	for _, entry := range entries {
+		result := SearchResult{SearchEntry: entry}
+		matched := true
+		for _, term := range terms {
+			score, positions, ok := fuzzyMatch(term, entry.Title)
+			if detailScore, _, detailOK := fuzzyMatch(term, entry.Detail); detailOK && (!ok || detailScore/2 > score) {
+				score, positions, ok = detailScore/2, nil, true
+			}
+			if !ok {
+				matched = false
+				break
+			}
+			result.Score += score
+			result.Matched = append(result.Matched, positions...)
+		}
+		if matched {
+			results = append(results, result)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+// fuzzyMatch reports whether the runes of query appear in order in text,
+// ignoring case, and scores the best such alignment. Consecutive runs,
+// matches at word starts and early matches score higher.
+func fuzzyMatch(query, text string) (int, []int, bool) {
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(text))
+	if len(q) == 0 || len(q) > len(t) {
+		return 0, nil, false
+	}
+
+	bestScore := 0
+	var best []int
+	for start := range t {
+		if t[start] != q[0] {
+			continue
+		}
+		score, positions, ok := alignFrom(q, t, start)
+		if ok && (best == nil || score > bestScore) {
+			bestScore, best = score, positions
+		}
+	}
+	if best == nil {
+		return 0, nil, false
+	}
+	if strings.Contains(string(t), string(q)) {
+		bestScore += 10 * len(q)
+	}
+	return bestScore, best, true
+}
+
+// alignFrom greedily matches q against t, starting at t[start].
+func alignFrom(q, t []rune, start int) (int, []int, bool) {
+	positions := make([]int, 0, len(q))
+	score := 0
+	prev := -1
+	i := start
+	for _, r := range q {
+		for i < len(t) && t[i] != r {
+			i++
+		}
+		if i == len(t) {
+			return 0, nil, false
+		}
+		score += 10
+		if prev >= 0 && i == prev+1 {
+			score += 15
+		} else if prev >= 0 {
+			score -= min(i-prev-1, 10)
+		}
+		if i == 0 || !unicode.IsLetter(t[i-1]) && !unicode.IsDigit(t[i-1]) {
+			score += 20
+		}
+		positions = append(positions, i)
+		prev = i
+		i++
+	}
+	score -= min(start, 20)
+	return score, positions, true
+}
+
+// searchPageSize is how many results fit on screen.
+func searchPageSize(termHeight int) int {
+	if termHeight <= 0 {
+		return 8
+	}
+	return max(3, termHeight-12)
+}
+
+func RenderSearch(styles view.ThemeStyles, query string, results []SearchResult, cursor int, help string, boxWidth int, termHeight int) string {
+	var b strings.Builder
+	width := boxContentWidth(boxWidth)
+
+	b.WriteString(styles.Title.Render("━━━ Search ━━━"))
+	b.WriteString("\n\n")
+	b.WriteString(styles.Accent.Render("/ ") + styles.Content.Render(query) + styles.Accent.Render("▏"))
+	b.WriteString("\n\n")
+
+	if len(results) == 0 {
+		b.WriteString(styles.Subtle.Render("No matches."))
+		b.WriteString("\n\n")
+		b.WriteString(styles.Help.Render(help))
+		return b.String()
+	}
+
+	pageSize := searchPageSize(termHeight)
+	start, end := projectWindow(cursor, len(results), pageSize)
+	for i := start; i < end; i++ {
+		result := results[i]
+		prefix := "  "
+		if i == cursor {
+			prefix = "→ "
+		}
+		section := styles.Period.Render(" · " + result.Section)
+		titleWidth := max(1, width-lipgloss.Width(prefix)-lipgloss.Width(section))
+		b.WriteString(prefix)
+		b.WriteString(highlightMatches(styles, result.Title, result.Matched, titleWidth, i == cursor))
+		b.WriteString(section)
+		b.WriteString("\n")
+		if i == cursor && result.Detail != "" {
+			b.WriteString("    ")
+			b.WriteString(styles.Subtle.Render(xansi.Truncate(strings.Join(strings.Fields(result.Detail), " "), max(1, width-4), "…")))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	if end < len(results) {
+		moreStyle := styles.Accent.Copy().Faint(true)
+		b.WriteString(moreStyle.Render("more below!"))
+		b.WriteString(styles.Help.Render(" • " + help))
+	} else {
+		b.WriteString(styles.Help.Render(help))
+	}
+	return b.String()
+}
+
+// highlightMatches renders title truncated to width, with the matched runes
+// in the accent color.
+func highlightMatches(styles view.ThemeStyles, title string, matched []int, width int, selected bool) string {
+	base := styles.Content
+	if selected {
+		base = styles.Role
+	}
+	mark := styles.Accent.Copy().Bold(true).Underline(true)
+
+	isMatch := make(map[int]bool, len(matched))
+	for _, i := range matched {
+		isMatch[i] = true
+	}
+
+	runes := []rune(xansi.Truncate(title, width, "…"))
+	var b strings.Builder
+	runStart := 0
+	for i := 1; i <= len(runes); i++ {
+		if i < len(runes) && isMatch[i] == isMatch[runStart] {
+			continue
+		}
+		style := base
+		if isMatch[runStart] {
+			style = mark
+		}
+		b.WriteString(style.Render(string(runes[runStart:i])))
+		runStart = i
+	}
+	return b.String()
+}
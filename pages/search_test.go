@@ -0,0 +1,45 @@
+package pages
+
+import "testing"
+
+func TestRankSearch(t *testing.T) {
+	entries := []SearchEntry{
+		{PageID: ProjectsID, Section: "Projects", SearchItem: SearchItem{Title: "Toshiki's Gallery", Detail: "Hugo"}},
+		{PageID: ProjectsID, Section: "Projects", SearchItem: SearchItem{Title: "Go Terminal Toolkit", Detail: "Bubble Tea"}},
+		{PageID: ExperienceID, Section: "Experience", SearchItem: SearchItem{Title: "Intern @ Blue Origin", Detail: "New Glenn rocket software"}},
+	}
+
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{query: "", want: []string{"Toshiki's Gallery", "Go Terminal Toolkit", "Intern @ Blue Origin"}},
+		{query: "gtt", want: []string{"Go Terminal Toolkit"}},
+		{query: "gal", want: []string{"Toshiki's Gallery"}},
+		{query: "rocket", want: []string{"Intern @ Blue Origin"}},
+		{query: "BLUE", want: []string{"Intern @ Blue Origin"}},
+		{query: "zzz", want: nil},
+		{query: "origin intern", want: []string{"Intern @ Blue Origin"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			results := RankSearch(tc.query, entries)
+			if len(results) < len(tc.want) || (tc.want == nil && len(results) != 0) {
+				t.Fatalf("RankSearch(%q) returned %d results, want %v", tc.query, len(results), tc.want)
+			}
+			for i, want := range tc.want {
+				if results[i].Title != want {
+					t.Fatalf("RankSearch(%q)[%d] = %q, want %q", tc.query, i, results[i].Title, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFuzzyMatchPrefersWordStarts(t *testing.T) {
+	_, matched, ok := fuzzyMatch("gt", "big Go Terminal")
+	if !ok || len(matched) != 2 || matched[0] != 4 || matched[1] != 7 {
+		t.Fatalf("fuzzyMatch positions = %v, %v, want [4 7]", matched, ok)
+	}
+}
@@ -2,41 +2,114 @@ package pages
 
 import (
 	"strings"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/andatoshiki/termfolio/content"
 	"github.com/andatoshiki/termfolio/view"
 )
 
-const splashIntroPrefix = "Hi! Welcome to "
-const splashIntroName = "Toshiki's"
-const splashIntroSuffix = " termfolio, say hi to me!"
-const splashIntroLink = "https://toshiki.dev"
-const splashIntroText = splashIntroPrefix + splashIntroName + splashIntroSuffix
-const splashOpenSourcePrefix = "open sourced on "
-const splashOpenSourceLabel = "github"
-const splashOpenSourceLink = "https://github.com/andatoshiki/termfolio"
 const splashCommandBar = "enter: continue"
 const splashTickMillis = 40
 const splashBlinkIntervalMillis = 500
 
-var splashRunes = []rune(splashIntroText)
+const splashTick = splashTickMillis * time.Millisecond
+
+const splashLogoRevealStep = 3
+
+const splashTextRevealTickDivisor = 1
+
 var splashLogoTypewriterRunes = view.LogoTypewriterRuneCount()
 
+type splashTickMsg struct {
+	gen int
+}
+
+type splashPage struct {
+	reveal    int
+	blinkStep int
+	gen       int
+}
+
+// NewSplash returns the animated welcome screen shown on connect.
+func NewSplash() Page {
+	return &splashPage{}
+}
+
+func (p *splashPage) Init(env *Env) tea.Cmd {
+	p.gen++
+	return splashTickCmd(p.gen)
+}
+
+func (p *splashPage) Update(env *Env, msg tea.Msg) (Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case splashTickMsg:
+		if msg.gen != p.gen {
+			return p, nil
+		}
+		p.blinkStep++
+		if p.blinkStep >= 1_000_000 {
+			p.blinkStep = 0
+		}
+		logoTotal := SplashLogoRuneCount()
+		total := SplashRuneCount(env.Content.Splash)
+		if p.reveal < logoTotal {
+			p.reveal += splashLogoRevealStep
+			if p.reveal > logoTotal {
+				p.reveal = logoTotal
+			}
+		} else if p.reveal < total {
+			if p.blinkStep%splashTextRevealTickDivisor == 0 {
+				p.reveal++
+			}
+		}
+		return p, splashTickCmd(p.gen)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter", " ":
+			return p, Navigate(MenuID)
+		}
+	}
+	return p, nil
+}
+
+func (p *splashPage) View(env *Env) string {
+	return RenderSplash(env.Styles, env.Content.Splash, p.reveal, p.blinkStep, env.BoxWidth)
+}
+
+func (p *splashPage) Frame(env *Env, boxed string) string {
+	left := max(0, (env.Width-lipgloss.Width(boxed))/2)
+	top := max(0, (env.Height-lipgloss.Height(boxed))/2)
+	return renderSplashWithRain(env.Styles, p.blinkStep, env.Width, env.Height, boxed, left, top)
+}
+
+func (p *splashPage) Title() string       { return "Welcome" }
+func (p *splashPage) Description() string { return "" }
+func (p *splashPage) KeyHelp() string     { return splashCommandBar }
+
+func splashTickCmd(gen int) tea.Cmd {
+	return tea.Tick(splashTick, func(time.Time) tea.Msg {
+		return splashTickMsg{gen: gen}
+	})
+}
+
 func SplashLogoRuneCount() int {
 	return splashLogoTypewriterRunes
 }
 
-func SplashTextRuneCount() int {
-	return len(splashRunes)
+func SplashTextRuneCount(splash content.Splash) int {
+	return len([]rune(splash.IntroText()))
 }
 
-func SplashRuneCount() int {
-	return SplashLogoRuneCount() + SplashTextRuneCount()
+func SplashRuneCount(splash content.Splash) int {
+	return SplashLogoRuneCount() + SplashTextRuneCount(splash)
 }
 
-func RenderSplash(styles view.ThemeStyles, revealCount int, blinkStep int, boxWidth int) string {
-	total := SplashRuneCount()
+func RenderSplash(styles view.ThemeStyles, splash content.Splash, revealCount int, blinkStep int, boxWidth int) string {
+	total := SplashRuneCount(splash)
 	if revealCount < 0 {
 		revealCount = 0
 	}
@@ -44,7 +117,7 @@ func RenderSplash(styles view.ThemeStyles, revealCount int, blinkStep int, boxWi
 		revealCount = total
 	}
 
-	contentWidth := splashContentWidth(boxWidth)
+	contentWidth := boxContentWidth(boxWidth)
 	logoCount := SplashLogoRuneCount()
 	logoReveal := revealCount
 	if logoReveal > logoCount {
@@ -55,8 +128,8 @@ func RenderSplash(styles view.ThemeStyles, revealCount int, blinkStep int, boxWi
 		textReveal = 0
 	}
 
-	textTotal := SplashTextRuneCount()
-	text := renderSplashText(styles, textReveal, textTotal)
+	textTotal := SplashTextRuneCount(splash)
+	text := renderSplashText(styles, splash, textReveal, textTotal)
 	cursor := ""
 	if textReveal >= textTotal {
 		cursor = " " + renderSplashCursor(styles, blinkStep)
@@ -70,7 +143,7 @@ func RenderSplash(styles view.ThemeStyles, revealCount int, blinkStep int, boxWi
 	line := text + cursor
 	b.WriteString(lipgloss.NewStyle().Width(contentWidth).Align(lipgloss.Center).Render(line))
 	b.WriteString("\n")
-	openSourceLine := splashOpenSourcePrefix + view.ClickableLink(splashOpenSourceLabel, splashOpenSourceLink)
+	openSourceLine := splash.OpenSourcePrefix + view.ClickableLink(splash.OpenSourceLabel, splash.OpenSourceLink)
 	b.WriteString(styles.Accent.Copy().Bold(false).Faint(true).Width(contentWidth).Align(lipgloss.Center).Render(openSourceLine))
 	b.WriteString("\n")
 	b.WriteString(styles.Help.Copy().Width(contentWidth).Align(lipgloss.Center).Render(splashCommandBar))
@@ -78,12 +151,15 @@ func RenderSplash(styles view.ThemeStyles, revealCount int, blinkStep int, boxWi
 	return b.String()
 }
 
-func renderSplashText(styles view.ThemeStyles, revealCount, total int) string {
+func renderSplashText(styles view.ThemeStyles, splash content.Splash, revealCount, total int) string {
 	if revealCount < total {
-		return styles.Content.Render(string(splashRunes[:revealCount]))
+		return styles.Content.Render(string([]rune(splash.IntroText())[:revealCount]))
+	}
+	name := styles.Accent.Copy().Bold(false).Underline(true).Render(splash.IntroName)
+	if splash.IntroLink != "" {
+		name = view.ClickableLink(name, splash.IntroLink)
 	}
-	name := view.ClickableLink(styles.Accent.Copy().Bold(false).Underline(true).Render(splashIntroName), splashIntroLink)
-	return styles.Content.Render(splashIntroPrefix) + name + styles.Content.Render(splashIntroSuffix)
+	return styles.Content.Render(splash.IntroPrefix) + name + styles.Content.Render(splash.IntroSuffix)
 }
 
 func renderSplashCursor(styles view.ThemeStyles, blinkStep int) string {
@@ -96,13 +172,3 @@ func renderSplashCursor(styles view.ThemeStyles, blinkStep int) string {
 	}
 	return " "
 }
-
-func splashContentWidth(boxWidth int) int {
-	if boxWidth <= 0 {
-		return 60
-	}
-	if boxWidth > 4 {
-		return boxWidth - 4
-	}
-	return boxWidth
-}
@@ -0,0 +1,252 @@
+package pages
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/andatoshiki/termfolio/counter"
+	"github.com/andatoshiki/termfolio/view"
+)
+
+const (
+	statsDays        = 30
+	statsWeeks       = 12
+	statsLabelWidth  = 10
+	statsBarWidth    = 24
+	statsHourHeight  = 4
+	statsDateFormat  = "Jan 2"
+	statsHourSpacing = 6
+	statsTopPages    = 8
+)
+
+type statsPage struct {
+	trends     counter.VisitTrends
+	nav        counter.NavigationStats
+	navigation bool
+	err        string
+}
+
+func (p *statsPage) Init(env *Env) tea.Cmd {
+	p.refresh(env)
+	return nil
+}
+
+func (p *statsPage) Update(env *Env, msg tea.Msg) (Page, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "r":
+			p.refresh(env)
+		case "tab":
+			p.navigation = !p.navigation
+		}
+	}
+	return p, nil
+}
+
+func (p *statsPage) View(env *Env) string {
+	if p.navigation {
+		titles := map[string]string{SplashID: "Splash", MenuID: "Menu"}
+		for _, entry := range env.Menu {
+			titles[entry.ID] = entry.Title
+		}
+		return RenderNavigation(env.Styles, p.nav, titles, p.err, env.Help(p.KeyHelp()))
+	}
+	return RenderStats(env.Styles, p.trends, p.err, env.Help(p.KeyHelp()))
+}
+
+func (p *statsPage) Title() string       { return "Stats" }
+func (p *statsPage) Description() string { return "Visits over time" }
+func (p *statsPage) KeyHelp() string {
+	return "tab: visits/navigation • r: refresh • esc/backspace: menu • q: quit"
+}
+
+// Available hides the stats unless the counter is on and the owner shares
+// them.
+func (p *statsPage) Available(env *Env) bool {
+	return env.Counter != nil && env.StatsEnabled
+}
+
+func (p *statsPage) refresh(env *Env) {
+	p.trends = counter.VisitTrends{}
+	p.nav = counter.NavigationStats{}
+	p.err = ""
+	if !p.Available(env) {
+		p.err = "stats are turned off"
+		return
+	}
+	trends, err := env.Counter.VisitTrends(time.Now(), statsDays, statsWeeks)
+	if err != nil {
+		p.err = err.Error()
+		return
+	}
+	nav, err := env.Counter.NavigationStats(time.Now().AddDate(0, 0, -statsDays))
+	if err != nil {
+		p.err = err.Error()
+		return
+	}
+	p.trends = trends
+	p.nav = nav
+}
+
+// RenderStats draws the visit series as sparklines, the split between new
+// and returning visitors as a bar and the visits by hour as columns.
+func RenderStats(styles view.ThemeStyles, trends counter.VisitTrends, errMsg string, help string) string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("━━━ Stats ━━━"))
+	b.WriteString("\n")
+	if errMsg != "" {
+		b.WriteString(styles.Subtle.Render("Unavailable: " + errMsg))
+		b.WriteString("\n")
+		b.WriteString(styles.Help.Render(help))
+		return b.String()
+	}
+
+	series := func(label string, periods []counter.VisitPeriod, value func(counter.VisitPeriod) int) {
+		values := make([]int, len(periods))
+		total := 0
+		for i, period := range periods {
+			values[i] = value(period)
+			total += values[i]
+		}
+		b.WriteString(styles.Subtle.Render(fmt.Sprintf("  %-*s", statsLabelWidth, label)))
+		b.WriteString(styles.Accent.Render(view.Sparkline(values)))
+		b.WriteString(styles.Content.Render(fmt.Sprintf(" %d", total)))
+		b.WriteString("\n")
+	}
+	visits := func(p counter.VisitPeriod) int { return p.Visits }
+	visitors := func(p counter.VisitPeriod) int { return p.Visitors }
+
+	if len(trends.Days) > 0 {
+		b.WriteString(styles.Accent.Render(fmt.Sprintf("Last %d days", len(trends.Days))))
+		b.WriteString(styles.Subtle.Render(fmt.Sprintf("  since %s", trends.Days[0].Start.Format(statsDateFormat))))
+		b.WriteString("\n")
+		series("Visits", trends.Days, visits)
+		// Daily visitors do not add up, a visitor returning on another day
+		// is counted again
+		series("Visitors", trends.Days, visitors)
+		b.WriteString("\n")
+	}
+	if len(trends.Weeks) > 0 {
+		b.WriteString(styles.Accent.Render(fmt.Sprintf("Last %d weeks", len(trends.Weeks))))
+		b.WriteString(styles.Subtle.Render(fmt.Sprintf("  since %s", trends.Weeks[0].Start.Format(statsDateFormat))))
+		b.WriteString("\n")
+		series("Visits", trends.Weeks, visits)
+		series("Visitors", trends.Weeks, visitors)
+		b.WriteString("\n")
+
+		newVisitors, returning := 0, 0
+		for _, week := range trends.Weeks {
+			newVisitors += week.NewVisitors
+			returning += week.ReturningVisitors()
+		}
+		b.WriteString(styles.Accent.Render("New and returning visitors"))
+		b.WriteString(styles.Subtle.Render("  by week"))
+		b.WriteString("\n  ")
+		b.WriteString(styles.Accent.Render(view.HBar(newVisitors, newVisitors+returning, statsBarWidth)))
+		b.WriteString(styles.Content.Render(fmt.Sprintf(" %d new", newVisitors)))
+		b.WriteString(styles.Subtle.Render(fmt.Sprintf(" · %d returning", returning)))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(styles.Accent.Render("Visits by hour"))
+	b.WriteString(styles.Subtle.Render(fmt.Sprintf("  UTC, last %d days", len(trends.Days))))
+	b.WriteString("\n")
+	for _, row := range view.Columns(trends.Hours[:], statsHourHeight, 1) {
+		b.WriteString("  ")
+		b.WriteString(styles.Accent.Render(row))
+		b.WriteString("\n")
+	}
+	var axis strings.Builder
+	for hour := 0; hour < len(trends.Hours); hour += statsHourSpacing {
+		axis.WriteString(fmt.Sprintf("%-*s", statsHourSpacing*2, fmt.Sprintf("%02d", hour)))
+	}
+	b.WriteString(styles.Subtle.Render("  " + strings.TrimRight(axis.String(), " ")))
+	b.WriteString("\n\n")
+
+	b.WriteString(styles.Help.Render(help))
+	return b.String()
+}
+
+// RenderNavigation draws the most viewed pages, how long visits last, how
+// they end and how many visitors go on to each further page. Pages are
+// named by titles, falling back to their IDs.
+func RenderNavigation(styles view.ThemeStyles, nav counter.NavigationStats, titles map[string]string, errMsg string, help string) string {
+	var b strings.Builder
+
+	b.WriteString(styles.Title.Render("━━━ Navigation ━━━"))
+	b.WriteString("\n")
+	if errMsg != "" {
+		b.WriteString(styles.Subtle.Render("Unavailable: " + errMsg))
+		b.WriteString("\n")
+		b.WriteString(styles.Help.Render(help))
+		return b.String()
+	}
+
+	b.WriteString(styles.Accent.Render(fmt.Sprintf("Last %d days", statsDays)))
+	b.WriteString(styles.Subtle.Render(fmt.Sprintf("  %d visits, %s on average", nav.Visits, nav.AvgVisit.Round(time.Second))))
+	b.WriteString("\n\n")
+
+	title := func(page string) string {
+		if t, ok := titles[page]; ok && t != "" {
+			return t
+		}
+		return page
+	}
+
+	b.WriteString(styles.Accent.Render("Top pages"))
+	b.WriteString("\n")
+	if len(nav.Pages) == 0 {
+		b.WriteString(styles.Subtle.Render("  No page views yet"))
+		b.WriteString("\n")
+	}
+	topViews := 0
+	if len(nav.Pages) > 0 {
+		topViews = nav.Pages[0].Views
+	}
+	for i, page := range nav.Pages {
+		if i == statsTopPages {
+			break
+		}
+		b.WriteString(styles.Content.Render(fmt.Sprintf("  %-*s", statsLabelWidth+2, truncate(title(page.Page), statsLabelWidth+1))))
+		b.WriteString(styles.Accent.Render(view.HBar(page.Views, topViews, statsBarWidth/2)))
+		b.WriteString(styles.Content.Render(fmt.Sprintf(" %d", page.Views)))
+		b.WriteString(styles.Subtle.Render(fmt.Sprintf(" · %s each", page.AvgTime.Round(time.Second))))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(styles.Accent.Render("Pages per visit"))
+	b.WriteString(styles.Subtle.Render("  visits that reached each step"))
+	b.WriteString("\n")
+	for i, reached := range nav.Funnel {
+		label := fmt.Sprintf("%d page", i+1)
+		if i > 0 {
+			label += "s"
+		}
+		if i == len(nav.Funnel)-1 {
+			label += "+"
+		}
+		b.WriteString(styles.Subtle.Render(fmt.Sprintf("  %-*s", statsLabelWidth, label)))
+		b.WriteString(styles.Accent.Render(view.HBar(reached, nav.Visits, statsBarWidth/2)))
+		b.WriteString(styles.Content.Render(fmt.Sprintf(" %d", reached)))
+		if i > 0 && nav.Funnel[i-1] > 0 {
+			dropped := nav.Funnel[i-1] - reached
+			b.WriteString(styles.Subtle.Render(fmt.Sprintf(" · %d%% left before", dropped*100/nav.Funnel[i-1])))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(styles.Accent.Render("Visits ended by"))
+	b.WriteString("\n  ")
+	b.WriteString(styles.Content.Render(fmt.Sprintf("%d quit", nav.EndReasons[counter.EndQuit])))
+	b.WriteString(styles.Subtle.Render(fmt.Sprintf(" · %d disconnect", nav.EndReasons[counter.EndDisconnect])))
+	b.WriteString("\n\n")
+
+	b.WriteString(styles.Help.Render(help))
+	return b.String()
+}
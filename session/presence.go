@@ -0,0 +1,82 @@
+package session
+
+import (
+	"context"
+	"time"
+)
+
+// Presence is a snapshot of who is browsing one tenant's portfolio.
+type Presence struct {
+	Online int
+	// Pages counts the sessions on each page ID.
+	Pages map[string]int
+}
+
+// SetPage records the page a session is looking at.
+func (r *Registry) SetPage(id uint64, page string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.sessions[id]
+	if !ok || e.info.Page == page {
+		return
+	}
+	e.info.Page = page
+	r.markChanged(e.info.Tenant)
+}
+
+// Presence counts the visitor sessions of a tenant and the pages they are
+// on.
+func (r *Registry) Presence(tenant string) Presence {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p := Presence{Pages: make(map[string]int)}
+	for _, e := range r.sessions {
+		if e.info.Tenant != tenant || e.info.Admin {
+			continue
+		}
+		p.Online++
+		p.Pages[e.info.Page]++
+	}
+	return p
+}
+
+// RunPresence calls publish for every tenant whose sessions changed, until
+// ctx is cancelled. Changes within interval of each other are coalesced so a
+// burst of connections or page switches costs one publish per tenant.
+func (r *Registry) RunPresence(ctx context.Context, interval time.Duration, publish func(tenant string, p Presence)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.changed:
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		r.mu.Lock()
+		dirty := r.dirty
+		r.dirty = make(map[string]bool)
+		r.mu.Unlock()
+
+		for tenant := range dirty {
+			publish(tenant, r.Presence(tenant))
+		}
+	}
+}
+
+// markChanged flags a tenant for the next publish. r.mu must be held.
+func (r *Registry) markChanged(tenant string) {
+	r.dirty[tenant] = true
+	select {
+	case r.changed <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,68 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPresence(t *testing.T) {
+	r := NewRegistry()
+	a := r.Add(Info{Tenant: "alice", Page: "splash"}, nil)
+	b := r.Add(Info{Tenant: "alice", Page: "splash"}, nil)
+	r.Add(Info{Tenant: "bob", Page: "menu"}, nil)
+	r.Add(Info{Tenant: "alice", Page: "projects", Admin: true}, nil)
+
+	r.SetPage(a, "projects")
+	r.SetPage(b, "projects")
+	r.SetPage(99, "projects")
+
+	got := r.Presence("alice")
+	if got.Online != 2 || got.Pages["projects"] != 2 || got.Pages["splash"] != 0 {
+		t.Fatalf("Presence(alice) = %+v", got)
+	}
+
+	if got := r.Sessions("alice"); len(got) != 3 || got[0].ID != a || got[0].Page != "projects" || !got[2].Admin {
+		t.Fatalf("Sessions(alice) = %+v", got)
+	}
+
+	r.Remove(a)
+	if got := r.Presence("alice"); got.Online != 1 {
+		t.Fatalf("Presence(alice) after Remove = %+v", got)
+	}
+	if got := r.Presence("carol"); got.Online != 0 {
+		t.Fatalf("Presence(carol) = %+v", got)
+	}
+}
+
+func TestRunPresenceCoalescesChanges(t *testing.T) {
+	r := NewRegistry()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	published := make(chan string, 10)
+	go r.RunPresence(ctx, 20*time.Millisecond, func(tenant string, p Presence) {
+		published <- tenant
+	})
+
+	id := r.Add(Info{Tenant: "alice"}, nil)
+	r.SetPage(id, "menu")
+	r.SetPage(id, "about")
+	r.Add(Info{Tenant: "bob"}, nil)
+
+	seen := map[string]int{}
+	timeout := time.After(time.Second)
+	for len(seen) < 2 {
+		select {
+		case tenant := <-published:
+			seen[tenant]++
+		case <-timeout:
+			t.Fatalf("published %v, want alice and bob", seen)
+		}
+	}
+	select {
+	case tenant := <-published:
+		t.Fatalf("extra publish for %s", tenant)
+	case <-time.After(60 * time.Millisecond):
+	}
+}
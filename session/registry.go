@@ -0,0 +1,135 @@
+// Package session tracks the Bubble Tea programs running for connected SSH
+// sessions so server-side events can be pushed into them.
+package session
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// queueSize is how many broadcasts may wait for a busy program before new
+// ones are dropped for it.
+const queueSize = 64
+
+type Info struct {
+	ID         uint64
+	Tenant     string
+	User       string
+	RemoteAddr string
+	StartedAt  time.Time
+	// Page is the ID of the page the visitor is looking at.
+	Page string
+	// Admin sessions belong to the owner and are left out of presence.
+	Admin bool
+}
+
+type Registry struct {
+	mu       sync.Mutex
+	nextID   uint64
+	sessions map[uint64]*entry
+
+	// dirty holds the tenants whose presence changed since the last publish.
+	dirty   map[string]bool
+	changed chan struct{}
+}
+
+type entry struct {
+	info Info
+	// queue feeds the program in broadcast order; it is closed on Remove.
+	queue chan tea.Msg
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		sessions: make(map[uint64]*entry),
+		dirty:    make(map[string]bool),
+		changed:  make(chan struct{}, 1),
+	}
+}
+
+// Add registers a running program and returns the session ID assigned to it.
+func (r *Registry) Add(info Info, program *tea.Program) uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	info.ID = r.nextID
+	if info.StartedAt.IsZero() {
+		info.StartedAt = time.Now()
+	}
+	e := &entry{info: info, queue: make(chan tea.Msg, queueSize)}
+	r.sessions[info.ID] = e
+	r.markChanged(info.Tenant)
+	if program != nil {
+		go deliver(program, e.queue)
+	}
+	return info.ID
+}
+
+// deliver sends queued messages to program one at a time, so they arrive in
+// the order they were broadcast. tea.Program.Send blocks until the event
+// loop reads, or returns at once when the program has exited.
+func deliver(program *tea.Program, queue <-chan tea.Msg) {
+	for msg := range queue {
+		program.Send(msg)
+	}
+}
+
+func (r *Registry) Remove(id uint64) {
+	r.mu.Lock()
+	if e, ok := r.sessions[id]; ok {
+		delete(r.sessions, id)
+		close(e.queue)
+		r.markChanged(e.info.Tenant)
+	}
+	r.mu.Unlock()
+}
+
+// Sessions returns the sessions of a tenant, oldest first.
+func (r *Registry) Sessions(tenant string) []Info {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var infos []Info
+	for _, e := range r.sessions {
+		if e.info.Tenant == tenant {
+			infos = append(infos, e.info)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
+
+func (r *Registry) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.sessions)
+}
+
+// Broadcast sends msg to every registered program without waiting for it.
+func (r *Registry) Broadcast(msg tea.Msg) {
+	r.BroadcastWhere(nil, msg)
+}
+
+// BroadcastWhere sends msg to the programs whose session info matches. A nil
+// match selects every session. A program that has fallen queueSize messages
+// behind misses msg rather than holding up the others.
+func (r *Registry) BroadcastWhere(match func(Info) bool, msg tea.Msg) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.sessions {
+		if match != nil && !match(e.info) {
+			continue
+		}
+		select {
+		case e.queue <- msg:
+		default:
+		}
+	}
+}
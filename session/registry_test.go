@@ -0,0 +1,62 @@
+package session
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// recorder collects the ints it is sent and quits after want of them.
+type recorder struct {
+	want int
+	got  []int
+}
+
+func (m *recorder) Init() tea.Cmd { return nil }
+
+func (m *recorder) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if n, ok := msg.(int); ok {
+		m.got = append(m.got, n)
+		if len(m.got) == m.want {
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+func (m *recorder) View() string { return "" }
+
+func TestBroadcastKeepsOrder(t *testing.T) {
+	const n = 50
+	m := &recorder{want: n}
+	p := tea.NewProgram(m, tea.WithInput(nil), tea.WithOutput(io.Discard), tea.WithoutRenderer(), tea.WithoutSignalHandler())
+
+	r := NewRegistry()
+	id := r.Add(Info{Tenant: "alice"}, p)
+	defer r.Remove(id)
+	for i := range n {
+		r.BroadcastWhere(func(info Info) bool { return info.Tenant == "alice" }, i)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.Run()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		p.Kill()
+		t.Fatalf("program received %d of %d messages", len(m.got), n)
+	}
+	for i, got := range m.got {
+		if got != i {
+			t.Fatalf("message %d = %d, want in broadcast order: %v", i, got, m.got)
+		}
+	}
+}
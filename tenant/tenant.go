@@ -0,0 +1,89 @@
+// Package tenant maps SSH usernames to the portfolio they should see.
+package tenant
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/charmbracelet/ssh"
+
+	"github.com/andatoshiki/termfolio/chat"
+	"github.com/andatoshiki/termfolio/content"
+	"github.com/andatoshiki/termfolio/counter"
+	"github.com/andatoshiki/termfolio/outbox"
+)
+
+// Tenant is one hosted portfolio with its own content and visitor counter.
+// The single-tenant default has an empty User. Outbox is nil when the
+// contact form is disabled and Lobby when the lobby is.
+type Tenant struct {
+	User     string
+	Name     string
+	Content  *content.Watcher
+	Counter  *counter.Store
+	Outbox   *outbox.Outbox
+	Lobby    *chat.Hub
+	Settings *Settings
+	// AdminKeys administer this tenant only, on top of the server-wide
+	// ssh.adminKeys.
+	AdminKeys []ssh.PublicKey
+}
+
+// Settings are the switches the owner flips from the admin TUI. They start
+// from the config and last until the server restarts.
+type Settings struct {
+	// Counting records new visits; when off the count is only shown.
+	Counting atomic.Bool
+	// Stats shows visitor countries on the privacy page.
+	Stats atomic.Bool
+}
+
+// Registry resolves usernames to tenants. With no tenants configured every
+// username maps to the default portfolio.
+type Registry struct {
+	fallback *Tenant
+	tenants  []*Tenant
+	byUser   map[string]*Tenant
+}
+
+func NewRegistry(fallback *Tenant) *Registry {
+	return &Registry{
+		fallback: fallback,
+		byUser:   make(map[string]*Tenant),
+	}
+}
+
+func (r *Registry) Add(t *Tenant) {
+	r.tenants = append(r.tenants, t)
+	r.byUser[normalizeUser(t.User)] = t
+}
+
+// Lookup returns the tenant for an SSH username. It returns false when
+// tenants are configured but none matches, so the caller can show the
+// directory instead.
+func (r *Registry) Lookup(user string) (*Tenant, bool) {
+	if len(r.tenants) == 0 {
+		return r.fallback, r.fallback != nil
+	}
+	t, ok := r.byUser[normalizeUser(user)]
+	return t, ok
+}
+
+// List returns the configured tenants in config order.
+func (r *Registry) List() []*Tenant {
+	return append([]*Tenant(nil), r.tenants...)
+}
+
+// All returns every tenant including the default one, for background jobs
+// that must visit each counter.
+func (r *Registry) All() []*Tenant {
+	all := r.List()
+	if r.fallback != nil {
+		all = append(all, r.fallback)
+	}
+	return all
+}
+
+func normalizeUser(user string) string {
+	return strings.ToLower(strings.TrimSpace(user))
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/andatoshiki/termfolio/config"
+	"github.com/andatoshiki/termfolio/content"
+	"github.com/andatoshiki/termfolio/counter"
+	"github.com/andatoshiki/termfolio/outbox"
+	"github.com/andatoshiki/termfolio/pages"
+	"github.com/andatoshiki/termfolio/tenant"
+)
+
+// buildTenants loads the content and opens the visitor counter of every
+// portfolio served by this process. Without a tenants block the top-level
+// content and counter form a single default portfolio.
+func buildTenants(cfg *config.Config, configPath string, userProvided bool, salts counter.Salts) (*tenant.Registry, error) {
+	if len(cfg.Tenants) == 0 {
+		fallback, err := openTenant(cfg, "", "", cfg.Counter.DBPath, salts, contentLoader(configPath, userProvided, ""))
+		if err != nil {
+			return nil, err
+		}
+		return tenant.NewRegistry(fallback), nil
+	}
+
+	registry := tenant.NewRegistry(nil)
+	for _, tc := range cfg.Tenants {
+		adminKeys, err := parseAdminKeys("adminKeys", tc.AdminKeys)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", tc.User, err)
+		}
+		t, err := openTenant(cfg, tc.User, tc.Name, tc.DBPath, salts, contentLoader(configPath, userProvided, tc.User))
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", tc.User, err)
+		}
+		t.AdminKeys = adminKeys
+		registry.Add(t)
+	}
+	return registry, nil
+}
+
+func openTenant(cfg *config.Config, user string, name string, dbPath string, salts counter.Salts, load content.Loader) (*tenant.Tenant, error) {
+	watcher, err := content.NewWatcher(load, cfg.Content.ReloadInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	var store *counter.Store
+	if cfg.Counter.Enabled {
+		store, err = counter.Open(dbPath, cfg.Stats.GeoLiteDBPath, salts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var messages *outbox.Outbox
+	if store != nil && cfg.Messages.Enabled {
+		messages = outbox.New(store, messageSinks(cfg.Messages, watcher), cfg.Messages.MaxAttempts, cfg.Messages.RetryInterval)
+	}
+
+	if name == "" {
+		name = user
+	}
+	settings := &tenant.Settings{}
+	settings.Counting.Store(true)
+	settings.Stats.Store(cfg.Stats.Enabled)
+	return &tenant.Tenant{
+		User:     user,
+		Name:     name,
+		Content:  watcher,
+		Counter:  store,
+		Outbox:   messages,
+		Settings: settings,
+	}, nil
+}
+
+// rotateSalts checks the IP salt file every interval and hands a rotated
+// salt to the counter of every portfolio.
+func rotateSalts(ctx context.Context, cfg config.CounterConfig, tenants *tenant.Registry, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			salts, err := counter.LoadSalts(cfg.SaltPath, cfg.SaltRotation, now)
+			if err != nil {
+				slog.Error("Failed to rotate IP salt", "err", err)
+				continue
+			}
+			for _, t := range tenants.All() {
+				t.Counter.SetSalts(salts)
+			}
+		}
+	}
+}
+
+// messageSinks builds the configured delivery targets. Mail goes to the
+// profile email unless smtp.to is set. Without sinks messages are only
+// stored.
+func messageSinks(cfg config.MessagesConfig, watcher *content.Watcher) []outbox.Sink {
+	var sinks []outbox.Sink
+	if cfg.SMTP.Host != "" {
+		to := cfg.SMTP.To
+		sinks = append(sinks, &outbox.SMTPSink{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+			To: func() string {
+				if to != "" {
+					return to
+				}
+				return watcher.Current().Profile.Email
+			},
+		})
+	}
+	if cfg.Webhook.URL != "" {
+		sinks = append(sinks, &outbox.WebhookSink{URL: cfg.Webhook.URL, Headers: cfg.Webhook.Headers})
+	}
+	return sinks
+}
+
+// contentLoader re-reads the config on every load so edits to content paths
+// are picked up by hot reload.
+func contentLoader(configPath string, userProvided bool, user string) content.Loader {
+	return func() (*content.Content, []string, error) {
+		current, err := config.Load(configPath, userProvided)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		path := current.Content.Path
+		load := content.Load
+		if user != "" {
+			tc, ok := current.FindTenant(user)
+			if !ok {
+				return nil, nil, fmt.Errorf("tenant %s is no longer configured", user)
+			}
+			// A tenant shows only its own content, never the built-in
+			// portfolio
+			path = tc.Content
+			load = content.LoadWithoutDefaults
+		}
+
+		portfolio, err := load(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := pages.DefaultRegistry().ValidateMenu(portfolio.Menu); err != nil {
+			return nil, nil, fmt.Errorf("invalid content file at %s: %w", path, err)
+		}
+		// Images are watched too, so adding a missing one reloads
+		return portfolio, append([]string{configPath, path, portfolio.Resume}, portfolio.ImagePaths()...), nil
+	}
+}
+
+// logContentWarnings reports what Load tolerated in a portfolio's content.
+func logContentWarnings(name string, portfolio *content.Content) {
+	for _, warning := range portfolio.Warnings {
+		slog.Warn("Content problem", "portfolio", name, "warning", warning)
+	}
+}
+
+func directoryEntries(cfg *config.Config, tenants *tenant.Registry) []pages.DirectoryEntry {
+	var entries []pages.DirectoryEntry
+	for _, t := range tenants.List() {
+		entries = append(entries, pages.DirectoryEntry{
+			Name:    t.Name,
+			Command: cfg.SSH.ConnectCommand(t.User),
+		})
+	}
+	return entries
+}
@@ -0,0 +1,33 @@
+// Package textutil sanitises text that visitors type before it is shown on
+// other people's terminals.
+package textutil
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	xansi "github.com/charmbracelet/x/ansi"
+)
+
+// Clean strips escape sequences, control and bidi override characters, and
+// collapses whitespace to single spaces, so the text cannot move the cursor,
+// restyle or rearrange the terminal it is printed on.
+func Clean(text string) string {
+	text = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r), isBidiControl(r), r == utf8.RuneError:
+			return -1
+		}
+		return r
+	}, xansi.Strip(text))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// isBidiControl reports the embedding, override and isolate characters that
+// reorder the text around them.
+func isBidiControl(r rune) bool {
+	return r >= 0x202a && r <= 0x202e || r >= 0x2066 && r <= 0x2069
+}
@@ -0,0 +1,27 @@
+package textutil
+
+import "testing"
+
+func TestClean(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain", in: "hello there", want: "hello there"},
+		{name: "whitespace", in: "  hi\t\n  all\r\n", want: "hi all"},
+		{name: "escapes", in: "hi\x1b[2J\x1b]8;;http://x\x07there\x1b[31m", want: "hithere"},
+		{name: "controls", in: "a\x00b\x7fc", want: "abc"},
+		{name: "bidi", in: "‮evil⁦ text⁩", want: "evil text"},
+		{name: "invalid utf8", in: "a\xffb", want: "ab"},
+		{name: "emoji", in: "hi 👩‍💻", want: "hi 👩‍💻"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Clean(tc.in); got != tc.want {
+				t.Fatalf("Clean(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
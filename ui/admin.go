@@ -0,0 +1,435 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/andatoshiki/termfolio/counter"
+	"github.com/andatoshiki/termfolio/pages"
+	"github.com/andatoshiki/termfolio/session"
+	"github.com/andatoshiki/termfolio/tenant"
+	"github.com/andatoshiki/termfolio/view"
+)
+
+// AdminPageID is the page reported for owner sessions while the admin TUI,
+// rather than the portfolio, is on screen.
+const AdminPageID = "admin"
+
+const (
+	adminRefreshInterval = time.Second
+	// adminListLimit is how many of the newest rows the lists load.
+	adminListLimit = 50
+)
+
+const (
+	adminTabSessions = iota
+	adminTabAnalytics
+	adminTabGuestbook
+	adminTabMessages
+	adminTabOptOuts
+	adminTabSettings
+)
+
+var adminTabs = []string{"Sessions", "Analytics", "Guestbook", "Messages", "Opt-outs", "Settings"}
+
+const (
+	adminSettingCounting = iota
+	adminSettingStats
+)
+
+type adminTickMsg struct{}
+
+// adminModel is the owner's view of a portfolio: live sessions, analytics,
+// moderation and runtime switches. The lists are reloaded every second.
+type adminModel struct {
+	tenant        *tenant.Tenant
+	sessions      *session.Registry
+	applySettings func()
+	reportPage    func(id string)
+
+	// visitor is the portfolio as visitors see it, built the first time the
+	// owner opens it. visitorPage is shared so copies of the model agree.
+	portfolio   func(reportPage func(id string)) tea.Model
+	visitor     tea.Model
+	visitorPage *string
+	browsing    bool
+
+	tab        int
+	cursor     int
+	confirm    bool
+	notice     string
+	err        string
+	loadErr    string
+	width      int
+	height     int
+	themeIndex int
+	styles     view.ThemeStyles
+
+	now            time.Time
+	live           []session.Info
+	analytics      pages.AdminAnalytics
+	guestbook      []counter.GuestbookEntry
+	guestbookTotal int
+	inbox          []counter.InboxMessage
+	inboxTotal     int
+	optOuts        []counter.OptOut
+	optOutsTotal   int
+}
+
+// NewAdminModel opens the admin TUI for a tenant. applySettings pushes the
+// switches to running visitor sessions after one changes; portfolio builds
+// the visitor view the owner can switch to.
+func NewAdminModel(
+	t *tenant.Tenant,
+	sessions *session.Registry,
+	applySettings func(),
+	portfolio func(reportPage func(id string)) tea.Model,
+	reportPage func(id string),
+) tea.Model {
+	visitorPage := pages.SplashID
+	m := adminModel{
+		tenant:        t,
+		sessions:      sessions,
+		applySettings: applySettings,
+		reportPage:    reportPage,
+		portfolio:     portfolio,
+		visitorPage:   &visitorPage,
+		width:         80,
+		height:        24,
+		styles:        view.NewThemeStyles(view.ThemeAt(0)),
+	}
+	m.refresh()
+	return m
+}
+
+func (m adminModel) Init() tea.Cmd {
+	return adminTick()
+}
+
+func adminTick() tea.Cmd {
+	return tea.Tick(adminRefreshInterval, func(time.Time) tea.Msg {
+		return adminTickMsg{}
+	})
+}
+
+func (m adminModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m.updateVisitor(msg)
+
+	case adminTickMsg:
+		if !m.browsing {
+			m.refresh()
+		}
+		return m, adminTick()
+
+	case tea.KeyMsg:
+		if !m.browsing {
+			return m.updateKey(msg)
+		}
+		if msg.Type == tea.KeyCtrlA {
+			m.browsing = false
+			m.reportPage(AdminPageID)
+			m.refresh()
+			return m, nil
+		}
+	}
+	// Everything else, such as presence and lobby updates, keeps the
+	// portfolio current while it is hidden
+	return m.updateVisitor(msg)
+}
+
+func (m adminModel) updateVisitor(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.visitor == nil {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.visitor, cmd = m.visitor.Update(msg)
+	return m, cmd
+}
+
+func (m adminModel) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if m.confirm {
+		m.confirm = false
+		m.notice = ""
+		if key == "d" {
+			m.deleteSelected()
+			m.refresh()
+		}
+		return m, nil
+	}
+	m.notice = ""
+	m.err = ""
+
+	switch key {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "tab", "right", "l":
+		m.setTab((m.tab + 1) % len(adminTabs))
+	case "shift+tab", "left", "h":
+		m.setTab((m.tab + len(adminTabs) - 1) % len(adminTabs))
+	case "1", "2", "3", "4", "5", "6":
+		tab, _ := strconv.Atoi(key)
+		m.setTab(tab - 1)
+	case "up", "k":
+		m.cursor = clampIndex(m.cursor-1, m.rows())
+	case "down", "j":
+		m.cursor = clampIndex(m.cursor+1, m.rows())
+	case "t", "T":
+		m.themeIndex = view.NextThemeIndex(m.themeIndex)
+		m.styles = view.NewThemeStyles(view.ThemeAt(m.themeIndex))
+	case "v":
+		return m.openPortfolio()
+	case "a":
+		if m.tab == adminTabGuestbook {
+			m.moderate(counter.GuestbookApproved)
+		}
+	case "r":
+		switch m.tab {
+		case adminTabGuestbook:
+			m.moderate(counter.GuestbookRejected)
+		case adminTabMessages:
+			m.retry()
+		}
+	case "d":
+		if target := m.deleteTarget(); target != "" {
+			m.confirm = true
+			m.notice = "Press d again to delete " + target + ", any other key to keep it."
+		}
+	case "enter", " ":
+		if m.tab == adminTabSettings {
+			m.toggle()
+		}
+	}
+	return m, nil
+}
+
+func (m *adminModel) setTab(tab int) {
+	m.tab = tab
+	m.cursor = 0
+}
+
+// openPortfolio switches to the visitor view, building it on first use.
+func (m adminModel) openPortfolio() (tea.Model, tea.Cmd) {
+	m.browsing = true
+	m.reportPage(*m.visitorPage)
+	if m.visitor != nil {
+		return m, nil
+	}
+	page := m.visitorPage
+	m.visitor = m.portfolio(func(id string) {
+		*page = id
+		m.reportPage(id)
+	})
+	var cmd tea.Cmd
+	m.visitor, cmd = m.visitor.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	return m, tea.Batch(m.visitor.Init(), cmd)
+}
+
+func (m *adminModel) moderate(status string) {
+	if m.cursor >= len(m.guestbook) {
+		return
+	}
+	entry := m.guestbook[m.cursor]
+	if err := m.tenant.Counter.SetGuestbookStatus(entry.ID, status); err != nil {
+		m.err = err.Error()
+		return
+	}
+	m.notice = fmt.Sprintf("Entry #%d %s.", entry.ID, status)
+	m.refresh()
+}
+
+func (m *adminModel) retry() {
+	if m.cursor >= len(m.inbox) {
+		return
+	}
+	msg := m.inbox[m.cursor]
+	if msg.State != counter.DeliveryFailed {
+		m.err = fmt.Sprintf("Message #%d has not failed.", msg.ID)
+		return
+	}
+	if m.tenant.Outbox == nil {
+		m.err = "The contact form is disabled, so nothing can deliver it."
+		return
+	}
+	if err := m.tenant.Outbox.Retry(msg.ID); err != nil {
+		m.err = err.Error()
+		return
+	}
+	m.notice = fmt.Sprintf("Message #%d will be delivered again.", msg.ID)
+	m.refresh()
+}
+
+// deleteTarget names what d would delete on the current tab, if anything.
+func (m adminModel) deleteTarget() string {
+	switch {
+	case m.tab == adminTabMessages && m.cursor < len(m.inbox):
+		return fmt.Sprintf("message #%d", m.inbox[m.cursor].ID)
+	case m.tab == adminTabOptOuts && m.cursor < len(m.optOuts):
+		return "the opt-out of " + m.optOuts[m.cursor].ID
+	}
+	return ""
+}
+
+func (m *adminModel) deleteSelected() {
+	target := m.deleteTarget()
+	var err error
+	switch m.tab {
+	case adminTabMessages:
+		err = m.tenant.Counter.DeleteMessage(m.inbox[m.cursor].ID)
+	case adminTabOptOuts:
+		err = m.tenant.Counter.DeleteOptOut(m.optOuts[m.cursor].ID)
+	default:
+		return
+	}
+	if err != nil {
+		m.err = err.Error()
+		return
+	}
+	m.notice = "Deleted " + target + "."
+}
+
+func (m *adminModel) toggle() {
+	settings := m.tenant.Settings
+	switch m.cursor {
+	case adminSettingCounting:
+		settings.Counting.Store(!settings.Counting.Load())
+	case adminSettingStats:
+		settings.Stats.Store(!settings.Stats.Load())
+		m.applySettings()
+		m.refresh()
+	}
+}
+
+// refresh reloads every tab, so switching tabs never shows stale rows.
+func (m *adminModel) refresh() {
+	m.now = time.Now()
+	m.loadErr = ""
+	fail := func(err error) {
+		if err != nil && m.loadErr == "" {
+			m.loadErr = err.Error()
+		}
+	}
+
+	m.live = m.sessions.Sessions(m.tenant.User)
+	presence := m.sessions.Presence(m.tenant.User)
+	a := pages.AdminAnalytics{Online: presence.Online, Pages: presence.Pages}
+
+	if store := m.tenant.Counter; store != nil {
+		var err error
+		a.Visits, err = store.Count()
+		fail(err)
+		m.guestbook, m.guestbookTotal, err = store.GuestbookEntries(counter.GuestbookPending, 0, adminListLimit)
+		fail(err)
+		a.GuestbookPending = m.guestbookTotal
+		_, a.GuestbookApproved, err = store.GuestbookEntries(counter.GuestbookApproved, 0, 0)
+		fail(err)
+		m.inbox, m.inboxTotal, err = store.Inbox(0, adminListLimit)
+		fail(err)
+		a.Messages = m.inboxTotal
+		m.optOuts, m.optOutsTotal, err = store.OptOuts(0, adminListLimit)
+		fail(err)
+		a.OptOuts = m.optOutsTotal
+
+		if m.tenant.Settings.Stats.Load() {
+			stats, err := store.CountryStats()
+			if err != nil {
+				a.CountriesError = err.Error()
+			} else {
+				a.Countries = append([]counter.CountryCount{}, stats.TopCountries...)
+			}
+		}
+	}
+	m.analytics = a
+	if !m.confirm {
+		m.cursor = clampIndex(m.cursor, m.rows())
+	}
+}
+
+// rows is the number of selectable rows on the current tab.
+func (m adminModel) rows() int {
+	switch m.tab {
+	case adminTabSessions:
+		return len(m.live)
+	case adminTabGuestbook:
+		return len(m.guestbook)
+	case adminTabMessages:
+		return len(m.inbox)
+	case adminTabOptOuts:
+		return len(m.optOuts)
+	case adminTabSettings:
+		return 2
+	}
+	return 0
+}
+
+func clampIndex(i int, n int) int {
+	return max(min(i, n-1), 0)
+}
+
+func (m adminModel) View() string {
+	if m.browsing && m.visitor != nil {
+		return m.visitor.View()
+	}
+
+	boxWidth := min(m.width-4, 100)
+	width := boxWidth - 4
+	rows := max(m.height-22, 3)
+	var body string
+	switch {
+	case m.tab == adminTabSessions:
+		body = pages.RenderAdminSessions(m.styles, m.live, m.now, m.cursor, rows)
+	case m.tab == adminTabAnalytics:
+		body = pages.RenderAdminAnalytics(m.styles, m.analytics)
+	case m.tab == adminTabSettings:
+		body = pages.RenderAdminSettings(m.styles, []pages.AdminSetting{
+			{Label: "Count visits", Description: "record new visitors in the counter", On: m.tenant.Settings.Counting.Load()},
+			{Label: "Stats", Description: "show the stats page and visitor countries on the privacy page", On: m.tenant.Settings.Stats.Load()},
+		}, m.cursor)
+	case m.tenant.Counter == nil:
+		body = m.styles.Subtle.Render("The counter is disabled, so there is nothing stored to moderate.")
+	case m.tab == adminTabGuestbook:
+		body = pages.RenderAdminGuestbook(m.styles, m.guestbook, m.guestbookTotal, m.cursor, rows, width)
+	case m.tab == adminTabMessages:
+		body = pages.RenderAdminMessages(m.styles, m.inbox, m.inboxTotal, m.cursor, rows, width)
+	case m.tab == adminTabOptOuts:
+		body = pages.RenderAdminOptOuts(m.styles, m.optOuts, m.optOutsTotal, m.cursor, rows)
+	}
+
+	errMsg := m.err
+	if errMsg == "" {
+		errMsg = m.loadErr
+	}
+	content := pages.RenderAdmin(m.styles, m.tenant.Name, adminTabs, m.tab, body, m.notice, errMsg, m.help())
+
+	boxedContent := lipgloss.NewStyle().
+		Padding(1, 2).
+		Width(boxWidth).
+		Render(content)
+
+	return lipgloss.Place(m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxedContent)
+}
+
+func (m adminModel) help() string {
+	help := "tab/1-6: switch tab • ↑/↓: select • v: view portfolio (ctrl+a returns) • " + themeLabelAt(m.themeIndex) + " • q: quit"
+	switch m.tab {
+	case adminTabGuestbook:
+		help = "a: approve • r: reject • " + help
+	case adminTabMessages:
+		help = "r: retry failed • d: delete • " + help
+	case adminTabOptOuts:
+		help = "d: delete record • " + help
+	case adminTabSettings:
+		help = "enter: toggle • " + help
+	}
+	return help
+}
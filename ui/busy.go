@@ -0,0 +1,24 @@
+package ui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/andatoshiki/termfolio/pages"
+	"github.com/andatoshiki/termfolio/view"
+)
+
+// RenderBusyScreen is shown instead of the TUI to a session refused by the
+// connection limits. It is printed once rather than run as a program, so it
+// costs the server next to nothing, and stays on the visitor's terminal
+// after the connection closes.
+func RenderBusyScreen(reason string, width int) string {
+	if width <= 0 {
+		width = 80
+	}
+	boxWidth := min(width-4, 60)
+	boxed := lipgloss.NewStyle().
+		Padding(1, 2).
+		Width(boxWidth).
+		Render(pages.RenderBusy(view.NewThemeStyles(view.ThemeAt(0)), reason))
+	return lipgloss.PlaceHorizontal(width, lipgloss.Center, boxed)
+}
@@ -0,0 +1,320 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/andatoshiki/termfolio/pages"
+	"github.com/andatoshiki/termfolio/view"
+)
+
+// command is a : command. Complete lists the candidates for its argument.
+type command struct {
+	name        string
+	args        string
+	description string
+	complete    func(m model) []string
+	run         func(m model, args []string) (model, tea.Cmd, error)
+}
+
+// commandRegistry holds the : commands in help order.
+type commandRegistry struct {
+	names    []string
+	commands map[string]command
+}
+
+func newCommandRegistry() *commandRegistry {
+	return &commandRegistry{commands: make(map[string]command)}
+}
+
+// register adds a command. It panics on duplicate names.
+func (r *commandRegistry) register(c command) {
+	if _, exists := r.commands[c.name]; exists {
+		panic(fmt.Sprintf("ui: duplicate command %q", c.name))
+	}
+	r.names = append(r.names, c.name)
+	r.commands[c.name] = c
+}
+
+func (r *commandRegistry) lookup(name string) (command, bool) {
+	c, ok := r.commands[name]
+	return c, ok
+}
+
+func (r *commandRegistry) list() []command {
+	list := make([]command, 0, len(r.names))
+	for _, name := range r.names {
+		list = append(list, r.commands[name])
+	}
+	return list
+}
+
+func defaultCommands() *commandRegistry {
+	r := newCommandRegistry()
+	r.register(command{
+		name:        "goto",
+		args:        "<section>",
+		description: "open a section",
+		complete: func(m model) []string {
+			ids := []string{pages.MenuID}
+			for _, item := range m.env.Menu {
+				ids = append(ids, item.ID)
+			}
+			return ids
+		},
+		run: runGoto,
+	})
+	r.register(command{
+		name:        "theme",
+		args:        "[name]",
+		description: "switch theme, or cycle without a name",
+		complete:    func(model) []string { return view.ThemeSlugs() },
+		run:         runTheme,
+	})
+	r.register(command{
+		name:        "feed",
+		args:        "refresh",
+		description: "fetch the feed again",
+		complete:    func(model) []string { return []string{"refresh"} },
+		run:         runFeed,
+	})
+	r.register(command{
+		name:        "privacy",
+		args:        "on|off",
+		description: "opt in or out of visit tracking",
+		complete:    func(model) []string { return []string{"on", "off"} },
+		run:         runPrivacy,
+	})
+	r.register(command{
+		name:        "help",
+		description: "list commands",
+		run: func(m model, args []string) (model, tea.Cmd, error) {
+			m.command.help = true
+			return m, nil, nil
+		},
+	})
+	r.register(command{
+		name:        "quit",
+		description: "disconnect",
+		run: func(m model, args []string) (model, tea.Cmd, error) {
+			m, cmd := m.quit()
+			return m, cmd, nil
+		},
+	})
+	return r
+}
+
+func runGoto(m model, args []string) (model, tea.Cmd, error) {
+	if len(args) != 1 {
+		return m, nil, fmt.Errorf("usage: goto <section>")
+	}
+	target := strings.ToLower(args[0])
+	if target == pages.MenuID {
+		next, cmd := m.navigate(pages.MenuID)
+		return next, cmd, nil
+	}
+	for _, item := range m.env.Menu {
+		if item.ID == target || strings.ToLower(item.Title) == target {
+			next, cmd := m.navigate(item.ID)
+			return next, cmd, nil
+		}
+	}
+	return m, nil, fmt.Errorf("unknown section %q", args[0])
+}
+
+func runTheme(m model, args []string) (model, tea.Cmd, error) {
+	if len(args) == 0 {
+		m.setTheme(view.NextThemeIndex(m.themeIndex))
+		return m, nil, nil
+	}
+	index, ok := view.FindTheme(strings.Join(args, " "))
+	if !ok {
+		return m, nil, fmt.Errorf("unknown theme %q", strings.Join(args, " "))
+	}
+	m.setTheme(index)
+	return m, nil, nil
+}
+
+func runFeed(m model, args []string) (model, tea.Cmd, error) {
+	if len(args) != 1 || args[0] != "refresh" {
+		return m, nil, fmt.Errorf("usage: feed refresh")
+	}
+	if !m.inMenu(pages.FeedID) {
+		return m, nil, fmt.Errorf("feed is not available")
+	}
+	next, cmd := m.navigate(pages.FeedID)
+	return next, tea.Batch(cmd, pages.RefreshFeed()), nil
+}
+
+func runPrivacy(m model, args []string) (model, tea.Cmd, error) {
+	if len(args) != 1 || (args[0] != "on" && args[0] != "off") {
+		return m, nil, fmt.Errorf("usage: privacy on|off")
+	}
+	if !m.env.TrackingAvailable() {
+		return m, nil, fmt.Errorf("visit tracking is not available")
+	}
+	enabled := args[0] == "on"
+	m.env.SetTracking(enabled)
+	if m.env.TrackingEnabled != enabled {
+		return m, nil, fmt.Errorf("failed to update privacy setting")
+	}
+	if enabled {
+		m.command.message = "visit tracking enabled"
+	} else {
+		m.command.message = "visit tracking disabled"
+	}
+	return m, nil, nil
+}
+
+// commandLine is the : prompt at the bottom of the screen. Errors and
+// messages stay on the bottom line until the next key.
+type commandLine struct {
+	open    bool
+	input   []rune
+	message string
+	err     string
+	help    bool
+}
+
+func (m model) updateCommand(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m.quit()
+	case tea.KeyEsc:
+		m.command = commandLine{}
+	case tea.KeyEnter:
+		input := string(m.command.input)
+		m.command = commandLine{}
+		return m.runCommand(input)
+	case tea.KeyTab:
+		m.completeCommand()
+	case tea.KeyBackspace:
+		if len(m.command.input) == 0 {
+			m.command = commandLine{}
+			return m, nil
+		}
+		m.command.input = m.command.input[:len(m.command.input)-1]
+		m.command.message = ""
+	case tea.KeyCtrlU:
+		m.command.input = nil
+		m.command.message = ""
+	case tea.KeyRunes, tea.KeySpace:
+		m.command.input = append(m.command.input, msg.Runes...)
+		m.command.message = ""
+	}
+	return m, nil
+}
+
+func (m model) runCommand(input string) (tea.Model, tea.Cmd) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return m, nil
+	}
+	name := fields[0]
+	if name == "q" {
+		name = "quit"
+	}
+	c, ok := m.commands.lookup(name)
+	if !ok {
+		m.command.err = fmt.Sprintf("unknown command: %s", fields[0])
+		return m, nil
+	}
+	next, cmd, err := c.run(m, fields[1:])
+	if err != nil {
+		next.command.err = err.Error()
+	}
+	return next, cmd
+}
+
+// completeCommand completes the command name, or its argument after a
+// space, to the longest prefix shared by the candidates, and lists them
+// when more than one remains.
+func (m *model) completeCommand() {
+	input := string(m.command.input)
+	var prefix string
+	var candidates []string
+	if i := strings.IndexByte(input, ' '); i < 0 {
+		prefix = input
+		candidates = m.commands.names
+	} else {
+		c, ok := m.commands.lookup(input[:i])
+		if !ok || c.complete == nil {
+			return
+		}
+		prefix = strings.TrimLeft(input[i+1:], " ")
+		candidates = c.complete(*m)
+	}
+
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, strings.ToLower(prefix)) {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		m.command.message = "no completions"
+		return
+	}
+
+	completed := matches[0]
+	for _, match := range matches[1:] {
+		completed = commonPrefix(completed, match)
+	}
+	if len(matches) == 1 && !strings.Contains(input, " ") {
+		completed += " "
+	}
+	m.command.input = []rune(input[:len(input)-len(prefix)] + completed)
+	m.command.message = ""
+	if len(matches) > 1 {
+		sort.Strings(matches)
+		m.command.message = strings.Join(matches, "  ")
+	}
+}
+
+func commonPrefix(a, b string) string {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return a[:n]
+}
+
+// commandBar renders the bottom lines: the prompt with any completion
+// candidates above it, or the last error or message.
+func (m model) commandBar() []string {
+	styles := m.env.Styles
+	switch {
+	case m.command.open:
+		prompt := styles.Accent.Render(":") + styles.Content.Render(string(m.command.input)) + styles.Accent.Render("▏")
+		if m.command.message != "" {
+			return []string{styles.Subtle.Render(m.command.message), prompt}
+		}
+		return []string{prompt}
+	case m.command.err != "":
+		return []string{styles.Error.Render(m.command.err)}
+	case m.command.message != "":
+		return []string{styles.Subtle.Render(m.command.message)}
+	}
+	return nil
+}
+
+func (m model) commandHelpView() string {
+	styles := m.env.Styles
+	var b strings.Builder
+	b.WriteString(styles.Title.Render("━━━ Commands ━━━"))
+	b.WriteString("\n")
+	for _, c := range m.commands.list() {
+		usage := ":" + c.name
+		if c.args != "" {
+			usage += " " + c.args
+		}
+		b.WriteString(styles.Accent.Render(fmt.Sprintf("%-22s", usage)))
+		b.WriteString(styles.Content.Render(c.description))
+		b.WriteString("\n")
+	}
+	b.WriteString(styles.Help.Render(m.env.Help("tab: complete • any key: close")))
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/andatoshiki/termfolio/pages"
+	"github.com/andatoshiki/termfolio/view"
+)
+
+// directoryModel is the landing page for usernames that match no tenant.
+type directoryModel struct {
+	user       string
+	entries    []pages.DirectoryEntry
+	cursor     int
+	width      int
+	height     int
+	themeIndex int
+	styles     view.ThemeStyles
+}
+
+// NewDirectoryModel lists the hosted portfolios for a visitor who connected
+// as an unknown user.
+func NewDirectoryModel(user string, entries []pages.DirectoryEntry) tea.Model {
+	return directoryModel{
+		user:    user,
+		entries: entries,
+		width:   80,
+		height:  24,
+		styles:  view.NewThemeStyles(view.ThemeAt(0)),
+	}
+}
+
+func (m directoryModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m directoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.entries)-1 {
+				m.cursor++
+			}
+		case "t", "T":
+			m.themeIndex = view.NextThemeIndex(m.themeIndex)
+			m.styles = view.NewThemeStyles(view.ThemeAt(m.themeIndex))
+		}
+	}
+	return m, nil
+}
+
+func (m directoryModel) View() string {
+	boxWidth := min(m.width-4, 70)
+	help := "↑/↓: browse • q: quit • " + themeLabelAt(m.themeIndex)
+	content := pages.RenderDirectory(m.styles, m.entries, m.cursor, m.user, help)
+
+	boxedContent := lipgloss.NewStyle().
+		Padding(1, 2).
+		Width(boxWidth).
+		Render(content)
+
+	return lipgloss.Place(m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxedContent)
+}
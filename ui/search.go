@@ -0,0 +1,95 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/andatoshiki/termfolio/pages"
+)
+
+const searchKeyHelp = "type to search • ↑/↓: select • enter: open • esc: close"
+
+// searchOverlay is the / search over every searchable page in the menu. The
+// entries are collected when it opens and ranked on every keystroke.
+type searchOverlay struct {
+	open    bool
+	query   []rune
+	entries []pages.SearchEntry
+	results []pages.SearchResult
+	cursor  int
+}
+
+func (m model) openSearch() model {
+	var entries []pages.SearchEntry
+	for _, item := range m.env.Menu {
+		searchable, ok := m.pages[item.ID].(pages.Searchable)
+		if !ok {
+			continue
+		}
+		for _, si := range searchable.SearchItems(m.env) {
+			entries = append(entries, pages.SearchEntry{PageID: item.ID, Section: item.Title, SearchItem: si})
+		}
+	}
+	m.search = searchOverlay{open: true, entries: entries}
+	m.search.rank()
+	return m
+}
+
+func (s *searchOverlay) rank() {
+	s.results = pages.RankSearch(string(s.query), s.entries)
+	s.cursor = 0
+}
+
+func (m model) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m.quit()
+	case tea.KeyEsc:
+		m.search = searchOverlay{}
+		return m, nil
+	case tea.KeyEnter:
+		if len(m.search.results) == 0 {
+			return m, nil
+		}
+		result := m.search.results[m.search.cursor]
+		m.search = searchOverlay{}
+		next, cmd := m.navigate(result.PageID)
+		if searchable, ok := m.pages[result.PageID].(pages.Searchable); ok {
+			searchable.Focus(m.env, result.Index)
+		}
+		return next, cmd
+	case tea.KeyUp, tea.KeyCtrlP:
+		if m.search.cursor > 0 {
+			m.search.cursor--
+		}
+	case tea.KeyDown, tea.KeyCtrlN, tea.KeyTab:
+		if m.search.cursor < len(m.search.results)-1 {
+			m.search.cursor++
+		}
+	case tea.KeyBackspace:
+		if len(m.search.query) == 0 {
+			m.search = searchOverlay{}
+			return m, nil
+		}
+		m.search.query = m.search.query[:len(m.search.query)-1]
+		m.search.rank()
+	case tea.KeyCtrlU:
+		m.search.query = nil
+		m.search.rank()
+	case tea.KeyRunes, tea.KeySpace:
+		m.search.query = append(m.search.query, msg.Runes...)
+		m.search.rank()
+	}
+	return m, nil
+}
+
+func (m model) searchView() string {
+	return pages.RenderSearch(
+		m.env.Styles,
+		string(m.search.query),
+		m.search.results,
+		m.search.cursor,
+		m.env.Help(searchKeyHelp),
+		m.env.BoxWidth,
+		m.env.Height,
+	)
+}
@@ -0,0 +1,69 @@
+package view
+
+import "strings"
+
+// sparkBlocks are the eighths of a character cell, from empty to full.
+var sparkBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// Sparkline draws one character per value, scaled so the largest value
+// fills the cell. Zero values are drawn as the lowest bar so the line keeps
+// its length visible; a series of zeros stays flat.
+func Sparkline(values []int) string {
+	peak := maxValue(values)
+	var b strings.Builder
+	for _, v := range values {
+		b.WriteRune(sparkBlocks[max(scaleEighths(v, peak, 1), 1)])
+	}
+	return b.String()
+}
+
+// Columns draws a vertical bar per value, height rows tall, each column
+// width characters wide with a space between columns. Rows are returned
+// top first.
+func Columns(values []int, height int, width int) []string {
+	height = max(height, 1)
+	width = max(width, 1)
+	peak := maxValue(values)
+	rows := make([]string, height)
+	for row := range rows {
+		// Eighths already drawn in the rows below this one
+		below := (height - 1 - row) * 8
+		var b strings.Builder
+		for i, v := range values {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			fill := min(max(scaleEighths(v, peak, height)-below, 0), 8)
+			b.WriteString(strings.Repeat(string(sparkBlocks[fill]), width))
+		}
+		rows[row] = b.String()
+	}
+	return rows
+}
+
+// HBar draws a horizontal bar width cells long, filled in proportion to
+// value out of total.
+func HBar(value int, total int, width int) string {
+	filled := 0
+	if total > 0 {
+		filled = min(max(value*width/total, 0), width)
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// scaleEighths scales v against peak to eighths of rows cells, rounding up
+// so any non-zero value is visible.
+func scaleEighths(v int, peak int, rows int) int {
+	if v <= 0 || peak <= 0 {
+		return 0
+	}
+	return (v*rows*8 + peak - 1) / peak
+}
+
+func maxValue(values []int) int {
+	peak := 0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	return peak
+}
@@ -0,0 +1,53 @@
+package view
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSparkline(t *testing.T) {
+	cases := []struct {
+		name   string
+		values []int
+		want   string
+	}{
+		{name: "empty", values: nil, want: ""},
+		{name: "zeros", values: []int{0, 0, 0}, want: "▁▁▁"},
+		{name: "scaled", values: []int{0, 1, 4, 8}, want: "▁▁▄█"},
+		{name: "small values stay visible", values: []int{1, 100}, want: "▁█"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Sparkline(tc.values); got != tc.want {
+				t.Fatalf("Sparkline(%v) = %q, want %q", tc.values, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestColumns(t *testing.T) {
+	got := Columns([]int{0, 2, 3, 4}, 2, 1)
+	want := []string{
+		"    ▄ █",
+		"  █ █ █",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Columns() = %q, want %q", got, want)
+	}
+}
+
+func TestHBar(t *testing.T) {
+	cases := []struct {
+		value, total, width int
+		want                string
+	}{
+		{value: 1, total: 4, width: 8, want: "██░░░░░░"},
+		{value: 0, total: 0, width: 3, want: "░░░"},
+		{value: 5, total: 4, width: 2, want: "██"},
+	}
+	for _, tc := range cases {
+		if got := HBar(tc.value, tc.total, tc.width); got != tc.want {
+			t.Fatalf("HBar(%d, %d, %d) = %q, want %q", tc.value, tc.total, tc.width, got, tc.want)
+		}
+	}
+}
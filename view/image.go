@@ -0,0 +1,222 @@
+package view
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/muesli/termenv"
+)
+
+// asciiRamp shades pixels from dark to light when the session has no colors.
+const asciiRamp = " .:-=+*#%@"
+
+// maxCachedImages bounds the render cache; it is cleared when full.
+const maxCachedImages = 128
+
+type imageKey struct {
+	path    string
+	width   int
+	height  int
+	profile termenv.Profile
+}
+
+type imageEntry struct {
+	modTime time.Time
+	size    int64
+	art     string
+}
+
+var imageCache = struct {
+	sync.Mutex
+	entries map[imageKey]imageEntry
+}{entries: make(map[imageKey]imageEntry)}
+
+// RenderImage renders the PNG or JPEG file at path as half-block (▀) art at
+// most width cells wide and height lines tall (zero means unbounded), using
+// the colors available in profile. Renders are cached per file, size and
+// profile until the file changes on disk.
+func RenderImage(path string, width, height int, profile termenv.Profile) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open image at %s: %w", path, err)
+	}
+
+	key := imageKey{path: path, width: width, height: height, profile: profile}
+	imageCache.Lock()
+	entry, ok := imageCache.entries[key]
+	imageCache.Unlock()
+	if ok && entry.modTime.Equal(info.ModTime()) && entry.size == info.Size() {
+		return entry.art, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open image at %s: %w", path, err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image at %s: %w", path, err)
+	}
+
+	art := HalfBlocks(img, width, height, profile)
+
+	imageCache.Lock()
+	if len(imageCache.entries) >= maxCachedImages {
+		imageCache.entries = make(map[imageKey]imageEntry)
+	}
+	imageCache.entries[key] = imageEntry{modTime: info.ModTime(), size: info.Size(), art: art}
+	imageCache.Unlock()
+
+	return art, nil
+}
+
+// HalfBlocks renders img as half-block art. Each cell shows two vertically
+// stacked pixels: the upper one as the foreground of ▀ and the lower one as
+// its background.
+func HalfBlocks(img image.Image, width, height int, profile termenv.Profile) string {
+	cols, rows := halfBlockSize(img.Bounds(), width, height)
+	if cols == 0 || rows == 0 {
+		return ""
+	}
+
+	pixels := sampleImage(img, cols, rows*2)
+	lines := make([]string, rows)
+	for row := 0; row < rows; row++ {
+		var b strings.Builder
+		for col := 0; col < cols; col++ {
+			top := pixels[row*2][col]
+			bottom := pixels[row*2+1][col]
+			b.WriteString(halfBlockCell(top, bottom, profile))
+		}
+		lines[row] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// halfBlockSize fits the image into width cells and height lines, keeping
+// its aspect ratio and never scaling it up.
+func halfBlockSize(bounds image.Rectangle, width, height int) (cols, rows int) {
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if srcW <= 0 || srcH <= 0 {
+		return 0, 0
+	}
+
+	cols = srcW
+	if width > 0 && cols > width {
+		cols = width
+	}
+	pixelRows := (srcH*cols + srcW/2) / srcW
+	if pixelRows < 1 {
+		pixelRows = 1
+	}
+	if height > 0 && pixelRows > height*2 {
+		pixelRows = height * 2
+		cols = (srcW*pixelRows + srcH/2) / srcH
+		if cols < 1 {
+			cols = 1
+		}
+	}
+	return cols, (pixelRows + 1) / 2
+}
+
+// sampleImage box-filters img down to cols x rows pixels.
+func sampleImage(img image.Image, cols, rows int) [][]color.NRGBA {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	pixels := make([][]color.NRGBA, rows)
+	for y := 0; y < rows; y++ {
+		pixels[y] = make([]color.NRGBA, cols)
+		y0 := bounds.Min.Y + y*srcH/rows
+		y1 := bounds.Min.Y + (y+1)*srcH/rows
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < cols; x++ {
+			x0 := bounds.Min.X + x*srcW/cols
+			x1 := bounds.Min.X + (x+1)*srcW/cols
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			pixels[y][x] = averageColor(img, x0, y0, x1, y1)
+		}
+	}
+	return pixels
+}
+
+func averageColor(img image.Image, x0, y0, x1, y1 int) color.NRGBA {
+	var r, g, b, a, n uint64
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			pr, pg, pb, pa := img.At(x, y).RGBA()
+			r += uint64(pr)
+			g += uint64(pg)
+			b += uint64(pb)
+			a += uint64(pa)
+			n++
+		}
+	}
+	if n == 0 || a == 0 {
+		return color.NRGBA{}
+	}
+	// RGBA() is alpha-premultiplied; undo it so edges keep their hue.
+	return color.NRGBA{
+		R: uint8(r * 0xffff / a >> 8),
+		G: uint8(g * 0xffff / a >> 8),
+		B: uint8(b * 0xffff / a >> 8),
+		A: uint8(a / n >> 8),
+	}
+}
+
+func halfBlockCell(top, bottom color.NRGBA, profile termenv.Profile) string {
+	topVisible := top.A >= 0x80
+	bottomVisible := bottom.A >= 0x80
+
+	if profile == termenv.Ascii {
+		switch {
+		case topVisible && bottomVisible:
+			return asciiShade((luminance(top) + luminance(bottom)) / 2)
+		case topVisible:
+			return asciiShade(luminance(top))
+		case bottomVisible:
+			return asciiShade(luminance(bottom))
+		default:
+			return " "
+		}
+	}
+
+	switch {
+	case topVisible && bottomVisible:
+		return profile.String("▀").Foreground(profile.FromColor(top)).Background(profile.FromColor(bottom)).String()
+	case topVisible:
+		return profile.String("▀").Foreground(profile.FromColor(top)).String()
+	case bottomVisible:
+		return profile.String("▄").Foreground(profile.FromColor(bottom)).String()
+	default:
+		return " "
+	}
+}
+
+func luminance(c color.NRGBA) float64 {
+	return (0.2126*float64(c.R) + 0.7152*float64(c.G) + 0.0722*float64(c.B)) / 255
+}
+
+func asciiShade(l float64) string {
+	i := int(l*float64(len(asciiRamp)-1) + 0.5)
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(asciiRamp) {
+		i = len(asciiRamp) - 1
+	}
+	return string(asciiRamp[i])
+}
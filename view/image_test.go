@@ -0,0 +1,101 @@
+package view
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	xansi "github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+func TestHalfBlockSize(t *testing.T) {
+	cases := []struct {
+		name          string
+		w, h          int
+		width, height int
+		cols, rows    int
+	}{
+		{name: "native", w: 10, h: 4, width: 0, height: 0, cols: 10, rows: 2},
+		{name: "no upscale", w: 10, h: 4, width: 40, height: 0, cols: 10, rows: 2},
+		{name: "fit width", w: 100, h: 100, width: 20, height: 0, cols: 20, rows: 10},
+		{name: "fit height", w: 100, h: 100, width: 60, height: 5, cols: 10, rows: 5},
+		{name: "odd rows", w: 4, h: 3, width: 0, height: 0, cols: 4, rows: 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cols, rows := halfBlockSize(image.Rect(0, 0, tc.w, tc.h), tc.width, tc.height)
+			if cols != tc.cols || rows != tc.rows {
+				t.Fatalf("halfBlockSize(%dx%d, %d, %d) = %d, %d, want %d, %d", tc.w, tc.h, tc.width, tc.height, cols, rows, tc.cols, tc.rows)
+			}
+		})
+	}
+}
+
+func testImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	img.Set(0, 1, color.NRGBA{B: 255, A: 255})
+	img.Set(1, 0, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	// (1, 1) stays transparent.
+	return img
+}
+
+func TestHalfBlocks(t *testing.T) {
+	img := testImage()
+
+	if got, want := HalfBlocks(img, 0, 0, termenv.Ascii), ".@"; got != want {
+		t.Fatalf("ascii = %q, want %q", got, want)
+	}
+
+	got := HalfBlocks(img, 0, 0, termenv.TrueColor)
+	if !strings.Contains(got, "38;2;255;0;0") || !strings.Contains(got, "48;2;0;0;255") {
+		t.Fatalf("truecolor = %q, want red over blue", got)
+	}
+	if plain := xansi.Strip(got); plain != "▀▀" {
+		t.Fatalf("truecolor cells = %q, want %q", plain, "▀▀")
+	}
+
+	if got := HalfBlocks(img, 0, 0, termenv.ANSI256); strings.Contains(got, "38;2;") {
+		t.Fatalf("ANSI256 = %q, want no truecolor sequences", got)
+	}
+}
+
+func TestRenderImageCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "avatar.png")
+	writePNG := func(img image.Image) {
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if err := png.Encode(f, img); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writePNG(testImage())
+	first, err := RenderImage(path, 10, 0, termenv.Ascii)
+	if err != nil {
+		t.Fatalf("RenderImage() error = %v", err)
+	}
+
+	// A larger image changes the file size, so the cache entry is stale.
+	writePNG(image.NewNRGBA(image.Rect(0, 0, 4, 4)))
+	second, err := RenderImage(path, 10, 0, termenv.Ascii)
+	if err != nil {
+		t.Fatalf("RenderImage() error = %v", err)
+	}
+	if first == second {
+		t.Fatalf("RenderImage() returned the stale render %q", second)
+	}
+
+	if _, err := RenderImage(filepath.Join(t.TempDir(), "missing.png"), 10, 0, termenv.Ascii); err == nil {
+		t.Fatal("RenderImage(missing) error = nil, want error")
+	}
+}
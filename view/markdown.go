@@ -0,0 +1,568 @@
+package view
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	xansi "github.com/charmbracelet/x/ansi"
+)
+
+// The Markdown subset understood here covers what portfolio text needs:
+// ATX headings, bullet and numbered lists, block quotes, fenced code blocks,
+// **bold**, *italic*, `code` spans, [links](url) and <autolinks>.
+
+type mdStyle int
+
+const (
+	mdPlain mdStyle = iota
+	mdBold
+	mdItalic
+	mdBoldItalic
+	mdCode
+	mdHeading
+	mdSubheading
+	mdMarker
+	mdQuote
+)
+
+type mdPiece struct {
+	text  string
+	style mdStyle
+	link  string
+}
+
+type mdLine []mdPiece
+
+type mdBlockKind int
+
+const (
+	mdParagraph mdBlockKind = iota
+	mdHeadingBlock
+	mdBulletItem
+	mdOrderedItem
+	mdQuoteBlock
+	mdCodeBlock
+)
+
+type mdBlock struct {
+	kind   mdBlockKind
+	level  int
+	number int
+	text   string
+	lines  []string
+}
+
+// RenderMarkdown renders src with the theme styles, wrapped to width. A
+// width of zero or less disables wrapping.
+func RenderMarkdown(styles ThemeStyles, src string, width int) string {
+	lines := layoutMarkdown(src, width)
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		var b strings.Builder
+		for _, piece := range mergePieces(line) {
+			rendered := markdownStyle(styles, piece.style, piece.link != "").Render(piece.text)
+			if piece.link != "" {
+				rendered = ClickableLink(rendered, piece.link)
+			}
+			b.WriteString(rendered)
+		}
+		out[i] = b.String()
+	}
+	return strings.Join(out, "\n")
+}
+
+// MarkdownPlain returns the same layout as RenderMarkdown without any
+// styling, so effects that reveal text rune by rune line up with the final
+// styled render.
+func MarkdownPlain(src string, width int) string {
+	lines := layoutMarkdown(src, width)
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		var b strings.Builder
+		for _, piece := range line {
+			b.WriteString(piece.text)
+		}
+		out[i] = b.String()
+	}
+	return strings.Join(out, "\n")
+}
+
+func markdownStyle(styles ThemeStyles, style mdStyle, link bool) lipgloss.Style {
+	if link {
+		s := styles.Accent.Copy().Underline(true)
+		switch style {
+		case mdBold, mdBoldItalic:
+			return s.Bold(true)
+		case mdItalic:
+			return s.Bold(false).Italic(true)
+		}
+		return s
+	}
+	switch style {
+	case mdBold:
+		return styles.Content.Copy().Bold(true)
+	case mdItalic:
+		return styles.Content.Copy().Italic(true)
+	case mdBoldItalic:
+		return styles.Content.Copy().Bold(true).Italic(true)
+	case mdCode:
+		return styles.Tech
+	case mdHeading:
+		return styles.Title.Copy().MarginBottom(0)
+	case mdSubheading:
+		return styles.Accent
+	case mdMarker:
+		return styles.Accent.Copy().Bold(false)
+	case mdQuote:
+		return styles.Subtle.Copy().Italic(true)
+	default:
+		return styles.Content
+	}
+}
+
+func layoutMarkdown(src string, width int) []mdLine {
+	blocks := parseMarkdownBlocks(src)
+	var lines []mdLine
+	for i, block := range blocks {
+		if i > 0 && !(isListBlock(block) && isListBlock(blocks[i-1])) {
+			lines = append(lines, mdLine{})
+		}
+		lines = append(lines, layoutBlock(block, width)...)
+	}
+	return lines
+}
+
+func isListBlock(block mdBlock) bool {
+	return block.kind == mdBulletItem || block.kind == mdOrderedItem
+}
+
+func layoutBlock(block mdBlock, width int) []mdLine {
+	switch block.kind {
+	case mdCodeBlock:
+		lines := make([]mdLine, 0, len(block.lines))
+		for _, line := range block.lines {
+			lines = append(lines, mdLine{{text: "  " + line, style: mdCode}})
+		}
+		return lines
+	case mdHeadingBlock:
+		style := mdHeading
+		if block.level > 1 {
+			style = mdSubheading
+		}
+		return wrapPieces(parseInline(block.text, style), width, nil, nil)
+	case mdBulletItem:
+		return wrapPieces(parseInline(block.text, mdPlain), width,
+			mdLine{{text: "• ", style: mdMarker}}, mdLine{{text: "  "}})
+	case mdOrderedItem:
+		marker := strconv.Itoa(block.number) + ". "
+		return wrapPieces(parseInline(block.text, mdPlain), width,
+			mdLine{{text: marker, style: mdMarker}}, mdLine{{text: strings.Repeat(" ", len(marker))}})
+	case mdQuoteBlock:
+		bar := mdLine{{text: "│ ", style: mdMarker}}
+		return wrapPieces(parseInline(block.text, mdQuote), width, bar, bar)
+	default:
+		return wrapPieces(parseInline(block.text, mdPlain), width, nil, nil)
+	}
+}
+
+func parseMarkdownBlocks(src string) []mdBlock {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	rawLines := strings.Split(src, "\n")
+
+	var blocks []mdBlock
+	var current *mdBlock
+	flush := func() {
+		if current != nil {
+			blocks = append(blocks, *current)
+			current = nil
+		}
+	}
+
+	for i := 0; i < len(rawLines); i++ {
+		line := strings.TrimRight(rawLines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			flush()
+			code := mdBlock{kind: mdCodeBlock}
+			for i++; i < len(rawLines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(rawLines[i]), "```") {
+					break
+				}
+				code.lines = append(code.lines, strings.TrimRight(rawLines[i], " \t"))
+			}
+			blocks = append(blocks, code)
+			continue
+		}
+
+		if trimmed == "" {
+			flush()
+			continue
+		}
+
+		if level, text, ok := headingLine(trimmed); ok {
+			flush()
+			blocks = append(blocks, mdBlock{kind: mdHeadingBlock, level: level, text: text})
+			continue
+		}
+
+		if text, ok := bulletLine(trimmed); ok {
+			flush()
+			current = &mdBlock{kind: mdBulletItem, text: text}
+			continue
+		}
+
+		if number, text, ok := orderedLine(trimmed); ok {
+			flush()
+			current = &mdBlock{kind: mdOrderedItem, number: number, text: text}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, ">") {
+			text := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			if current != nil && current.kind == mdQuoteBlock {
+				current.text += " " + text
+				continue
+			}
+			flush()
+			current = &mdBlock{kind: mdQuoteBlock, text: text}
+			continue
+		}
+
+		if current != nil && current.kind != mdQuoteBlock {
+			// Lazy continuation of a paragraph or list item.
+			current.text += " " + trimmed
+			continue
+		}
+		flush()
+		current = &mdBlock{kind: mdParagraph, text: trimmed}
+	}
+	flush()
+
+	return blocks
+}
+
+func headingLine(line string) (int, string, bool) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level >= len(line) || line[level] != ' ' {
+		return 0, "", false
+	}
+	return level, strings.TrimSpace(strings.TrimRight(line[level:], "#")), true
+}
+
+func bulletLine(line string) (string, bool) {
+	if len(line) < 2 {
+		return "", false
+	}
+	switch line[0] {
+	case '-', '*', '+':
+		if line[1] == ' ' {
+			return strings.TrimSpace(line[2:]), true
+		}
+	}
+	return "", false
+}
+
+func orderedLine(line string) (int, string, bool) {
+	digits := 0
+	for digits < len(line) && line[digits] >= '0' && line[digits] <= '9' {
+		digits++
+	}
+	if digits == 0 || digits > 9 || digits+1 >= len(line) {
+		return 0, "", false
+	}
+	if (line[digits] != '.' && line[digits] != ')') || line[digits+1] != ' ' {
+		return 0, "", false
+	}
+	number, err := strconv.Atoi(line[:digits])
+	if err != nil {
+		return 0, "", false
+	}
+	return number, strings.TrimSpace(line[digits+2:]), true
+}
+
+// parseInline splits text into styled pieces. base is the style of text
+// outside any emphasis.
+func parseInline(text string, base mdStyle) []mdPiece {
+	var pieces []mdPiece
+	var buf strings.Builder
+	bold := false
+	italic := false
+	link := ""
+
+	style := func() mdStyle {
+		switch {
+		case bold && italic:
+			return mdBoldItalic
+		case bold:
+			return mdBold
+		case italic:
+			return mdItalic
+		default:
+			return base
+		}
+	}
+	emit := func() {
+		if buf.Len() == 0 {
+			return
+		}
+		pieces = append(pieces, mdPiece{text: buf.String(), style: style(), link: link})
+		buf.Reset()
+	}
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		ch := runes[i]
+
+		switch {
+		case ch == '\\' && i+1 < len(runes) && unicode.IsPunct(runes[i+1]):
+			buf.WriteRune(runes[i+1])
+			i++
+
+		case ch == '`':
+			end := indexRune(runes, i+1, '`')
+			if end < 0 {
+				buf.WriteRune(ch)
+				continue
+			}
+			emit()
+			pieces = append(pieces, mdPiece{text: string(runes[i+1 : end]), style: mdCode, link: link})
+			i = end
+
+		case hasPrefixRunes(runes[i:], "**") || hasPrefixRunes(runes[i:], "__"):
+			marker := string(runes[i : i+2])
+			if !bold && indexRunes(runes, i+2, marker) < 0 {
+				buf.WriteString(marker)
+				i++
+				continue
+			}
+			emit()
+			bold = !bold
+			i++
+
+		case ch == '*' || ch == '_':
+			if ch == '_' && !emphasisBoundary(runes, i, italic) {
+				buf.WriteRune(ch)
+				continue
+			}
+			if !italic && indexRune(runes, i+1, ch) < 0 {
+				buf.WriteRune(ch)
+				continue
+			}
+			emit()
+			italic = !italic
+
+		case ch == '[' && link == "":
+			label, url, consumed, ok := parseLink(runes[i:])
+			if !ok {
+				buf.WriteRune(ch)
+				continue
+			}
+			emit()
+			for _, piece := range parseInline(label, style()) {
+				piece.link = url
+				pieces = append(pieces, piece)
+			}
+			i += consumed - 1
+
+		case ch == '<' && link == "":
+			end := indexRune(runes, i+1, '>')
+			if end < 0 {
+				buf.WriteRune(ch)
+				continue
+			}
+			target := string(runes[i+1 : end])
+			if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") && !strings.HasPrefix(target, "mailto:") {
+				buf.WriteRune(ch)
+				continue
+			}
+			emit()
+			pieces = append(pieces, mdPiece{text: strings.TrimPrefix(target, "mailto:"), style: style(), link: target})
+			i = end
+
+		default:
+			buf.WriteRune(ch)
+		}
+	}
+	emit()
+
+	return pieces
+}
+
+func indexRune(runes []rune, from int, r rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasPrefixRunes reports whether runes starts with prefix, without copying
+// runes into a string.
+func hasPrefixRunes(runes []rune, prefix string) bool {
+	i := 0
+	for _, r := range prefix {
+		if i >= len(runes) || runes[i] != r {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// indexRunes is the index of the first occurrence of sub in runes from
+// from, or -1.
+func indexRunes(runes []rune, from int, sub string) int {
+	for i := from; i < len(runes); i++ {
+		if hasPrefixRunes(runes[i:], sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// emphasisBoundary keeps underscores inside words (snake_case) literal.
+func emphasisBoundary(runes []rune, i int, closing bool) bool {
+	if closing {
+		return i+1 >= len(runes) || !isWordRune(runes[i+1])
+	}
+	return i == 0 || !isWordRune(runes[i-1])
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// parseLink parses "[label](url)" at the start of runes and reports how many
+// runes it used.
+func parseLink(runes []rune) (label string, url string, consumed int, ok bool) {
+	depth := 0
+	closeLabel := -1
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				closeLabel = i
+			}
+		}
+		if closeLabel >= 0 {
+			break
+		}
+	}
+	if closeLabel < 0 || closeLabel+1 >= len(runes) || runes[closeLabel+1] != '(' {
+		return "", "", 0, false
+	}
+	for j := closeLabel + 2; j < len(runes); j++ {
+		if runes[j] == ')' {
+			url = strings.TrimSpace(string(runes[closeLabel+2 : j]))
+			if url == "" {
+				return "", "", 0, false
+			}
+			return string(runes[1:closeLabel]), url, j + 1, true
+		}
+	}
+	return "", "", 0, false
+}
+
+type mdWord struct {
+	pieces []mdPiece
+	width  int
+}
+
+// wrapPieces wraps pieces to width. first prefixes the first line and rest
+// prefixes continuation lines.
+func wrapPieces(pieces []mdPiece, width int, first mdLine, rest mdLine) []mdLine {
+	words := splitWords(pieces)
+	prefix := first
+	available := width - lineWidth(prefix)
+
+	var lines []mdLine
+	line := append(mdLine(nil), prefix...)
+	used := 0
+	for _, word := range words {
+		if used > 0 && width > 0 && used+1+word.width > available {
+			lines = append(lines, line)
+			prefix = rest
+			available = width - lineWidth(prefix)
+			line = append(mdLine(nil), prefix...)
+			used = 0
+		}
+		if used > 0 {
+			line = append(line, mdPiece{text: " ", style: word.pieces[0].style, link: spaceLink(line, word)})
+			used++
+		}
+		line = append(line, word.pieces...)
+		used += word.width
+	}
+	lines = append(lines, line)
+	return lines
+}
+
+// spaceLink keeps a space inside a link when both neighbours share it.
+func spaceLink(line mdLine, word mdWord) string {
+	if len(line) == 0 {
+		return ""
+	}
+	prev := line[len(line)-1].link
+	if prev != "" && prev == word.pieces[0].link {
+		return prev
+	}
+	return ""
+}
+
+func splitWords(pieces []mdPiece) []mdWord {
+	var words []mdWord
+	var current mdWord
+	flush := func() {
+		if len(current.pieces) > 0 {
+			words = append(words, current)
+		}
+		current = mdWord{}
+	}
+
+	for _, piece := range pieces {
+		fields := strings.Split(piece.text, " ")
+		for i, field := range fields {
+			if i > 0 {
+				flush()
+			}
+			if field == "" {
+				continue
+			}
+			current.pieces = append(current.pieces, mdPiece{text: field, style: piece.style, link: piece.link})
+			current.width += xansi.StringWidth(field)
+		}
+	}
+	flush()
+
+	return words
+}
+
+func lineWidth(line mdLine) int {
+	width := 0
+	for _, piece := range line {
+		width += xansi.StringWidth(piece.text)
+	}
+	return width
+}
+
+func mergePieces(line mdLine) mdLine {
+	var merged mdLine
+	for _, piece := range line {
+		if n := len(merged); n > 0 && merged[n-1].style == piece.style && merged[n-1].link == piece.link {
+			merged[n-1].text += piece.text
+			continue
+		}
+		merged = append(merged, piece)
+	}
+	return merged
+}
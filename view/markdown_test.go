@@ -0,0 +1,56 @@
+package view
+
+import (
+	"strings"
+	"testing"
+
+	xansi "github.com/charmbracelet/x/ansi"
+)
+
+func TestMarkdownPlain(t *testing.T) {
+	cases := []struct {
+		name  string
+		src   string
+		width int
+		want  string
+	}{
+		{name: "emphasis", src: "I'm **bold**, *kiki* and `go`", width: 0, want: "I'm bold, kiki and go"},
+		{name: "link", src: "see [my site](https://example.com).", width: 0, want: "see my site."},
+		{name: "autolink", src: "mail <mailto:me@example.com>", width: 0, want: "mail me@example.com"},
+		{name: "snake case", src: "use read_only_mode", width: 0, want: "use read_only_mode"},
+		{name: "escape", src: `a \*literal\* star`, width: 0, want: "a *literal* star"},
+		{name: "unclosed", src: "2 * 3", width: 0, want: "2 * 3"},
+		{name: "unclosed bold", src: "a ** b", width: 0, want: "a ** b"},
+		{name: "underscore bold", src: "__café__ ok", width: 0, want: "café ok"},
+		{name: "heading", src: "## Hello ##\nbody", width: 0, want: "Hello\n\nbody"},
+		{name: "bullets", src: "- one\n- two\n\nafter", width: 0, want: "• one\n• two\n\nafter"},
+		{name: "ordered", src: "1. first\n2. second", width: 0, want: "1. first\n2. second"},
+		{name: "quote", src: "> quoted\n> text", width: 0, want: "│ quoted text"},
+		{name: "code block", src: "```\nx := 1\n```", width: 0, want: "  x := 1"},
+		{name: "wrap", src: "aaa bbb ccc ddd", width: 7, want: "aaa bbb\nccc ddd"},
+		{name: "wrap list", src: "- aaa bbb ccc", width: 7, want: "• aaa\n  bbb\n  ccc"},
+		{name: "paragraph join", src: "one\ntwo", width: 0, want: "one two"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := MarkdownPlain(tc.src, tc.width)
+			if got != tc.want {
+				t.Fatalf("MarkdownPlain(%q) = %q, want %q", tc.src, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownMatchesPlain(t *testing.T) {
+	src := "# Title\n\nHey, I'm **Anda** -- see [Kiki's Delivery Service](https://example.com/kiki) and `code`.\n\n- a list item that wraps around\n> a quote"
+	styles := NewThemeStyles(ThemeAt(0))
+
+	rendered := RenderMarkdown(styles, src, 20)
+	if got, want := xansi.Strip(rendered), MarkdownPlain(src, 20); got != want {
+		t.Fatalf("stripped render = %q, want %q", got, want)
+	}
+	if !strings.Contains(rendered, "\x1b]8;;https://example.com/kiki\x1b\\") {
+		t.Fatalf("render is missing the hyperlink: %q", rendered)
+	}
+}
@@ -1,6 +1,10 @@
 package view
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 type ThemePalette struct {
 	Name      string
@@ -12,6 +16,7 @@ type ThemePalette struct {
 	Tech      lipgloss.Color
 	Project   lipgloss.Color
 	LogoSnake lipgloss.Color
+	Error     lipgloss.Color
 }
 
 type ThemeStyles struct {
@@ -29,6 +34,7 @@ type ThemeStyles struct {
 	Period      lipgloss.Style
 	LogoBase    lipgloss.Style
 	LogoSnake   lipgloss.Style
+	Error       lipgloss.Style
 }
 
 var themePalettes = []ThemePalette{
@@ -42,6 +48,7 @@ var themePalettes = []ThemePalette{
 		Tech:      lipgloss.Color("#9ECE6A"),
 		Project:   lipgloss.Color("#7AA2F7"),
 		LogoSnake: lipgloss.Color("#9EC5FF"),
+		Error:     lipgloss.Color("#F7768E"),
 	},
 	{
 		Name:      "Nord",
@@ -53,6 +60,7 @@ var themePalettes = []ThemePalette{
 		Tech:      lipgloss.Color("#A3BE8C"),
 		Project:   lipgloss.Color("#EBCB8B"),
 		LogoSnake: lipgloss.Color("#8FBCBB"),
+		Error:     lipgloss.Color("#BF616A"),
 	},
 	{
 		Name:      "Gruvbox",
@@ -64,6 +72,7 @@ var themePalettes = []ThemePalette{
 		Tech:      lipgloss.Color("#B8BB26"),
 		Project:   lipgloss.Color("#FABD2F"),
 		LogoSnake: lipgloss.Color("#FE8019"),
+		Error:     lipgloss.Color("#FB4934"),
 	},
 	{
 		Name:      "Catppuccin Mocha",
@@ -75,6 +84,7 @@ var themePalettes = []ThemePalette{
 		Tech:      lipgloss.Color("#A6E3A1"),
 		Project:   lipgloss.Color("#F9E2AF"),
 		LogoSnake: lipgloss.Color("#F5C2E7"),
+		Error:     lipgloss.Color("#F38BA8"),
 	},
 	{
 		Name:      "Rose Pine",
@@ -86,6 +96,7 @@ var themePalettes = []ThemePalette{
 		Tech:      lipgloss.Color("#31748F"),
 		Project:   lipgloss.Color("#F6C177"),
 		LogoSnake: lipgloss.Color("#EB6F92"),
+		Error:     lipgloss.Color("#EB6F92"),
 	},
 }
 
@@ -109,6 +120,31 @@ func NextThemeIndex(current int) int {
 	return (current + 1) % len(themePalettes)
 }
 
+// ThemeSlug is the theme name as typed in commands, e.g. "tokyo-night".
+func ThemeSlug(p ThemePalette) string {
+	return strings.ReplaceAll(strings.ToLower(p.Name), " ", "-")
+}
+
+// ThemeSlugs lists the slugs of every theme in cycle order.
+func ThemeSlugs() []string {
+	slugs := make([]string, 0, len(themePalettes))
+	for _, p := range themePalettes {
+		slugs = append(slugs, ThemeSlug(p))
+	}
+	return slugs
+}
+
+// FindTheme returns the index of the theme with the given name or slug,
+// ignoring case.
+func FindTheme(name string) (int, bool) {
+	for i, p := range themePalettes {
+		if strings.EqualFold(p.Name, name) || ThemeSlug(p) == strings.ToLower(name) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
 func NewThemeStyles(p ThemePalette) ThemeStyles {
 	return ThemeStyles{
 		Title: lipgloss.NewStyle().
@@ -149,5 +185,7 @@ func NewThemeStyles(p ThemePalette) ThemeStyles {
 		LogoSnake: lipgloss.NewStyle().
 			Foreground(p.LogoSnake).
 			Bold(true),
+		Error: lipgloss.NewStyle().
+			Foreground(p.Error),
 	}
 }
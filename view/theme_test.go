@@ -101,3 +101,25 @@ func assertColor(t *testing.T, got lipgloss.TerminalColor, want lipgloss.Color)
 		t.Fatalf("got %q, want %q", gotColor, want)
 	}
 }
+
+func TestFindTheme(t *testing.T) {
+	cases := []struct {
+		name  string
+		want  int
+		found bool
+	}{
+		{name: "nord", want: 1, found: true},
+		{name: "Tokyo Night", want: 0, found: true},
+		{name: "catppuccin-mocha", want: 3, found: true},
+		{name: "solarized", found: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := FindTheme(tc.name)
+			if ok != tc.found || (ok && got != tc.want) {
+				t.Fatalf("FindTheme(%q) = %d, %v, want %d, %v", tc.name, got, ok, tc.want, tc.found)
+			}
+		})
+	}
+}